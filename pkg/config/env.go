@@ -0,0 +1,214 @@
+// Package config builds GeoGuard engine configurations from external sources.
+//
+// It lets operators tune rules, scores, and thresholds without code changes,
+// e.g. via environment variables in twelve-factor deployments.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/engine"
+	"github.com/gokaycavdar/go-geoguard/pkg/geoip"
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+	"github.com/gokaycavdar/go-geoguard/pkg/storage"
+)
+
+// EngineConfig is a declarative engine configuration: the rules to register
+// (in order) and the engine thresholds.
+type EngineConfig struct {
+	// Rules are registered with AddRule in slice order.
+	Rules []rules.Rule
+
+	// BlockThreshold sets RiskResult.IsBlocked at or above this score (0 = disabled).
+	BlockThreshold int
+
+	// MaxTotalScore caps RiskResult.TotalRiskScore (0 = no cap).
+	MaxTotalScore int
+}
+
+// Options returns the engine options corresponding to this configuration.
+func (c *EngineConfig) Options() []engine.Option {
+	return []engine.Option{
+		engine.WithBlockThreshold(c.BlockThreshold),
+		engine.WithMaxTotalScore(c.MaxTotalScore),
+	}
+}
+
+// Build constructs a GeoGuard engine with this configuration's options and rules.
+// Additional options are applied after the configuration's own options.
+func (c *EngineConfig) Build(geoService geoip.Provider, store storage.HistoryStore, opts ...engine.Option) *engine.GeoGuard {
+	guard := engine.New(geoService, store, append(c.Options(), opts...)...)
+	for _, rule := range c.Rules {
+		guard.AddRule(rule)
+	}
+	return guard
+}
+
+// FromEnv builds an EngineConfig from environment variables.
+//
+// Variables are named PREFIX_NAME (prefix "GEOGUARD" gives GEOGUARD_BLOCK_THRESHOLD).
+// A rule is enabled when its primary variable is set; its score variable is
+// optional and defaults to the typical score in parentheses:
+//
+//	PREFIX_BLOCK_THRESHOLD              Block threshold (>= 0)
+//	PREFIX_MAX_TOTAL_SCORE              Total score cap (>= 0, 0 = no cap)
+//	PREFIX_GEOFENCE_RADIUS_KM           Geofencing radius (> 0), requires:
+//	PREFIX_GEOFENCE_LAT                   center latitude (-90..90)
+//	PREFIX_GEOFENCE_LON                   center longitude (-180..180)
+//	PREFIX_GEOFENCE_SCORE                 score (50)
+//	PREFIX_VELOCITY_MAX_SPEED           Velocity max speed in km/h (> 0)
+//	PREFIX_VELOCITY_SCORE                 score (80)
+//	PREFIX_IPGPS_MAX_DISTANCE_KM        IP-GPS max distance (> 0)
+//	PREFIX_IPGPS_SCORE                    score (40)
+//	PREFIX_DATACENTER_SCORE             Data center detection score
+//	PREFIX_TIMEZONE_SCORE               Timezone mismatch score
+//	PREFIX_FINGERPRINT_SCORE            Device fingerprint change score
+//	PREFIX_COUNTRY_MISMATCH_SCORE       Country change score
+//	PREFIX_OPEN_PROXY_FILE              Proxy blacklist path (see LoadOpenProxyRule)
+//	PREFIX_OPEN_PROXY_SCORE               score (40)
+//
+// Rules are built in the order listed. All invalid variables are reported
+// together in the returned error.
+func FromEnv(prefix string) (*EngineConfig, error) {
+	env := &envReader{prefix: strings.TrimSuffix(prefix, "_")}
+	cfg := &EngineConfig{}
+
+	if threshold, ok := env.int("BLOCK_THRESHOLD", 0, 0, -1); ok {
+		cfg.BlockThreshold = threshold
+	}
+	if maxScore, ok := env.int("MAX_TOTAL_SCORE", 0, 0, -1); ok {
+		cfg.MaxTotalScore = maxScore
+	}
+
+	if radius, ok := env.float("GEOFENCE_RADIUS_KM", 0, 0, -1); ok {
+		lat, latOK := env.float("GEOFENCE_LAT", 0, -90, 90)
+		lon, lonOK := env.float("GEOFENCE_LON", 0, -180, 180)
+		if !latOK || !lonOK {
+			env.errs = append(env.errs, fmt.Errorf("%s and %s are required when %s is set",
+				env.name("GEOFENCE_LAT"), env.name("GEOFENCE_LON"), env.name("GEOFENCE_RADIUS_KM")))
+		}
+		score := env.score("GEOFENCE_SCORE", 50)
+		if radius <= 0 {
+			env.fail("GEOFENCE_RADIUS_KM must be greater than 0")
+		}
+		cfg.Rules = append(cfg.Rules, rules.Geofencing(lat, lon, radius, score))
+	}
+
+	if speed, ok := env.float("VELOCITY_MAX_SPEED", 0, 0, -1); ok {
+		if speed <= 0 {
+			env.fail("VELOCITY_MAX_SPEED must be greater than 0")
+		}
+		cfg.Rules = append(cfg.Rules, rules.Velocity(speed, env.score("VELOCITY_SCORE", 80)))
+	}
+
+	if dist, ok := env.float("IPGPS_MAX_DISTANCE_KM", 0, 0, -1); ok {
+		if dist <= 0 {
+			env.fail("IPGPS_MAX_DISTANCE_KM must be greater than 0")
+		}
+		cfg.Rules = append(cfg.Rules, rules.IPGPS(dist, env.score("IPGPS_SCORE", 40)))
+	}
+
+	if score, ok := env.int("DATACENTER_SCORE", 0, 0, -1); ok {
+		cfg.Rules = append(cfg.Rules, rules.DefaultDataCenterRule(score))
+	}
+	if score, ok := env.int("TIMEZONE_SCORE", 0, 0, -1); ok {
+		cfg.Rules = append(cfg.Rules, rules.Timezone(score))
+	}
+	if score, ok := env.int("FINGERPRINT_SCORE", 0, 0, -1); ok {
+		cfg.Rules = append(cfg.Rules, rules.Fingerprint(score))
+	}
+	if score, ok := env.int("COUNTRY_MISMATCH_SCORE", 0, 0, -1); ok {
+		cfg.Rules = append(cfg.Rules, rules.CountryMismatch(score))
+	}
+
+	if path, ok := env.string("OPEN_PROXY_FILE"); ok {
+		rule, err := rules.LoadOpenProxyRule(path, env.score("OPEN_PROXY_SCORE", 40))
+		if err != nil {
+			env.fail(fmt.Sprintf("OPEN_PROXY_FILE: %v", err))
+		} else {
+			cfg.Rules = append(cfg.Rules, rule)
+		}
+	}
+
+	if len(env.errs) > 0 {
+		return nil, errors.Join(env.errs...)
+	}
+	return cfg, nil
+}
+
+// envReader reads prefixed environment variables and accumulates validation errors.
+type envReader struct {
+	prefix string
+	errs   []error
+}
+
+// name returns the full variable name for a key.
+func (e *envReader) name(key string) string {
+	if e.prefix == "" {
+		return key
+	}
+	return e.prefix + "_" + key
+}
+
+// fail records a validation error, qualifying the message with the prefix.
+func (e *envReader) fail(msg string) {
+	if e.prefix != "" {
+		msg = e.prefix + "_" + msg
+	}
+	e.errs = append(e.errs, errors.New(msg))
+}
+
+// string returns the trimmed value of a variable and whether it is set and non-empty.
+func (e *envReader) string(key string) (string, bool) {
+	value, ok := os.LookupEnv(e.name(key))
+	value = strings.TrimSpace(value)
+	return value, ok && value != ""
+}
+
+// int parses an integer variable within [minValue, maxValue] (maxValue < minValue means unbounded).
+// Returns def and false if the variable is unset or invalid.
+func (e *envReader) int(key string, def, minValue, maxValue int) (int, bool) {
+	raw, ok := e.string(key)
+	if !ok {
+		return def, false
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: invalid integer %q", e.name(key), raw))
+		return def, false
+	}
+	if value < minValue || (maxValue >= minValue && value > maxValue) {
+		e.errs = append(e.errs, fmt.Errorf("%s: %d is out of range", e.name(key), value))
+		return def, false
+	}
+	return value, true
+}
+
+// float parses a float variable within [minValue, maxValue] (maxValue < minValue means unbounded).
+// Returns def and false if the variable is unset or invalid.
+func (e *envReader) float(key string, def, minValue, maxValue float64) (float64, bool) {
+	raw, ok := e.string(key)
+	if !ok {
+		return def, false
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: invalid number %q", e.name(key), raw))
+		return def, false
+	}
+	if value < minValue || (maxValue >= minValue && value > maxValue) {
+		e.errs = append(e.errs, fmt.Errorf("%s: %g is out of range", e.name(key), value))
+		return def, false
+	}
+	return value, true
+}
+
+// score parses an optional non-negative score, returning def when unset.
+func (e *envReader) score(key string, def int) int {
+	value, _ := e.int(key, def, 0, -1)
+	return value
+}
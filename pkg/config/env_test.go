@@ -0,0 +1,103 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("GEOGUARD_BLOCK_THRESHOLD", "100")
+	t.Setenv("GEOGUARD_MAX_TOTAL_SCORE", " 150 ")
+	t.Setenv("GEOGUARD_GEOFENCE_RADIUS_KM", "500")
+	t.Setenv("GEOGUARD_GEOFENCE_LAT", "41.0")
+	t.Setenv("GEOGUARD_GEOFENCE_LON", "29.0")
+	t.Setenv("GEOGUARD_VELOCITY_MAX_SPEED", "900")
+	t.Setenv("GEOGUARD_VELOCITY_SCORE", "60")
+
+	// A trailing underscore in the prefix is ignored
+	cfg, err := FromEnv("GEOGUARD_")
+	if err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+
+	if cfg.BlockThreshold != 100 || cfg.MaxTotalScore != 150 {
+		t.Errorf("thresholds = %d/%d, want 100/150", cfg.BlockThreshold, cfg.MaxTotalScore)
+	}
+	if len(cfg.Rules) != 2 {
+		t.Fatalf("got %d rules, want 2", len(cfg.Rules))
+	}
+	fence, ok := cfg.Rules[0].(*rules.GeofencingRule)
+	if !ok || fence.RiskScore != 50 || fence.RadiusKm != 500 {
+		t.Errorf("rule 0 = %+v, want geofence with radius 500 and default score 50", cfg.Rules[0])
+	}
+	velocity, ok := cfg.Rules[1].(*rules.VelocityRule)
+	if !ok || velocity.RiskScore != 60 || velocity.MaxSpeedKmh != 900 {
+		t.Errorf("rule 1 = %+v, want velocity with max speed 900 and score 60", cfg.Rules[1])
+	}
+}
+
+func TestFromEnvInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want []string // Substrings of the joined error
+	}{
+		{
+			name: "invalid integer",
+			env:  map[string]string{"GG_BLOCK_THRESHOLD": "high"},
+			want: []string{`GG_BLOCK_THRESHOLD: invalid integer "high"`},
+		},
+		{
+			name: "negative threshold",
+			env:  map[string]string{"GG_MAX_TOTAL_SCORE": "-1"},
+			want: []string{"GG_MAX_TOTAL_SCORE: -1 is out of range"},
+		},
+		{
+			name: "latitude out of range",
+			env:  map[string]string{"GG_GEOFENCE_RADIUS_KM": "10", "GG_GEOFENCE_LAT": "91", "GG_GEOFENCE_LON": "0"},
+			want: []string{"GG_GEOFENCE_LAT: 91 is out of range", "GG_GEOFENCE_LAT and GG_GEOFENCE_LON are required"},
+		},
+		{
+			name: "geofence without center",
+			env:  map[string]string{"GG_GEOFENCE_RADIUS_KM": "10"},
+			want: []string{"GG_GEOFENCE_LAT and GG_GEOFENCE_LON are required"},
+		},
+		{
+			name: "zero speed",
+			env:  map[string]string{"GG_VELOCITY_MAX_SPEED": "0"},
+			want: []string{"GG_VELOCITY_MAX_SPEED must be greater than 0"},
+		},
+		{
+			name: "missing proxy file",
+			env:  map[string]string{"GG_OPEN_PROXY_FILE": "/nonexistent/proxies.txt"},
+			want: []string{"GG_OPEN_PROXY_FILE:"},
+		},
+		{
+			name: "errors are aggregated",
+			env: map[string]string{
+				"GG_BLOCK_THRESHOLD":       "x",
+				"GG_IPGPS_MAX_DISTANCE_KM": "-5",
+				"GG_TIMEZONE_SCORE":        "1.5",
+			},
+			want: []string{"GG_BLOCK_THRESHOLD:", "GG_IPGPS_MAX_DISTANCE_KM:", "GG_TIMEZONE_SCORE:"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			cfg, err := FromEnv("GG")
+			if err == nil {
+				t.Fatalf("FromEnv = %+v, want error", cfg)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/engine"
+	"github.com/gokaycavdar/go-geoguard/pkg/geoip"
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+	"github.com/gokaycavdar/go-geoguard/pkg/storage"
+)
+
+// FileConfig is an engine configuration loaded from a JSON or YAML file:
+// GeoIP database paths, engine thresholds, and the ordered rule list.
+//
+// Example (YAML):
+//
+//	geoip:
+//	  city_db: data/GeoLite2-City.mmdb
+//	  asn_db: data/GeoLite2-ASN.mmdb
+//	  cache_size: 10000
+//	block_threshold: 100
+//	level_thresholds: [30, 60, 90]
+//	rules:
+//	  - name: velocity
+//	    params: {MaxSpeedKmh: 900, RiskScore: 80}
+//	  - name: country_policy
+//	    params: {Countries: [DE, FR, NL], Mode: allow}
+//	  - name: city_churn
+//	    params: {Window: 12h}
+//	    weight: 0.5
+//
+// Rule entries use the rules.Registry format (see rules.RuleConfig).
+type FileConfig struct {
+	// GeoIP lists the MaxMind databases. Without CityDB the engine runs in
+	// no-geo mode (see engine.NewWithoutGeo).
+	GeoIP GeoIPConfig `json:"geoip"`
+
+	// BlockThreshold sets RiskResult.IsBlocked at or above this score (0 = disabled).
+	BlockThreshold int `json:"block_threshold"`
+
+	// MaxTotalScore caps RiskResult.TotalRiskScore (0 = no cap).
+	MaxTotalScore int `json:"max_total_score"`
+
+	// MinViolationScore hides smaller violations (see engine.WithMinViolationScore).
+	MinViolationScore int `json:"min_violation_score"`
+
+	// HistoryDepth sets engine.WithHistoryDepth (0 = engine default).
+	HistoryDepth int `json:"history_depth"`
+
+	// LevelThresholds are the medium/high/critical boundaries to pass to
+	// RiskResult.Level for review decisions. The engine itself only blocks;
+	// callers apply these when routing results to review.
+	LevelThresholds []int `json:"level_thresholds"`
+
+	// Rules are registered in order with engine.AddRulesFromConfig.
+	// A tor_exit rule fetches its list in a background goroutine that runs
+	// for the life of the process.
+	Rules []rules.RuleConfig `json:"rules"`
+}
+
+// GeoIPConfig lists the MaxMind database paths for geoip.Service.
+type GeoIPConfig struct {
+	CityDB           string `json:"city_db"`            // GeoLite2/GeoIP2 City database
+	ASNDB            string `json:"asn_db"`             // GeoLite2 ASN or GeoIP2 ISP database (required with CityDB)
+	AnonymousDB      string `json:"anonymous_db"`       // Optional GeoIP2 Anonymous-IP database
+	ConnectionTypeDB string `json:"connection_type_db"` // Optional GeoIP2 Connection-Type database
+	CacheSize        int    `json:"cache_size"`         // Lookup cache entries (0 = no cache)
+}
+
+// LoadConfig reads a JSON or YAML configuration file and builds a fully
+// configured engine backed by an in-memory history store.
+//
+// The GeoIP databases stay open for the life of the process. To choose the
+// store or close the databases, use FromFile and FileConfig.Build instead.
+func LoadConfig(path string) (*engine.GeoGuard, error) {
+	cfg, err := FromFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := cfg.OpenGeoIP()
+	if err != nil {
+		return nil, err
+	}
+	var provider geoip.Provider
+	if service != nil {
+		provider = service
+	}
+
+	guard, err := cfg.Build(provider, storage.NewMemoryStore())
+	if err != nil {
+		if service != nil {
+			service.Close()
+		}
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	return guard, nil
+}
+
+// FromFile reads and validates a configuration file.
+//
+// The format is chosen by extension: .json, or .yaml/.yml. Unknown keys,
+// negative thresholds, unordered level thresholds, and unknown rule names
+// are reported together in the returned error. Rule parameters are
+// validated when the rules are built (see FileConfig.Build).
+func FromFile(path string) (*FileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+	case ".yaml", ".yml":
+		data, err = yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse config %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("config %s: unsupported extension (want .json, .yaml, or .yml)", path)
+	}
+
+	cfg := &FileConfig{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// validate checks thresholds and rule names, reporting all problems together.
+func (c *FileConfig) validate() error {
+	var errs []error
+	for _, setting := range []struct {
+		name  string
+		value int
+	}{
+		{"block_threshold", c.BlockThreshold},
+		{"max_total_score", c.MaxTotalScore},
+		{"min_violation_score", c.MinViolationScore},
+		{"history_depth", c.HistoryDepth},
+		{"geoip.cache_size", c.GeoIP.CacheSize},
+	} {
+		if setting.value < 0 {
+			errs = append(errs, fmt.Errorf("%s: %d must not be negative", setting.name, setting.value))
+		}
+	}
+
+	if len(c.LevelThresholds) > 3 {
+		errs = append(errs, fmt.Errorf("level_thresholds: at most 3 values (medium, high, critical), got %d", len(c.LevelThresholds)))
+	}
+	for i := 1; i < len(c.LevelThresholds); i++ {
+		if c.LevelThresholds[i] <= c.LevelThresholds[i-1] {
+			errs = append(errs, errors.New("level_thresholds: values must be strictly increasing"))
+			break
+		}
+	}
+
+	if c.GeoIP.CityDB != "" && c.GeoIP.ASNDB == "" {
+		errs = append(errs, errors.New("geoip.asn_db is required when geoip.city_db is set"))
+	}
+	if c.GeoIP.CityDB == "" && (c.GeoIP.ASNDB != "" || c.GeoIP.AnonymousDB != "" || c.GeoIP.ConnectionTypeDB != "") {
+		errs = append(errs, errors.New("geoip.city_db is required when other GeoIP databases are set"))
+	}
+
+	known := make(map[string]bool)
+	for _, name := range rules.DefaultRegistry().Names() {
+		known[name] = true
+	}
+	for i, rule := range c.Rules {
+		switch {
+		case rule.Name == "":
+			errs = append(errs, fmt.Errorf("rules[%d]: name is required", i))
+		case !known[rule.Name]:
+			errs = append(errs, fmt.Errorf("rules[%d]: %w: %q", i, rules.ErrUnknownRule, rule.Name))
+		}
+		if rule.Weight < 0 {
+			errs = append(errs, fmt.Errorf("rules[%d]: weight %g must not be negative", i, rule.Weight))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Options returns the engine options corresponding to this configuration.
+func (c *FileConfig) Options() []engine.Option {
+	return []engine.Option{
+		engine.WithBlockThreshold(c.BlockThreshold),
+		engine.WithMaxTotalScore(c.MaxTotalScore),
+		engine.WithMinViolationScore(c.MinViolationScore),
+		engine.WithHistoryDepth(c.HistoryDepth),
+	}
+}
+
+// OpenGeoIP opens the configured databases.
+// Returns nil without error when no city database is configured.
+func (c *FileConfig) OpenGeoIP() (*geoip.Service, error) {
+	if c.GeoIP.CityDB == "" {
+		return nil, nil
+	}
+
+	service, err := geoip.NewService(c.GeoIP.CityDB, c.GeoIP.ASNDB)
+	if err != nil {
+		return nil, err
+	}
+	if c.GeoIP.CacheSize > 0 {
+		service.EnableCache(c.GeoIP.CacheSize, geoip.DefaultCacheTTL)
+	}
+	if c.GeoIP.AnonymousDB != "" {
+		if err := service.OpenAnonymousIPDB(c.GeoIP.AnonymousDB); err != nil {
+			service.Close()
+			return nil, err
+		}
+	}
+	if c.GeoIP.ConnectionTypeDB != "" {
+		if err := service.OpenConnectionTypeDB(c.GeoIP.ConnectionTypeDB); err != nil {
+			service.Close()
+			return nil, err
+		}
+	}
+	return service, nil
+}
+
+// Build constructs a GeoGuard engine with this configuration's options and
+// rules, built with rules.DefaultRegistry. Additional options are applied
+// after the configuration's own options.
+func (c *FileConfig) Build(geoService geoip.Provider, store storage.HistoryStore, opts ...engine.Option) (*engine.GeoGuard, error) {
+	guard := engine.New(geoService, store, append(c.Options(), opts...)...)
+	if err := guard.AddRulesFromConfig(nil, c.Rules); err != nil {
+		return nil, err
+	}
+	return guard, nil
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+)
+
+// writeConfig writes content to a file with the given name in a temp dir.
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestFromFile(t *testing.T) {
+	files := map[string]string{
+		"config.json": `{
+			"block_threshold": 100,
+			"level_thresholds": [30, 60, 90],
+			"rules": [
+				{"name": "velocity", "params": {"MaxSpeedKmh": 900}},
+				{"name": "city_churn", "params": {"window": "12h"}, "weight": 0.5}
+			]
+		}`,
+		"config.yaml": `
+block_threshold: 100
+level_thresholds: [30, 60, 90]
+rules:
+  - name: velocity
+    params: {MaxSpeedKmh: 900}
+  - name: city_churn
+    params: {window: 12h}
+    weight: 0.5
+`,
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := FromFile(writeConfig(t, name, content))
+			if err != nil {
+				t.Fatalf("FromFile: %v", err)
+			}
+			if cfg.BlockThreshold != 100 || len(cfg.LevelThresholds) != 3 {
+				t.Errorf("thresholds = %d/%v, want 100/[30 60 90]", cfg.BlockThreshold, cfg.LevelThresholds)
+			}
+			if len(cfg.Rules) != 2 || cfg.Rules[1].Name != "city_churn" || cfg.Rules[1].Weight != 0.5 {
+				t.Errorf("rules = %+v", cfg.Rules)
+			}
+
+			guard, err := cfg.Build(nil, nil)
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			if got := len(guard.ListRules()); got != 2 {
+				t.Errorf("engine has %d rules, want 2", got)
+			}
+		})
+	}
+}
+
+func TestFromFileInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []string // Substrings of the error
+	}{
+		{
+			name:    "unsupported extension",
+			file:    "config.toml",
+			content: `block_threshold = 100`,
+			want:    []string{"unsupported extension"},
+		},
+		{
+			name:    "unknown field",
+			file:    "config.json",
+			content: `{"block_treshold": 100}`,
+			want:    []string{`unknown field "block_treshold"`},
+		},
+		{
+			name:    "unknown field in yaml",
+			file:    "config.yml",
+			content: "geoip:\n  city: data/GeoLite2-City.mmdb\n",
+			want:    []string{`unknown field "city"`},
+		},
+		{
+			name:    "negative thresholds",
+			file:    "config.json",
+			content: `{"block_threshold": -1, "geoip": {"cache_size": -5}}`,
+			want:    []string{"block_threshold: -1 must not be negative", "geoip.cache_size: -5 must not be negative"},
+		},
+		{
+			name:    "unordered level thresholds",
+			file:    "config.json",
+			content: `{"level_thresholds": [60, 30]}`,
+			want:    []string{"level_thresholds: values must be strictly increasing"},
+		},
+		{
+			name:    "too many level thresholds",
+			file:    "config.json",
+			content: `{"level_thresholds": [10, 20, 30, 40]}`,
+			want:    []string{"level_thresholds: at most 3 values"},
+		},
+		{
+			name:    "city database without asn database",
+			file:    "config.json",
+			content: `{"geoip": {"city_db": "city.mmdb"}}`,
+			want:    []string{"geoip.asn_db is required"},
+		},
+		{
+			name:    "asn database without city database",
+			file:    "config.json",
+			content: `{"geoip": {"asn_db": "asn.mmdb"}}`,
+			want:    []string{"geoip.city_db is required"},
+		},
+		{
+			name:    "errors are aggregated",
+			file:    "config.json",
+			content: `{"max_total_score": -1, "rules": [{"name": "warp_drive"}, {"params": {}}, {"name": "velocity", "weight": -2}]}`,
+			want: []string{
+				"max_total_score: -1 must not be negative",
+				`rules[0]: unknown rule: "warp_drive"`,
+				"rules[1]: name is required",
+				"rules[2]: weight -2 must not be negative",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := FromFile(writeConfig(t, tt.file, tt.content))
+			if err == nil {
+				t.Fatalf("FromFile = %+v, want error", cfg)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFromFileUnknownRuleIs(t *testing.T) {
+	_, err := FromFile(writeConfig(t, "config.json", `{"rules": [{"name": "warp_drive"}]}`))
+	if !errors.Is(err, rules.ErrUnknownRule) {
+		t.Errorf("error = %v, want rules.ErrUnknownRule", err)
+	}
+}
+
+func TestBuildRejectsInvalidParams(t *testing.T) {
+	cfg, err := FromFile(writeConfig(t, "config.json", `{"rules": [{"name": "velocity", "params": {"MaxSpeed": 900}}]}`))
+	if err != nil {
+		t.Fatalf("FromFile: %v", err)
+	}
+	if _, err := cfg.Build(nil, nil); err == nil || !strings.Contains(err.Error(), "MaxSpeed") {
+		t.Errorf("Build error = %v, want one naming MaxSpeed", err)
+	}
+}
+
+func TestLoadConfigWithoutGeoIP(t *testing.T) {
+	guard, err := LoadConfig(writeConfig(t, "config.yaml", "rules:\n  - name: bot_user_agent\n"))
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if got := len(guard.ListRules()); got != 1 {
+		t.Errorf("engine has %d rules, want 1", got)
+	}
+}
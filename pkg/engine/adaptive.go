@@ -0,0 +1,48 @@
+package engine
+
+import "math"
+
+// EnableAdaptiveScoring toggles scoring relative to each user's own baseline.
+//
+// When enabled, TotalRiskScore reports how far a login deviates above the
+// user's usual risk instead of its absolute risk. A user whose logins
+// routinely score 30 (e.g., a corporate VPN tripping DataCenterRule) stops
+// escalating at 30, while a jump to 90 still reports 60. Disabled by
+// default.
+//
+// Math:
+//   - raw = the aggregated, floored, and capped score (WithMaxTotalScore)
+//   - baseline = the user's EWMA before this login
+//     (LoginRecord.SmoothedRiskScore on the last record; 0 for a first login)
+//   - TotalRiskScore = max(0, round(raw - baseline))
+//   - The EWMA keeps averaging raw, never the adjusted score, so the
+//     baseline does not drift toward zero
+//
+// RiskResult.RawRiskScore and RiskResult.BaselineScore report both inputs.
+// The block threshold and RiskResult.Level apply to the adjusted score.
+//
+// Requirements:
+//   - WithEWMA must be set; without it adaptive scoring has no baseline
+//     and is a no-op (Check reports this)
+//   - AnalyzeStateless reads no history and always reports raw scores
+//
+// Limitations:
+// A user who is risky on every login eventually looks normal. An account
+// taken over long ago, or an attacker who logs in repeatedly before acting,
+// raises its own baseline. Keep absolute controls (CountryPolicyRule,
+// BotUserAgentRule) behind a separate check of RawRiskScore where that
+// matters.
+func (g *GeoGuard) EnableAdaptiveScoring(enabled bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.settings.adaptive = enabled
+}
+
+// adaptiveScore returns raw minus the baseline, rounded and floored at 0.
+func adaptiveScore(raw int, baseline float64) int {
+	adjusted := int(math.Round(float64(raw) - baseline))
+	if adjusted < 0 {
+		return 0
+	}
+	return adjusted
+}
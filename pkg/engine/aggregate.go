@@ -0,0 +1,58 @@
+package engine
+
+import "github.com/gokaycavdar/go-geoguard/pkg/models"
+
+// ScoreAggregator combines the scores of triggered rules into TotalRiskScore.
+//
+// It receives one Violation per active rule that returned a non-zero score,
+// in rule insertion order, with RiskScore already weighted (see
+// AddRuleWithWeight). Unlike RiskResult.Violations, the slice also includes
+// violations below WithMinViolationScore, and credits appear as negative
+// scores. Shadow rules and rule errors are never included.
+//
+// The engine still floors the result at 0, caps it at WithMaxTotalScore, and
+// applies WithBlockThreshold and EWMA smoothing to it. Aggregators must be
+// goroutine-safe and must not retain or modify the slice.
+type ScoreAggregator func(violations []models.Violation) int
+
+// SumScores is the default ScoreAggregator: the linear sum of all scores.
+func SumScores(violations []models.Violation) int {
+	total := 0
+	for _, violation := range violations {
+		total += violation.RiskScore
+	}
+	return total
+}
+
+// SetScoreAggregator replaces how rule scores are combined into TotalRiskScore.
+// Passing nil restores the default linear sum (SumScores).
+//
+// A linear sum lets many weak signals add up to a block; custom aggregators
+// can cap, take the maximum, or combine scores probabilistically instead.
+//
+// Example (strongest signal plus a capped bonus for corroboration):
+//
+//	guard.SetScoreAggregator(func(violations []models.Violation) int {
+//	    strongest, rest := 0, 0
+//	    for _, v := range violations {
+//	        if v.RiskScore > strongest {
+//	            strongest, rest = v.RiskScore, rest+strongest
+//	        } else {
+//	            rest += v.RiskScore
+//	        }
+//	    }
+//	    return strongest + min(rest, 20)
+//	})
+func (g *GeoGuard) SetScoreAggregator(aggregator ScoreAggregator) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.settings.scoreAggregator = aggregator
+}
+
+// aggregateScores combines violations with aggregator (nil = SumScores).
+func aggregateScores(aggregator ScoreAggregator, violations []models.Violation) int {
+	if aggregator == nil {
+		return SumScores(violations)
+	}
+	return aggregator(violations)
+}
@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"errors"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+	"github.com/gokaycavdar/go-geoguard/pkg/storage"
+)
+
+// BacktestReport summarizes how a single rule would have behaved over stored history.
+type BacktestReport struct {
+	// RecordsEvaluated is the number of stored records the rule was run against.
+	RecordsEvaluated int
+
+	// TriggerCount is the number of records for which the rule returned a non-zero score.
+	TriggerCount int
+
+	// TriggerRate is TriggerCount / RecordsEvaluated (0 when nothing was evaluated).
+	TriggerRate float64
+
+	// ErrorCount is the number of records for which the rule returned an error.
+	ErrorCount int
+
+	// ScoreDistribution maps each non-zero score to the number of times it was returned.
+	ScoreDistribution map[int]int
+}
+
+// Backtest replays a rule over stored login history to estimate its impact.
+//
+// Each stored record is evaluated as the "current" login, with the same user's
+// preceding record passed as lastRecord. This estimates trigger rate and score
+// distribution before a rule (or a new score) is deployed.
+//
+// Limitations:
+//   - Coordinates are never persisted, so rules implementing EphemeralGeoRule
+//     receive an empty GeoContext and location-based checks will not trigger
+//   - Only records the store retains are replayed (MemoryStore keeps a bounded history)
+//
+// Returns an empty report if the store does not implement storage.IterableStore.
+func Backtest(store storage.HistoryStore, rule rules.Rule) (BacktestReport, error) {
+	report := BacktestReport{
+		ScoreDistribution: make(map[int]int),
+	}
+
+	iterable, ok := store.(storage.IterableStore)
+	if !ok {
+		return report, nil
+	}
+
+	// Track the predecessor of each user's record while iterating chronologically
+	previous := make(map[string]*models.LoginRecord)
+
+	err := iterable.Iterate(func(record *models.LoginRecord) bool {
+		lastRecord := previous[record.UserID]
+		previous[record.UserID] = record
+
+		var score int
+		var ruleErr error
+		if geoRule, ok := rule.(rules.EphemeralGeoRule); ok {
+			score, ruleErr = geoRule.ValidateWithGeo(rules.GeoContext{}, *record, lastRecord)
+		} else {
+			score, ruleErr = rule.Validate(*record, lastRecord)
+		}
+
+		report.RecordsEvaluated++
+		if ruleErr != nil {
+			report.ErrorCount++
+			return true
+		}
+		if score != 0 {
+			report.TriggerCount++
+			report.ScoreDistribution[score]++
+		}
+		return true
+	})
+	if errors.Is(err, storage.ErrUnsupported) {
+		return report, nil
+	}
+	if err != nil {
+		return report, err
+	}
+
+	if report.RecordsEvaluated > 0 {
+		report.TriggerRate = float64(report.TriggerCount) / float64(report.RecordsEvaluated)
+	}
+
+	return report, nil
+}
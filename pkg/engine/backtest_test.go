@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+	"github.com/gokaycavdar/go-geoguard/pkg/storage"
+)
+
+// backtestStore keeps every saved record in insertion order.
+type backtestStore struct {
+	records []models.LoginRecord
+}
+
+func (s *backtestStore) GetLastRecord(userID string) (*models.LoginRecord, error) {
+	return nil, nil
+}
+
+func (s *backtestStore) SaveRecord(record *models.LoginRecord) error {
+	s.records = append(s.records, *record)
+	return nil
+}
+
+func (s *backtestStore) Iterate(fn func(record *models.LoginRecord) bool) error {
+	for i := range s.records {
+		record := s.records[i]
+		if !fn(&record) {
+			break
+		}
+	}
+	return nil
+}
+
+// backtestRule adapts a function to rules.Rule.
+type backtestRule func(input, last *models.LoginRecord) (int, error)
+
+func (r backtestRule) Name() string        { return "Backtest Rule" }
+func (r backtestRule) Description() string { return "test rule" }
+
+func (r backtestRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return r(&input, lastRecord)
+}
+
+func TestBacktest(t *testing.T) {
+	store := &backtestStore{}
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	logins := []struct{ user, country string }{
+		{"alice", "TR"}, {"alice", "DE"}, {"alice", "DE"},
+		{"bob", "US"}, {"bob", "RU"}, {"bob", ""},
+	}
+	for i, login := range logins {
+		record := &models.LoginRecord{UserID: login.user, CountryCode: login.country, Timestamp: start.Add(time.Duration(i) * time.Hour)}
+		if err := store.SaveRecord(record); err != nil {
+			t.Fatalf("SaveRecord: %v", err)
+		}
+	}
+
+	// Scores a country change (50 toward RU) and fails on a missing country
+	var pairs []string
+	rule := backtestRule(func(input, last *models.LoginRecord) (int, error) {
+		previous := "-"
+		if last != nil {
+			previous = last.CountryCode
+		}
+		pairs = append(pairs, input.UserID+":"+previous+">"+input.CountryCode)
+
+		switch {
+		case input.CountryCode == "":
+			return 0, errors.New("no country")
+		case last == nil || last.CountryCode == input.CountryCode:
+			return 0, nil
+		case input.CountryCode == "RU":
+			return 50, nil
+		}
+		return 30, nil
+	})
+
+	report, err := Backtest(store, rule)
+	if err != nil {
+		t.Fatalf("Backtest: %v", err)
+	}
+
+	if report.RecordsEvaluated != 6 || report.TriggerCount != 2 || report.ErrorCount != 1 {
+		t.Errorf("evaluated/triggered/errors = %d/%d/%d, want 6/2/1",
+			report.RecordsEvaluated, report.TriggerCount, report.ErrorCount)
+	}
+	if want := 2.0 / 6; report.TriggerRate != want {
+		t.Errorf("TriggerRate = %v, want %v", report.TriggerRate, want)
+	}
+	if want := map[int]int{30: 1, 50: 1}; !reflect.DeepEqual(report.ScoreDistribution, want) {
+		t.Errorf("ScoreDistribution = %v, want %v", report.ScoreDistribution, want)
+	}
+
+	// Each record is paired with the same user's preceding record
+	sort.Strings(pairs)
+	want := []string{"alice:->TR", "alice:DE>DE", "alice:TR>DE", "bob:->US", "bob:RU>", "bob:US>RU"}
+	sort.Strings(want)
+	if !reflect.DeepEqual(pairs, want) {
+		t.Errorf("pairs = %v, want %v", pairs, want)
+	}
+}
+
+func TestBacktestNonIterableStore(t *testing.T) {
+	rule := backtestRule(func(input, last *models.LoginRecord) (int, error) {
+		return 10, nil
+	})
+
+	// Embedding only the HistoryStore interface hides MemoryStore.Iterate
+	store := struct{ storage.HistoryStore }{storage.NewMemoryStore()}
+	report, err := Backtest(store, rule)
+	if err != nil {
+		t.Fatalf("Backtest: %v", err)
+	}
+	if report.RecordsEvaluated != 0 || report.TriggerRate != 0 || len(report.ScoreDistribution) != 0 {
+		t.Errorf("report = %+v, want empty", report)
+	}
+}
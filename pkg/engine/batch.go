@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// ValidateBatch validates many inputs, e.g. to re-score historical login
+// events for offline analysis or backfills.
+//
+// Results and records are indexed like inputs. When parallel evaluation is
+// enabled (EnableParallelEvaluation), inputs are processed concurrently by a
+// bounded pool of GOMAXPROCS workers; otherwise they run sequentially.
+//
+// Behavior:
+//   - Each input is evaluated exactly as by Validate (observer included)
+//   - The rule set is captured per input, as in Validate
+//   - Records are NOT saved between inputs: every input is compared against
+//     the history already in the store. To replay a user's logins in
+//     sequence, call Validate and save each record in turn instead
+//   - On the first error, remaining inputs are abandoned and the error is
+//     returned (with the failing input's index) without results
+//
+// Throughput: repeated IPs are served from the GeoIP lookup cache when the
+// service has one (geoip.NewServiceWithCache or Service.EnableCache).
+func (g *GeoGuard) ValidateBatch(inputs []Input) ([]*models.RiskResult, []*models.LoginRecord, error) {
+	results := make([]*models.RiskResult, len(inputs))
+	records := make([]*models.LoginRecord, len(inputs))
+
+	if !g.currentSettings().parallel || len(inputs) < 2 {
+		for i, input := range inputs {
+			result, record, err := g.Validate(input)
+			if err != nil {
+				return nil, nil, fmt.Errorf("validate batch: input %d: %w", i, err)
+			}
+			results[i], records[i] = result, record
+		}
+		return results, records, nil
+	}
+
+	// Canceling stops the remaining inputs after the first error
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	indexes := make(chan int)
+
+	workers := min(runtime.GOMAXPROCS(0), len(inputs))
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result, record, err := g.ValidateContext(ctx, inputs[i])
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("validate batch: input %d: %w", i, err)
+						cancel()
+					})
+					continue
+				}
+				results[i], records[i] = result, record
+			}
+		}()
+	}
+
+feed:
+	for i := range inputs {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+	return results, records, nil
+}
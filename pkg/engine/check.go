@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+	"github.com/gokaycavdar/go-geoguard/pkg/storage"
+)
+
+// Check inspects the engine configuration and returns human-readable warnings.
+//
+// It detects silent misconfigurations that would leave users believing they
+// are protected when they are not, for example stateful rules (VelocityRule,
+// CountryMismatchRule, ...) registered with a nil or no-op history store,
+// where they can never trigger.
+//
+// Call it once at startup and log (or fail on) any returned warnings:
+//
+//	for _, warning := range guard.Check() {
+//	    log.Println("geoguard:", warning)
+//	}
+//
+// Returns an empty slice when no problems are found.
+func (g *GeoGuard) Check() []string {
+	warnings := make([]string, 0)
+	settings := g.currentSettings()
+
+	if g.geoService == nil {
+		warnings = append(warnings, "GeoIP service is nil: running in no-geo mode, location rules are skipped")
+	}
+	if settings.adaptive && g.ewmaAlpha == 0 {
+		warnings = append(warnings, "adaptive scoring is enabled without WithEWMA: scores are not adjusted")
+	}
+
+	// Shadow rules read history the same way, so they are checked too
+	var stateful, historyRules, locationRules []string
+	active, _, shadow := g.enabledRules()
+	for _, rule := range append(active, shadow...) {
+		if statefulRule, ok := rule.(rules.StatefulRule); ok && statefulRule.Stateful() {
+			stateful = append(stateful, rule.Name())
+		}
+		if _, ok := rule.(rules.HistoryRule); ok {
+			historyRules = append(historyRules, rule.Name())
+		}
+		if locationRule, ok := rule.(rules.LocationHistoryRule); ok && locationRule.UsesLocationHistory() {
+			locationRules = append(locationRules, rule.Name())
+		}
+	}
+
+	// Capabilities are checked beneath wrappers such as storage.WithMetrics,
+	// which implement every optional interface
+	store := storage.Unwrap(g.historyStore)
+	switch {
+	case store == nil:
+		if settings.firstLogin != nil {
+			warnings = append(warnings, "history store is nil: the first-login policy never applies")
+		}
+		if len(stateful) > 0 {
+			warnings = append(warnings, fmt.Sprintf("history store is nil: stateful rules will never trigger (%s)", strings.Join(stateful, ", ")))
+		}
+	case discardsRecords(g.historyStore):
+		if settings.firstLogin != nil {
+			warnings = append(warnings, "history store retains nothing: the first-login policy applies to every login")
+		}
+		if len(stateful) > 0 {
+			warnings = append(warnings, fmt.Sprintf("history store retains nothing: stateful rules will never trigger (%s)", strings.Join(stateful, ", ")))
+		}
+	default:
+		if _, ok := store.(storage.RecentHistoryStore); !ok && len(historyRules) > 0 {
+			warnings = append(warnings, fmt.Sprintf("history store does not support recent history: rules fall back to the last record only (%s)", strings.Join(historyRules, ", ")))
+		}
+		if _, ok := store.(storage.RecentHistoryStore); !ok && len(locationRules) > 0 {
+			warnings = append(warnings, fmt.Sprintf("history store does not support recent history: previous login locations are unavailable (%s)", strings.Join(locationRules, ", ")))
+		}
+	}
+
+	return warnings
+}
+
+// discardsRecords reports whether store, or any store it wraps, implements
+// storage.DiscardingStore and drops saved records.
+func discardsRecords(store storage.HistoryStore) bool {
+	for store != nil {
+		if discarding, ok := store.(storage.DiscardingStore); ok && discarding.DiscardsRecords() {
+			return true
+		}
+		wrapper, ok := store.(storage.Wrapper)
+		if !ok {
+			return false
+		}
+		store = wrapper.Unwrap()
+	}
+	return false
+}
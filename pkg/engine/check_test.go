@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+	"github.com/gokaycavdar/go-geoguard/pkg/storage"
+)
+
+// discardMetrics ignores every observation.
+type discardMetrics struct{}
+
+func (discardMetrics) ObserveOperation(string, time.Duration, error) {}
+func (discardMetrics) ObserveLookup(bool)                            {}
+
+// forgetfulStore is a third-party no-op store that is not a NopStore.
+type forgetfulStore struct {
+	storage.NopStore
+}
+
+func TestCheckDetectsDiscardingStores(t *testing.T) {
+	stores := map[string]storage.HistoryStore{
+		"nop":         storage.NewNopStore(),
+		"wrapped nop": storage.WithMetrics(storage.NewNopStore(), discardMetrics{}),
+		"custom":      storage.WithMetrics(&forgetfulStore{}, discardMetrics{}),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			guard := New(nil, store)
+			guard.AddShadowRule(rules.Velocity(900, 50))
+
+			warnings := strings.Join(guard.Check(), "\n")
+			if !strings.Contains(warnings, "retains nothing") || !strings.Contains(warnings, "Impossible Travel") {
+				t.Errorf("Check() = %q, want a retains-nothing warning naming the shadow rule", warnings)
+			}
+		})
+	}
+}
+
+func TestCheckAcceptsRetainingStore(t *testing.T) {
+	guard := New(nil, storage.WithMetrics(storage.NewMemoryStore(), discardMetrics{}))
+	guard.AddShadowRule(rules.Velocity(900, 50))
+
+	for _, warning := range guard.Check() {
+		if strings.Contains(warning, "stateful") {
+			t.Errorf("unexpected warning %q", warning)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+	"github.com/gokaycavdar/go-geoguard/pkg/storage"
+)
+
+// TestConcurrentRuleManagement mutates the rule registry and runtime settings
+// while validating. Run with -race; it fails only through the race detector
+// or a panic.
+func TestConcurrentRuleManagement(t *testing.T) {
+	guard := NewWithoutGeo(storage.NewMemoryStore(), WithEWMA(0.3))
+	guard.AddRule(rules.NewBotUserAgentRule(40))
+
+	const (
+		validators = 4
+		iterations = 200
+	)
+
+	var wg sync.WaitGroup
+	for v := 0; v < validators; v++ {
+		wg.Add(1)
+		go func(v int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				input := Input{UserID: fmt.Sprintf("user-%d", v), IPAddress: "81.2.69.142", UserAgent: "curl/8.4.0"}
+				if _, _, err := guard.Validate(input); err != nil {
+					t.Errorf("Validate: %v", err)
+					return
+				}
+				if i%20 == 0 {
+					if _, _, err := guard.ValidateBatch([]Input{input, input}); err != nil {
+						t.Errorf("ValidateBatch: %v", err)
+						return
+					}
+				}
+			}
+		}(v)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			name := fmt.Sprintf("custom-%d", i%3)
+			guard.AddRule(rules.Func(name, "test rule", func(input, last *models.LoginRecord) (int, error) {
+				return 5, nil
+			}))
+			guard.AddShadowRule(rules.NewBotUserAgentRule(10))
+			guard.DisableRule(name)
+			guard.EnableRule(name)
+			guard.RemoveRule(name)
+			guard.RemoveRule("Bot User-Agent")
+			guard.AddRuleWithWeight(rules.NewBotUserAgentRule(40), 0.5)
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			guard.EnableParallelEvaluation(i%2 == 0)
+			guard.EnableAdaptiveScoring(i%3 == 0)
+			guard.SetScoreAggregator(SumScores)
+			guard.SetObserver(func(ObservationEvent) {})
+			guard.SetFirstLoginPolicy(FirstLoginPolicy{Score: 5})
+			guard.AddContextEnricher(func(Input, *rules.GeoContext) {})
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			_ = guard.Check()
+			_ = guard.ListRules()
+			_ = guard.ConfigSnapshot()
+		}
+	}()
+
+	wg.Wait()
+}
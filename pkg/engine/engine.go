@@ -1,6 +1,13 @@
 package engine
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
 	"time"
 
 	"github.com/gokaycavdar/go-geoguard/pkg/geoip"
@@ -15,7 +22,8 @@ import (
 // signals (GPS, timezone) to enable comprehensive security analysis.
 //
 // Backend-Derived (populated by your server):
-//   - IPAddress: From request headers (X-Forwarded-For, CF-Connecting-IP, etc.)
+//   - IPAddress: From request headers (X-Forwarded-For, CF-Connecting-IP, etc.;
+//     see geoip.ClientIPFromHeaders)
 //   - UserAgent: From User-Agent header
 //   - AcceptLanguage: From Accept-Language header
 //
@@ -32,6 +40,8 @@ type Input struct {
 	UserID string
 
 	// IPAddress is the raw IP from the request (ephemeral - never stored)
+	// A trailing port is accepted, so http.Request.RemoteAddr can be passed
+	// directly (see geoip.NormalizeIP)
 	IPAddress string
 
 	// Latitude/Longitude from device GPS (optional, ephemeral)
@@ -48,6 +58,19 @@ type Input struct {
 	// ClientTimezone from browser (e.g., "Europe/Istanbul")
 	// JavaScript: Intl.DateTimeFormat().resolvedOptions().timeZone
 	ClientTimezone string
+
+	// Timestamp of the login event (optional).
+	// Zero uses the engine clock; set it when replaying or importing events.
+	Timestamp time.Time
+
+	// Outcome of the attempt, if already known (e.g., after password check).
+	// Stored on the LoginRecord so later logins can correlate failed attempts.
+	Outcome models.Outcome
+
+	// TrustLevel is the caller's own trust tier for this user (ephemeral).
+	// 0 = unknown/untrusted, higher = more trusted (e.g., verified email, MFA, account age).
+	// Consumed by TrustAdjustmentRule; never persisted.
+	TrustLevel int
 }
 
 // GeoGuard is the main security analysis engine.
@@ -69,25 +92,104 @@ type Input struct {
 //	engine.AddRule(rules.Velocity(900, 80))
 //	result, record, err := engine.Validate(input)
 type GeoGuard struct {
-	geoService   *geoip.Service
+	geoService   geoip.Provider
 	historyStore storage.HistoryStore
-	rules        []rules.Rule
+
+	// Rule registry and runtime settings; guarded by mu so they can be
+	// changed while validating. Each evaluation reads them once.
+	mu          sync.RWMutex
+	rules       []rules.Rule
+	weights     []float64 // Score multiplier per rule, aligned with rules
+	shadowRules []rules.Rule
+	disabled    map[string]bool // Rule names skipped by Validate
+	enrichers   []ContextEnricher
+	settings    runtimeSettings
+
+	// Optional behavior configured via Option
+	historyDepth       int
+	baselineSelector   func(recent []*models.LoginRecord) *models.LoginRecord
+	storeCoordinates   bool
+	coordinateDecimals int
+	severities         map[string]string
+	minViolationScore  int
+	clock              func() time.Time
+	blockThreshold     int
+	maxTotalScore      int
+	ewmaAlpha          float64
+}
+
+// runtimeSettings are the behaviors configurable after New via GeoGuard
+// methods. They are copied under mu, so an evaluation never sees a
+// half-applied change.
+type runtimeSettings struct {
+	parallel        bool              // EnableParallelEvaluation
+	adaptive        bool              // EnableAdaptiveScoring
+	scoreAggregator ScoreAggregator   // SetScoreAggregator
+	observer        Observer          // SetObserver
+	firstLogin      *FirstLoginPolicy // SetFirstLoginPolicy
+}
+
+// currentSettings returns a copy of the runtime settings.
+func (g *GeoGuard) currentSettings() runtimeSettings {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.settings
 }
 
 // New creates a new GeoGuard engine with the specified dependencies.
 //
 // Parameters:
-//   - geoService: GeoIP lookup service (required for location-based rules)
+//   - geoService: GeoIP provider (required for location-based rules); usually
+//     a *geoip.Service, or any geoip.Provider such as a test stub
 //   - store: History storage backend (required for stateful rules)
+//   - opts: Optional behavior (see Option)
 //
 // The engine is the sole owner of the GeoIP service. Rules never access
 // GeoIP directly; they receive derived values via GeoContext.
-func New(geoService *geoip.Service, store storage.HistoryStore) *GeoGuard {
-	return &GeoGuard{
+func New(geoService geoip.Provider, store storage.HistoryStore, opts ...Option) *GeoGuard {
+	// A nil *geoip.Service (e.g., from a failed NewService) means no-geo mode
+	if service, ok := geoService.(*geoip.Service); ok && service == nil {
+		geoService = nil
+	}
+
+	g := &GeoGuard{
 		geoService:   geoService,
 		historyStore: store,
 		rules:        make([]rules.Rule, 0),
+		historyDepth: defaultHistoryDepth,
+		clock:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(g)
 	}
+	return g
+}
+
+// NewWithoutGeo creates an engine that runs in degraded "no-geo" mode.
+//
+// Use it to keep a service up (with reduced detection) when the GeoIP
+// databases cannot be loaded, instead of failing at startup:
+//
+//	geoService, err := geoip.NewService(cityDB, asnDB)
+//	if err != nil {
+//	    guard = engine.NewWithoutGeo(store)
+//	}
+//
+// In no-geo mode:
+//   - Records carry no country, city, ASN, organization, or IP timezone
+//   - EphemeralGeoRules that need GeoIP data are skipped; rules reading only
+//     request-derived context (rules.GeoIPRule returning false, such as
+//     HeaderConsistencyRule, BotUserAgentRule, TrustAdjustmentRule, and
+//     TimestampSanityRule) keep running
+//   - Rules that compare stored fields (FingerprintRule, PlatformSwitchRule,
+//     OpenProxyRule, SubnetReputationRule, ...) keep working; rules that
+//     compare GeoIP-derived fields (CountryMismatchRule, DataCenterRule,
+//     TimezoneRule, ...) find them empty and do not trigger
+//   - RiskResult.GeoUnavailable is set on every result
+//
+// Passing a nil geoService to New has the same effect.
+func NewWithoutGeo(store storage.HistoryStore, opts ...Option) *GeoGuard {
+	return New(nil, store, opts...)
 }
 
 // AddRule adds a security rule to the engine.
@@ -95,8 +197,42 @@ func New(geoService *geoip.Service, store storage.HistoryStore) *GeoGuard {
 //
 // The engine automatically detects if the rule implements EphemeralGeoRule
 // and handles coordinate passing appropriately.
+//
+// AddRule, RemoveRule, DisableRule and the runtime setters (SetObserver,
+// EnableParallelEvaluation, ...) are safe to call while Validate runs. An
+// in-flight evaluation keeps the rules and settings it started with.
 func (g *GeoGuard) AddRule(r rules.Rule) {
+	g.AddRuleWithWeight(r, 1.0)
+}
+
+// AddRuleWithWeight adds a rule whose score is multiplied by weight.
+//
+// Weights tune the relative importance of rules (e.g., VelocityRule versus
+// TimezoneRule) without changing their constructors. The weighted score is
+// rounded to the nearest integer before aggregation, and Violation.RiskScore
+// reports the weighted score that was actually applied. Rules added via
+// AddRule have weight 1.0.
+func (g *GeoGuard) AddRuleWithWeight(r rules.Rule, weight float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 	g.rules = append(g.rules, r)
+	g.weights = append(g.weights, weight)
+}
+
+// AddShadowRule adds a rule in shadow (dry-run) mode.
+//
+// Shadow rules are evaluated on every Validate call exactly like active rules,
+// but their results are reported only in RiskResult.ShadowViolations: they
+// never contribute to TotalRiskScore or the block decision. Use shadow mode
+// to measure a new detection against live traffic before enabling it.
+//
+// Promoting a shadow rule to active:
+//  1. Compare ShadowViolations against outcomes to validate trigger rate and score
+//  2. Replace the AddShadowRule call with AddRule (same rule and parameters)
+func (g *GeoGuard) AddShadowRule(r rules.Rule) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.shadowRules = append(g.shadowRules, r)
 }
 
 // Validate analyzes a login attempt and returns a risk assessment.
@@ -121,84 +257,484 @@ func (g *GeoGuard) AddRule(r rules.Rule) {
 //   - Deciding whether to block based on TotalRiskScore
 //   - Saving the LoginRecord via HistoryStore (for stateful rules)
 func (g *GeoGuard) Validate(input Input) (*models.RiskResult, *models.LoginRecord, error) {
-	// 1. Enrich with GeoIP data (ephemeral - coordinates not stored)
-	geoData, err := g.geoService.GetLocation(input.IPAddress)
+	return g.ValidateContext(context.Background(), input)
+}
+
+// ValidateContext is Validate bounded by ctx.
+//
+// The context is checked between processing stages (GeoIP enrichment,
+// history access, each rule) and passed to stores implementing
+// storage.ContextHistoryStore and rules implementing rules.ContextRule.
+// Once ctx is done, ValidateContext returns ctx.Err() without a result.
+//
+// Limitations:
+//   - GeoIP lookups are local memory-mapped reads and are not interrupted
+//   - Store and rule calls without context support run to completion; the
+//     deadline is enforced at the next stage boundary
+func (g *GeoGuard) ValidateContext(ctx context.Context, input Input) (*models.RiskResult, *models.LoginRecord, error) {
+	return g.observedValidate(ctx, input)
+}
+
+// validate implements ValidateContext without observer notification.
+// In stateless mode the history store is never accessed (see AnalyzeStateless).
+func (g *GeoGuard) validate(ctx context.Context, input Input, stateless bool) (*models.RiskResult, *models.LoginRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// 1-3. Enrich, mask, and build the privacy-safe record
+	now := g.clock()
+	currentRecord, geoData, lookupErr := g.enrich(input, now)
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// 4. Retrieve historical data for stateful rules
+	// latest is the most recent record, read once and reused for the
+	// first-login check and score smoothing
+	var lastRecord, latest *models.LoginRecord
+	var latestRead bool
+	if !stateless {
+		lastRecord, latest, latestRead = g.loadBaseline(ctx, input.UserID)
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// 5. Build ephemeral geo context for rules implementing EphemeralGeoRule
+	// This context exists only during rule evaluation and is garbage collected
+	geoCtx := g.buildGeoContext(geoData, input, lastRecord)
+	geoCtx.EvaluatedAt = now
+
+	// 6. Evaluate all rules and aggregate results
+	result := &models.RiskResult{
+		TotalRiskScore:   0,
+		Violations:       make([]models.Violation, 0),
+		ShadowViolations: make([]models.Violation, 0),
+		EvaluationID:     newEvaluationID(),
+		IsBlocked:        false,
+		RuleErrors:       make([]models.RuleError, 0),
+		GeoUnavailable:   g.geoService == nil,
+		PrivateIP:        geoip.IsPrivateIP(input.IPAddress),
+	}
+	if lookupErr != nil {
+		result.GeoLookupFailed = true
+		result.GeoLookupError = lookupErr.Error()
+	}
+
+	eval := &evaluation{
+		ctx:           ctx,
+		userID:        input.UserID,
+		geoCtx:        geoCtx,
+		currentRecord: currentRecord,
+		lastRecord:    lastRecord,
+		stateless:     stateless,
+		settings:      g.currentSettings(),
+	}
+
+	active, weights, shadow := g.enabledRules()
+
+	// Opt-in only: first logins get the policy's flat score and extra rules
+	if policy := eval.settings.firstLogin; policy != nil && !stateless && lastRecord == nil && isFirstLogin(latest, latestRead) {
+		result.FirstLogin = true
+		active, weights = g.withFirstLoginRules(policy, active, weights)
+	}
+
+	// Opt-in only: previous coordinates cost a history read and GeoIP lookups
+	if !stateless && g.geoService != nil && usesLocationHistory(active, shadow) {
+		g.loadPreviousCoords(eval)
+	}
+
+	// Enrichers see every built-in field, including PreviousIPCoords
+	g.runEnrichers(input, &eval.geoCtx)
+
+	outcomes, err := g.scoreRules(active, eval)
 	if err != nil {
-		geoData = &geoip.GeoData{}
+		return nil, nil, err
+	}
+
+	contributions := make([]models.Violation, 0, len(active))
+	for i, rule := range active {
+		if outcomes[i].err != nil {
+			result.RuleErrors = append(result.RuleErrors, models.RuleError{
+				RuleName: rule.Name(),
+				Skipped:  errors.Is(outcomes[i].err, rules.ErrMissingData),
+				Message:  outcomes[i].err.Error(),
+			})
+			continue
+		}
+		score := int(math.Round(float64(outcomes[i].score) * weights[i]))
+
+		// Negative scores are allowed: they act as credits (e.g., TrustAdjustmentRule)
+		if score != 0 {
+			violation := g.newViolation(rule, score, eval)
+			contributions = append(contributions, violation)
+
+			// Low-impact violations still count toward the total but are not listed
+			if abs(score) >= g.minViolationScore {
+				result.Violations = append(result.Violations, violation)
+			}
+		}
 	}
+	result.TotalRiskScore = aggregateScores(eval.settings.scoreAggregator, contributions)
 
-	asn, orgName, err := g.geoService.GetASN(input.IPAddress)
+	// Shadow rules are evaluated and reported but never affect the total
+	shadowOutcomes, err := g.scoreRules(shadow, eval)
 	if err != nil {
-		asn = 0
-		orgName = ""
+		return nil, nil, err
+	}
+
+	for i, rule := range shadow {
+		if shadowOutcomes[i].err == nil && shadowOutcomes[i].score != 0 {
+			result.ShadowViolations = append(result.ShadowViolations, g.newViolation(rule, shadowOutcomes[i].score, eval))
+		}
+	}
+
+	// Score floor: credits can offset risk but never produce a negative total
+	if result.TotalRiskScore < 0 {
+		result.TotalRiskScore = 0
+	}
+
+	// Optional cap: violations keep their full contributions for explainability
+	if g.maxTotalScore > 0 && result.TotalRiskScore > g.maxTotalScore {
+		result.TotalRiskScore = g.maxTotalScore
+	}
+
+	// Opt-in only: carry the smoothed score forward on the persisted record
+	if g.ewmaAlpha > 0 && !stateless {
+		smoothed, baseline := g.smoothScore(latest, result.TotalRiskScore)
+		result.SmoothedScore = smoothed
+		currentRecord.SmoothedRiskScore = smoothed
+
+		// The average above uses the raw score; only the reported total is adjusted
+		if eval.settings.adaptive {
+			result.RawRiskScore = result.TotalRiskScore
+			result.BaselineScore = baseline
+			result.TotalRiskScore = adaptiveScore(result.TotalRiskScore, baseline)
+		}
+	}
+
+	if g.blockThreshold > 0 && result.TotalRiskScore >= g.blockThreshold {
+		result.IsBlocked = true
+	}
+
+	// geoCtx goes out of scope here - coordinates are garbage collected
+	// Only privacy-safe currentRecord is returned
+
+	return result, &currentRecord, nil
+}
+
+// smoothScore folds score into the EWMA stored on last, the user's most
+// recent record, and returns the new average along with the previous one
+// (0 for a first login). last is not the baseline selector's pick because
+// the average must continue from the most recent login.
+func (g *GeoGuard) smoothScore(last *models.LoginRecord, score int) (smoothed, previous float64) {
+	current := float64(score)
+
+	// Cold start: the first login's average is its own score
+	if last == nil {
+		return current, 0
+	}
+
+	return g.ewmaAlpha*current + (1-g.ewmaAlpha)*last.SmoothedRiskScore, last.SmoothedRiskScore
+}
+
+// Enrich builds the privacy-safe LoginRecord for a login without evaluating rules.
+//
+// It performs the record-building half of Validate: GeoIP lookup, IP masking,
+// and fingerprint hashing. History is neither read nor written, so Enrich is
+// cheaper than Validate when an application logs every request but only runs
+// full risk analysis selectively.
+//
+// GeoIP failures degrade to empty location fields, exactly as in Validate;
+// the error return is reserved for future enrichment steps.
+func (g *GeoGuard) Enrich(input Input) (*models.LoginRecord, error) {
+	record, _, _ := g.enrich(input, g.clock())
+	return &record, nil
+}
+
+// enrich builds the LoginRecord and returns the ephemeral GeoIP data alongside it.
+// The GeoData carries IP coordinates for the geo context and must not be persisted.
+// The error is the failed location lookup, if any; private IPs and no-geo
+// mode are not failures and return nil.
+func (g *GeoGuard) enrich(input Input, now time.Time) (models.LoginRecord, *geoip.GeoData, error) {
+	// 1. Enrich with GeoIP data (ephemeral - coordinates not stored)
+	// Lookup failures and no-geo mode degrade to empty location fields
+	geoData := &geoip.GeoData{}
+	var asn uint
+	var orgName string
+	var lookupErr error
+	ip := normalizedIP(input.IPAddress)
+	if g.geoService != nil {
+		data, err := g.geoService.Lookup(ip)
+		switch {
+		case err == nil:
+			geoData = data
+		case !errors.Is(err, geoip.ErrPrivateIP):
+			lookupErr = err
+		}
+		if number, org, err := g.geoService.LookupASN(ip); err == nil {
+			asn, orgName = number, org
+		}
 	}
 
 	// 2. CRITICAL: Mask IP at ingestion time
 	// Raw IP is discarded after this point - only prefix is stored
-	maskedIP := rules.MaskIP(input.IPAddress)
+	maskedIP := rules.MaskIP(ip)
+
+	// Caller-supplied timestamps override the engine clock (e.g., replayed events)
+	timestamp := input.Timestamp
+	if timestamp.IsZero() {
+		timestamp = now
+	}
 
 	// 3. Create privacy-safe LoginRecord for persistence
 	// Note: NO coordinates, NO raw UserAgent - GDPR/KVKK compliant
-	currentRecord := models.LoginRecord{
+	record := models.LoginRecord{
+		SchemaVersion:   models.CurrentSchemaVersion,
 		UserID:          input.UserID,
-		Timestamp:       time.Now(),
+		Timestamp:       timestamp,
 		MaskedIPPrefix:  maskedIP, // Masked, not raw IP
 		CountryCode:     geoData.CountryCode,
 		CityGeonameID:   geoData.CityGeonameID,
 		ASN:             asn,
 		OrgName:         orgName,
+		ConnectionType:  geoData.ConnectionType,
 		FingerprintHash: rules.GenerateFingerprintHash(input.UserAgent, input.AcceptLanguage),
+		Platform:        rules.ParsePlatform(input.UserAgent),
 		IPTimezone:      geoData.Timezone,
 		ClientTimezone:  input.ClientTimezone,
+		Outcome:         input.Outcome,
 	}
 
-	// 4. Retrieve historical data for stateful rules
-	lastRecord, err := g.historyStore.GetLastRecord(input.UserID)
-	if err != nil {
-		lastRecord = nil
+	// Opt-in only: persist rounded device GPS for GPS-history rules
+	if g.storeCoordinates {
+		record.DeviceLatitude = roundCoordinate(input.Latitude, g.coordinateDecimals)
+		record.DeviceLongitude = roundCoordinate(input.Longitude, g.coordinateDecimals)
 	}
 
-	// 5. Build ephemeral geo context for rules implementing EphemeralGeoRule
-	// This context exists only during rule evaluation and is garbage collected
-	geoCtx := g.buildGeoContext(geoData, input, lastRecord)
+	return record, geoData, lookupErr
+}
 
-	// 6. Evaluate all rules and aggregate results
-	result := &models.RiskResult{
-		TotalRiskScore: 0,
-		Violations:     make([]models.Violation, 0),
-		IsBlocked:      false,
-	}
+// evaluation holds the per-Validate state shared by all rule evaluations.
+type evaluation struct {
+	ctx           context.Context
+	userID        string
+	geoCtx        rules.GeoContext
+	currentRecord models.LoginRecord
+	lastRecord    *models.LoginRecord
+	stateless     bool            // Skip stateful rules and history access (AnalyzeStateless)
+	settings      runtimeSettings // Runtime settings as of the start of the evaluation
+
+	// Recent history is fetched lazily, at most once, for rules implementing HistoryRule
+	history       []*models.LoginRecord
+	historyLoaded bool
+}
+
+// ruleOutcome is the raw result of evaluating one rule.
+type ruleOutcome struct {
+	score int
+	err   error
+}
 
-	for _, rule := range g.rules {
-		var score int
-		var ruleErr error
+// scoreRules evaluates rules in order, or concurrently when parallel evaluation
+// is enabled. Outcomes are indexed like rules, so aggregation stays deterministic.
+// Returns ctx.Err() if the evaluation context is done.
+func (g *GeoGuard) scoreRules(ruleList []rules.Rule, eval *evaluation) ([]ruleOutcome, error) {
+	if eval.settings.parallel && len(ruleList) > 1 {
+		return g.scoreRulesParallel(ruleList, eval)
+	}
 
-		// Dynamic interface detection: no type-switching on concrete types
-		// Rules implementing EphemeralGeoRule receive geographic context
-		if geoRule, ok := rule.(rules.EphemeralGeoRule); ok {
-			score, ruleErr = geoRule.ValidateWithGeo(geoCtx, currentRecord, lastRecord)
-		} else {
-			score, ruleErr = rule.Validate(currentRecord, lastRecord)
+	outcomes := make([]ruleOutcome, len(ruleList))
+	for i, rule := range ruleList {
+		if err := eval.ctx.Err(); err != nil {
+			return nil, err
 		}
+		outcomes[i].score, outcomes[i].err = g.evaluateRule(rule, eval)
+	}
+	return outcomes, nil
+}
 
-		if ruleErr != nil {
-			continue
+// evaluateRule runs a single rule, dispatching on the optional interfaces it implements.
+//
+// Dynamic interface detection: no type-switching on concrete types
+//   - EphemeralGeoRules needing GeoIP data are skipped in no-geo mode (see NewWithoutGeo)
+//   - Stateful rules are skipped in stateless analysis (see AnalyzeStateless)
+//   - Rules implementing ContextRule receive the request context and geographic context
+//   - Rules implementing EphemeralGeoRule receive geographic context
+//   - Rules implementing HistoryRule receive recent history when the store supports it
+//   - All other rules receive the current and last records
+func (g *GeoGuard) evaluateRule(rule rules.Rule, eval *evaluation) (int, error) {
+	// No-geo mode: GeoIP data is unavailable, so rules needing it cannot run
+	if g.geoService == nil && requiresGeoIP(rule) {
+		return 0, fmt.Errorf("%w: GeoIP service unavailable", rules.ErrMissingData)
+	}
+
+	// Stateless analysis: history is never consulted, so stateful rules cannot run
+	if statefulRule, ok := rule.(rules.StatefulRule); ok && eval.stateless && statefulRule.Stateful() {
+		return 0, fmt.Errorf("%w: stateless analysis", rules.ErrMissingData)
+	}
+
+	if contextRule, ok := rule.(rules.ContextRule); ok {
+		return contextRule.ValidateContext(eval.ctx, eval.geoCtx, eval.currentRecord, eval.lastRecord)
+	}
+
+	if geoRule, ok := rule.(rules.EphemeralGeoRule); ok {
+		return geoRule.ValidateWithGeo(eval.geoCtx, eval.currentRecord, eval.lastRecord)
+	}
+
+	if historyRule, ok := rule.(rules.HistoryRule); ok {
+		if !eval.historyLoaded {
+			eval.history, eval.historyLoaded = g.loadHistory(eval.ctx, eval.userID)
+		}
+		if eval.history != nil {
+			return historyRule.ValidateWithHistory(eval.currentRecord, eval.history)
 		}
+	}
 
-		if score > 0 {
-			result.TotalRiskScore += score
-			result.Violations = append(result.Violations, models.Violation{
-				RuleName:  rule.Name(),
-				RiskScore: score,
-				Reason:    rule.Description(),
-			})
+	return rule.Validate(eval.currentRecord, eval.lastRecord)
+}
+
+// newViolation builds the explainable violation entry for a triggered rule.
+// Rules implementing ExplainableRule or HistoryExplainableRule add their
+// measurements as Details.
+func (g *GeoGuard) newViolation(rule rules.Rule, score int, eval *evaluation) models.Violation {
+	violation := models.Violation{
+		RuleName:  rule.Name(),
+		RiskScore: score,
+		Reason:    rule.Description(),
+		Severity:  g.severities[rule.Name()],
+	}
+	if categorized, ok := rule.(rules.CategorizedRule); ok {
+		violation.Category = categorized.Category()
+	}
+	violation.Details = explain(rule, eval)
+	return violation
+}
+
+// explain returns the rule's Details from the inputs evaluateRule scored it
+// on: recent history for HistoryExplainableRules evaluated with it, else
+// the geographic context and last record for ExplainableRules.
+func explain(rule rules.Rule, eval *evaluation) map[string]any {
+	_, isContextRule := rule.(rules.ContextRule)
+	_, isGeoRule := rule.(rules.EphemeralGeoRule)
+	if explainable, ok := rule.(rules.HistoryExplainableRule); ok && eval.history != nil && !isContextRule && !isGeoRule {
+		return explainable.ExplainWithHistory(eval.currentRecord, eval.history)
+	}
+	if explainable, ok := rule.(rules.ExplainableRule); ok {
+		return explainable.Explain(eval.geoCtx, eval.currentRecord, eval.lastRecord)
+	}
+	return nil
+}
+
+// requiresGeoIP reports whether rule needs GeoIP data: every EphemeralGeoRule
+// unless it implements rules.GeoIPRule and returns false.
+func requiresGeoIP(rule rules.Rule) bool {
+	if _, ok := rule.(rules.EphemeralGeoRule); !ok {
+		return false
+	}
+	if geoIPRule, ok := rule.(rules.GeoIPRule); ok {
+		return geoIPRule.RequiresGeoIP()
+	}
+	return true
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// newEvaluationID generates a random identifier for a single Validate call.
+// Contains no user data; used only to correlate logs and API responses.
+func newEvaluationID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// loadBaseline retrieves the historical record stateful rules compare against.
+// Uses the configured baseline selector when the store supports recent history,
+// otherwise the most recent record. Returns nil for first logins or store errors.
+//
+// The most recent record is returned alongside the baseline so callers need
+// no second read; ok reports that it was read without error, so a nil latest
+// with ok set confirms the user has no history.
+func (g *GeoGuard) loadBaseline(ctx context.Context, userID string) (baseline, latest *models.LoginRecord, ok bool) {
+	if g.baselineSelector != nil {
+		if recent, err := g.getRecentRecords(ctx, userID); err == nil {
+			if len(recent) > 0 {
+				latest = recent[0]
+			}
+			return g.baselineSelector(recent), latest, true
 		}
 	}
 
-	// geoCtx goes out of scope here - coordinates are garbage collected
-	// Only privacy-safe currentRecord is returned
+	if g.historyStore == nil {
+		return nil, nil, false
+	}
 
-	return result, &currentRecord, nil
+	lastRecord, err := g.getLastRecord(ctx, userID)
+	if err != nil {
+		return nil, nil, false
+	}
+	return lastRecord, lastRecord, true
+}
+
+// getLastRecord reads the user's most recent record, honoring ctx when the
+// store implements storage.ContextHistoryStore.
+func (g *GeoGuard) getLastRecord(ctx context.Context, userID string) (*models.LoginRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if contextStore, ok := g.historyStore.(storage.ContextHistoryStore); ok {
+		return contextStore.GetLastRecordContext(ctx, userID)
+	}
+	return g.historyStore.GetLastRecord(userID)
+}
+
+// getRecentRecords reads up to historyDepth of the user's most recent records,
+// honoring ctx when the store implements storage.ContextRecentHistoryStore.
+// Returns storage.ErrUnsupported if the store keeps no recent history.
+func (g *GeoGuard) getRecentRecords(ctx context.Context, userID string) ([]*models.LoginRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if contextStore, ok := g.historyStore.(storage.ContextRecentHistoryStore); ok {
+		return contextStore.GetRecentRecordsContext(ctx, userID, g.historyDepth)
+	}
+	if recentStore, ok := g.historyStore.(storage.RecentHistoryStore); ok {
+		return recentStore.GetRecentRecords(userID, g.historyDepth)
+	}
+	return nil, storage.ErrUnsupported
+}
+
+// loadHistory fetches the user's recent records for rules implementing HistoryRule.
+// Returns nil if the store does not implement storage.RecentHistoryStore or the
+// lookup fails; the boolean reports that loading was attempted.
+func (g *GeoGuard) loadHistory(ctx context.Context, userID string) ([]*models.LoginRecord, bool) {
+	recent, err := g.getRecentRecords(ctx, userID)
+	if err != nil {
+		return nil, true
+	}
+	if recent == nil {
+		recent = make([]*models.LoginRecord, 0)
+	}
+	return recent, true
+}
+
+// normalizedIP canonicalizes the raw input IP (ports, brackets, zones,
+// IPv4-mapped forms) so custom Providers see the same string as Service.
+// Malformed input is passed through unchanged and fails in the provider.
+func normalizedIP(raw string) string {
+	if normalized, err := geoip.NormalizeIP(raw); err == nil {
+		return normalized
+	}
+	return raw
 }
 
 // buildGeoContext constructs ephemeral geographic context for rules.
@@ -210,10 +746,29 @@ func (g *GeoGuard) Validate(input Input) (*models.RiskResult, *models.LoginRecor
 //   - Previous IP coordinates (from GeoIP lookup of last login)
 func (g *GeoGuard) buildGeoContext(geoData *geoip.GeoData, input Input, lastRecord *models.LoginRecord) rules.GeoContext {
 	ctx := rules.GeoContext{
-		IPLatitude:      geoData.Latitude,
-		IPLongitude:     geoData.Longitude,
-		DeviceLatitude:  input.Latitude,
-		DeviceLongitude: input.Longitude,
+		IPLatitude:          geoData.Latitude,
+		IPLongitude:         geoData.Longitude,
+		IPAccuracyRadiusKm:  geoData.AccuracyRadius,
+		IPCountryConfidence: geoData.CountryConfidence,
+		DeviceLatitude:      input.Latitude,
+		DeviceLongitude:     input.Longitude,
+		TrustLevel:          input.TrustLevel,
+		UserAgent:           input.UserAgent,
+		AcceptLanguage:      input.AcceptLanguage,
+	}
+
+	// Anonymizer flags are optional: without the database the field stays nil
+	if anonymous, ok := g.geoService.(geoip.AnonymousProvider); ok {
+		if info, err := anonymous.LookupAnonymous(normalizedIP(input.IPAddress)); err == nil {
+			ctx.AnonymousIP = &rules.AnonymousIPInfo{
+				IsAnonymous:        info.IsAnonymous,
+				IsAnonymousVPN:     info.IsAnonymousVPN,
+				IsHostingProvider:  info.IsHostingProvider,
+				IsPublicProxy:      info.IsPublicProxy,
+				IsResidentialProxy: info.IsResidentialProxy,
+				IsTorExitNode:      info.IsTorExitNode,
+			}
+		}
 	}
 
 	// Look up previous location coordinates if historical data exists
@@ -229,10 +784,51 @@ func (g *GeoGuard) buildGeoContext(geoData *geoip.GeoData, input Input, lastReco
 	return ctx
 }
 
+// usesLocationHistory reports whether any rule reads GeoContext.PreviousIPCoords.
+func usesLocationHistory(ruleLists ...[]rules.Rule) bool {
+	for _, ruleList := range ruleLists {
+		for _, rule := range ruleList {
+			if locationRule, ok := rule.(rules.LocationHistoryRule); ok && locationRule.UsesLocationHistory() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loadPreviousCoords resolves the coordinates of the user's recent logins
+// into eval.geoCtx.PreviousIPCoords, most recent first. The recent history is
+// kept on eval so HistoryRule rules reuse it.
+func (g *GeoGuard) loadPreviousCoords(eval *evaluation) {
+	if !eval.historyLoaded {
+		eval.history, eval.historyLoaded = g.loadHistory(eval.ctx, eval.userID)
+	}
+	if len(eval.history) == 0 {
+		return
+	}
+
+	located := make(map[string]*geoip.GeoData, len(eval.history))
+	coords := make([][2]float64, 0, len(eval.history))
+	for _, record := range eval.history {
+		geoData, seen := located[record.MaskedIPPrefix]
+		if !seen {
+			if data, err := g.lookupPreviousLocation(record.MaskedIPPrefix); err == nil {
+				geoData = data
+			}
+			located[record.MaskedIPPrefix] = geoData
+		}
+		if geoData == nil || (geoData.Latitude == 0 && geoData.Longitude == 0) {
+			continue
+		}
+		coords = append(coords, [2]float64{geoData.Latitude, geoData.Longitude})
+	}
+	eval.geoCtx.PreviousIPCoords = coords
+}
+
 // lookupPreviousLocation performs ephemeral GeoIP lookup for historical IP prefix.
 // Used to provide previous coordinates to stateful rules like VelocityRule.
 func (g *GeoGuard) lookupPreviousLocation(maskedIPPrefix string) (*geoip.GeoData, error) {
-	if maskedIPPrefix == "" {
+	if maskedIPPrefix == "" || g.geoService == nil {
 		return nil, nil
 	}
 
@@ -245,5 +841,5 @@ func (g *GeoGuard) lookupPreviousLocation(maskedIPPrefix string) (*geoip.GeoData
 		}
 	}
 
-	return g.geoService.GetLocation(ipForLookup)
-}
\ No newline at end of file
+	return g.geoService.Lookup(ipForLookup)
+}
@@ -0,0 +1,44 @@
+package engine
+
+import (
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+)
+
+// ContextEnricher computes custom ephemeral values for rules.
+//
+// Enrichers run once per Validate call, in registration order, after the
+// built-in GeoContext fields are filled and before any rule is evaluated.
+// They store results in ctx.Extra, which is always non-nil, so multiple
+// rules can share an expensive value without each recomputing it.
+//
+// Privacy Note:
+// Input carries the raw IP address. Enrichers may use it ephemerally but
+// must not persist it; Extra itself is never persisted.
+//
+// Example:
+//
+//	guard.AddContextEnricher(func(input engine.Input, ctx *rules.GeoContext) {
+//	    if names, err := net.LookupAddr(input.IPAddress); err == nil && len(names) > 0 {
+//	        ctx.Extra["myapp.rdns"] = names[0]
+//	    }
+//	})
+type ContextEnricher func(input Input, ctx *rules.GeoContext)
+
+// AddContextEnricher registers an enricher that populates GeoContext.Extra.
+func (g *GeoGuard) AddContextEnricher(enricher ContextEnricher) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.enrichers = append(g.enrichers, enricher)
+}
+
+// runEnrichers populates ctx.Extra using the registered enrichers.
+func (g *GeoGuard) runEnrichers(input Input, ctx *rules.GeoContext) {
+	g.mu.RLock()
+	enrichers := g.enrichers
+	g.mu.RUnlock()
+
+	ctx.Extra = make(map[string]any, len(enrichers))
+	for _, enrich := range enrichers {
+		enrich(input, ctx)
+	}
+}
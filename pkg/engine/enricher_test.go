@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/geoip"
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+	"github.com/gokaycavdar/go-geoguard/pkg/storage"
+)
+
+// fixedProvider locates every IP in Istanbul.
+type fixedProvider struct{}
+
+func (fixedProvider) Lookup(ip string) (*geoip.GeoData, error) {
+	return &geoip.GeoData{CountryCode: "TR", Latitude: 41.01, Longitude: 28.98, Timezone: "Europe/Istanbul"}, nil
+}
+
+func (fixedProvider) LookupASN(ip string) (uint, string, error) {
+	return 9121, "Turk Telekom", nil
+}
+
+func TestEnrichersRunAfterPreviousCoords(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := storage.NewMemoryStore()
+	if err := store.SaveRecord(&models.LoginRecord{UserID: "alice", Timestamp: now.Add(-time.Hour), MaskedIPPrefix: "88.230.100.0/24"}); err != nil {
+		t.Fatalf("SaveRecord: %v", err)
+	}
+
+	guard := New(fixedProvider{}, store, WithClock(func() time.Time { return now }))
+	guard.AddRule(rules.NewHomeDistanceRule(500, 20)) // Requests PreviousIPCoords
+
+	var seen int
+	guard.AddContextEnricher(func(input Input, ctx *rules.GeoContext) {
+		seen = len(ctx.PreviousIPCoords)
+	})
+
+	if _, _, err := guard.Validate(Input{UserID: "alice", IPAddress: "88.230.100.50"}); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if seen != 1 {
+		t.Errorf("enricher saw %d previous coordinates, want 1", seen)
+	}
+}
@@ -0,0 +1,29 @@
+package engine
+
+import (
+	"errors"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/storage"
+)
+
+// ErrDeleteUnsupported is returned by DeleteUser when the history store cannot erase users.
+var ErrDeleteUnsupported = errors.New("history store does not support deleting users")
+
+// DeleteUser erases all stored login history for userID.
+//
+// Use this to honor GDPR/KVKK right-to-erasure requests. After deletion the
+// user's next login is treated as a first login by stateful rules. Deleting
+// a user with no history returns nil.
+//
+// Returns ErrDeleteUnsupported if the store does not implement storage.UserDeleter.
+func (g *GeoGuard) DeleteUser(userID string) error {
+	deleter, ok := g.historyStore.(storage.UserDeleter)
+	if !ok {
+		return ErrDeleteUnsupported
+	}
+	err := deleter.DeleteUser(userID)
+	if errors.Is(err, storage.ErrUnsupported) {
+		return ErrDeleteUnsupported
+	}
+	return err
+}
@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+	"github.com/gokaycavdar/go-geoguard/pkg/storage"
+)
+
+func TestViolationDetailsFromHistory(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := storage.NewMemoryStore()
+	for i, platform := range []string{rules.PlatformAndroid, rules.PlatformWindows} {
+		err := store.SaveRecord(&models.LoginRecord{
+			UserID:    "alice",
+			Timestamp: now.Add(-time.Duration(i+1) * time.Minute),
+			Platform:  platform,
+		})
+		if err != nil {
+			t.Fatalf("SaveRecord: %v", err)
+		}
+	}
+
+	guard := NewWithoutGeo(store, WithClock(func() time.Time { return now }))
+	guard.AddRule(rules.NewPlatformSwitchRule(2, time.Hour, 25))
+
+	result, _, err := guard.Validate(Input{
+		UserID:    "alice",
+		IPAddress: "81.2.69.142",
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15",
+	})
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if len(result.Violations) != 1 {
+		t.Fatalf("violations = %+v, want 1", result.Violations)
+	}
+
+	// Only the full history, not the last record, holds three platforms
+	platforms, _ := result.Violations[0].Details["platforms"].([]string)
+	if len(platforms) != 3 {
+		t.Errorf("Details = %v, want three platforms", result.Violations[0].Details)
+	}
+}
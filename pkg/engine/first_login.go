@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+)
+
+// FirstLoginPolicy configures extra scrutiny for a user's first login.
+//
+// Stateful rules (VelocityRule, CountryMismatchRule, ...) pass when there is
+// no previous login to compare against, so a fresh account from a high-risk
+// location scores only what the stateless rules see. Attackers exploit this
+// with newly created accounts. The policy closes the gap:
+//   - Score is added as a "First Login" violation (0 = none); it is
+//     aggregated and weighted like any rule score
+//   - Rules are evaluated only on first logins, after the active rules and
+//     with weight 1.0 (e.g., a stricter CountryPolicyRule or a lower-radius
+//     geofence for new accounts); DisableRule applies to them by name
+//
+// Results of first logins are marked with RiskResult.FirstLogin. The zero
+// policy only marks results.
+type FirstLoginPolicy struct {
+	Score int          // Flat risk points added on a first login
+	Rules []rules.Rule // Additional rules evaluated only on first logins
+}
+
+// SetFirstLoginPolicy enables first-login handling.
+//
+// A login counts as a first login when the history store holds no record
+// for the user. Store errors, a nil store, and AnalyzeStateless never count
+// as first logins; a baseline selector returning nil does not either, as
+// long as the user has history. With storage.NopStore every login is a
+// first login (Check reports this).
+//
+// Detection costs no extra store call: it reuses the most recent record
+// read while loading the baseline.
+func (g *GeoGuard) SetFirstLoginPolicy(policy FirstLoginPolicy) {
+	policy.Rules = append([]rules.Rule(nil), policy.Rules...)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.settings.firstLogin = &policy
+}
+
+// isFirstLogin reports whether the store confirms the user has no history:
+// the most recent record was read without error and there was none.
+func isFirstLogin(latest *models.LoginRecord, read bool) bool {
+	return read && latest == nil
+}
+
+// withFirstLoginRules appends the policy's score rule and extra rules
+// (skipping disabled names) to the active rules of this evaluation.
+func (g *GeoGuard) withFirstLoginRules(policy *FirstLoginPolicy, active []rules.Rule, weights []float64) ([]rules.Rule, []float64) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	extra := make([]rules.Rule, 0, len(policy.Rules)+1)
+	if policy.Score != 0 {
+		extra = append(extra, firstLoginRule{score: policy.Score})
+	}
+	extra = append(extra, policy.Rules...)
+
+	for _, rule := range extra {
+		if g.disabled[rule.Name()] {
+			continue
+		}
+		active = append(active, rule)
+		weights = append(weights, 1.0)
+	}
+	return active, weights
+}
+
+// firstLoginRule reports FirstLoginPolicy.Score as a violation.
+type firstLoginRule struct {
+	score int
+}
+
+func (f firstLoginRule) Name() string {
+	return "First Login"
+}
+
+func (f firstLoginRule) Description() string {
+	return "First login for this user: no history to compare against."
+}
+
+func (f firstLoginRule) Category() string {
+	return models.CategoryBehavior
+}
+
+func (f firstLoginRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return f.score, nil
+}
@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+	"github.com/gokaycavdar/go-geoguard/pkg/storage"
+)
+
+// countingStore counts GetLastRecord calls.
+type countingStore struct {
+	*storage.MemoryStore
+	lastReads int
+}
+
+func (s *countingStore) GetLastRecord(userID string) (*models.LoginRecord, error) {
+	s.lastReads++
+	return s.MemoryStore.GetLastRecord(userID)
+}
+
+func TestValidateReadsLastRecordOnce(t *testing.T) {
+	for _, name := range []string{"first login", "returning user"} {
+		t.Run(name, func(t *testing.T) {
+			store := &countingStore{MemoryStore: storage.NewMemoryStore()}
+			guard := NewWithoutGeo(store, WithEWMA(0.5))
+			guard.SetFirstLoginPolicy(FirstLoginPolicy{Score: 10})
+
+			if name == "returning user" {
+				if err := store.SaveRecord(&models.LoginRecord{UserID: "alice", SmoothedRiskScore: 20}); err != nil {
+					t.Fatalf("SaveRecord: %v", err)
+				}
+			}
+
+			result, _, err := guard.Validate(Input{UserID: "alice", IPAddress: "81.2.69.142"})
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if store.lastReads != 1 {
+				t.Errorf("GetLastRecord called %d times, want 1", store.lastReads)
+			}
+			if first := name == "first login"; result.FirstLogin != first {
+				t.Errorf("FirstLogin = %v, want %v", result.FirstLogin, first)
+			}
+		})
+	}
+}
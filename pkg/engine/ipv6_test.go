@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+	"github.com/gokaycavdar/go-geoguard/pkg/storage"
+)
+
+func TestIPv6PrefixMatchesBlocklists(t *testing.T) {
+	// Feed entries use non-canonical forms; rules must store them the way
+	// the engine masks the login IP.
+	proxy := rules.OpenProxy([]string{"2001:DB8:0:0::/64"}, 40)
+	feed := filepath.Join(t.TempDir(), "reputation.csv")
+	if err := os.WriteFile(feed, []byte("2001:0DB8::/64,90\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reputation, err := rules.LoadSubnetReputationRule(feed, 50, 30)
+	if err != nil {
+		t.Fatalf("LoadSubnetReputationRule: %v", err)
+	}
+
+	guard := NewWithoutGeo(storage.NewMemoryStore())
+	guard.AddRule(proxy)
+	guard.AddRule(reputation)
+
+	for _, ip := range []string{
+		"2001:db8::1234:5678",
+		"[2001:db8::abcd]:443",
+		"2001:DB8:0:0:ffff:ffff:ffff:ffff",
+	} {
+		t.Run(ip, func(t *testing.T) {
+			result, record, err := guard.Validate(Input{UserID: "alice", IPAddress: ip})
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if record.MaskedIPPrefix != "2001:db8::/64" {
+				t.Errorf("MaskedIPPrefix = %q, want %q", record.MaskedIPPrefix, "2001:db8::/64")
+			}
+
+			matched := make(map[string]bool)
+			for _, v := range result.Violations {
+				matched[v.RuleName] = true
+			}
+			for _, name := range []string{proxy.Name(), reputation.Name()} {
+				if !matched[name] {
+					t.Errorf("%s did not match; violations = %+v", name, result.Violations)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package engine
+
+import (
+	"errors"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/storage"
+)
+
+// ErrLinkUnsupported is returned by LinkUser when the history store cannot rename users.
+var ErrLinkUnsupported = errors.New("history store does not support linking users")
+
+// LinkUser reassigns login history from oldID to newID.
+//
+// Use this when users merge accounts or change their UserID, so stateful
+// rules keep comparing against the user's real history instead of treating
+// the next login as a first login.
+//
+// If newID already has history, both histories are merged chronologically
+// (see storage.UserRenamer for details).
+//
+// Returns ErrLinkUnsupported if the store does not implement storage.UserRenamer.
+func (g *GeoGuard) LinkUser(oldID, newID string) error {
+	renamer, ok := g.historyStore.(storage.UserRenamer)
+	if !ok {
+		return ErrLinkUnsupported
+	}
+	err := renamer.RenameUser(oldID, newID)
+	if errors.Is(err, storage.ErrUnsupported) {
+		return ErrLinkUnsupported
+	}
+	return err
+}
@@ -0,0 +1,36 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+	"github.com/gokaycavdar/go-geoguard/pkg/storage"
+)
+
+func TestNoGeoModeSkipsOnlyGeoIPRules(t *testing.T) {
+	guard := NewWithoutGeo(storage.NewMemoryStore())
+	headers := rules.NewHeaderConsistencyRule(40)
+	fence := rules.Geofencing(39.0, 35.0, 500, 50)
+	guard.AddRule(headers)
+	guard.AddRule(fence)
+
+	// A browser User-Agent without Accept-Language triggers the header rule
+	result, _, err := guard.Validate(Input{
+		UserID:    "alice",
+		IPAddress: "81.2.69.142",
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+	})
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	if !result.GeoUnavailable {
+		t.Error("GeoUnavailable = false in no-geo mode")
+	}
+	if len(result.Violations) != 1 || result.Violations[0].RuleName != headers.Name() {
+		t.Errorf("violations = %+v, want only %q", result.Violations, headers.Name())
+	}
+	if len(result.RuleErrors) != 1 || result.RuleErrors[0].RuleName != fence.Name() || !result.RuleErrors[0].Skipped {
+		t.Errorf("rule errors = %+v, want %q skipped", result.RuleErrors, fence.Name())
+	}
+}
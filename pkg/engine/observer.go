@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// ObservationEvent summarizes one Validate call for logging and metrics.
+//
+// Privacy Note:
+// It carries only privacy-safe values: the raw IP address, coordinates, and
+// raw headers never appear here. CountryCode and MaskedIPPrefix are the same
+// coarse values stored on the LoginRecord.
+type ObservationEvent struct {
+	EvaluationID   string           // RiskResult.EvaluationID; empty on error
+	UserID         string           // Input.UserID
+	TotalRiskScore int              // RiskResult.TotalRiskScore; 0 on error
+	Level          models.RiskLevel // RiskResult.Level() with default thresholds
+	IsBlocked      bool             // RiskResult.IsBlocked
+	TriggeredRules []string         // Names of rules listed in RiskResult.Violations, in order
+	SkippedRules   []string         // Names of rules listed in RiskResult.RuleErrors
+	CountryCode    string           // IP country of the current login
+	MaskedIPPrefix string           // Masked IP prefix of the current login
+	Duration       time.Duration    // Wall-clock time spent in Validate
+	Err            error            // Error returned by Validate, if any
+}
+
+// Observer receives an ObservationEvent after every Validate call.
+type Observer func(event ObservationEvent)
+
+// SetObserver registers a callback fired exactly once per Validate (and
+// ValidateContext) call, after the result is computed, including calls that
+// fail. Passing nil removes the observer.
+//
+// The observer runs synchronously on the calling goroutine, so it should be
+// fast (e.g., increment a counter or hand off to a logger). A panicking
+// observer is recovered and never affects the returned result.
+//
+// Example:
+//
+//	guard.SetObserver(func(e engine.ObservationEvent) {
+//	    logger.Info("geoguard",
+//	        zap.String("user", e.UserID),
+//	        zap.Int("score", e.TotalRiskScore),
+//	        zap.Stringer("level", e.Level),
+//	        zap.Strings("rules", e.TriggeredRules))
+//	})
+func (g *GeoGuard) SetObserver(observer Observer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.settings.observer = observer
+}
+
+// observedValidate runs validate and reports the outcome to the observer.
+func (g *GeoGuard) observedValidate(ctx context.Context, input Input) (*models.RiskResult, *models.LoginRecord, error) {
+	observer := g.currentSettings().observer
+	if observer == nil {
+		return g.validate(ctx, input, false)
+	}
+
+	start := time.Now()
+	result, record, err := g.validate(ctx, input, false)
+	notifyObserver(observer, newObservationEvent(input.UserID, result, record, time.Since(start), err))
+	return result, record, err
+}
+
+// newObservationEvent builds the privacy-safe summary of one evaluation.
+func newObservationEvent(userID string, result *models.RiskResult, record *models.LoginRecord, duration time.Duration, err error) ObservationEvent {
+	event := ObservationEvent{
+		UserID:   userID,
+		Duration: duration,
+		Err:      err,
+	}
+
+	if result != nil {
+		event.EvaluationID = result.EvaluationID
+		event.TotalRiskScore = result.TotalRiskScore
+		event.Level = result.Level()
+		event.IsBlocked = result.IsBlocked
+		event.TriggeredRules = make([]string, 0, len(result.Violations))
+		for _, violation := range result.Violations {
+			event.TriggeredRules = append(event.TriggeredRules, violation.RuleName)
+		}
+		event.SkippedRules = make([]string, 0, len(result.RuleErrors))
+		for _, ruleErr := range result.RuleErrors {
+			event.SkippedRules = append(event.SkippedRules, ruleErr.RuleName)
+		}
+	}
+
+	if record != nil {
+		event.CountryCode = record.CountryCode
+		event.MaskedIPPrefix = record.MaskedIPPrefix
+	}
+
+	return event
+}
+
+// notifyObserver calls observer, recovering any panic so observability
+// failures never affect evaluation.
+func notifyObserver(observer Observer, event ObservationEvent) {
+	defer func() {
+		_ = recover()
+	}()
+	observer(event)
+}
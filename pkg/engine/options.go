@@ -0,0 +1,182 @@
+package engine
+
+import (
+	"maps"
+	"math"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// defaultHistoryDepth is the number of recent records fetched for history-aware features.
+const defaultHistoryDepth = 10
+
+// Option configures optional GeoGuard behavior at construction time.
+//
+// Usage:
+//
+//	guard := engine.New(geoService, store,
+//	    engine.WithHistoryDepth(20),
+//	)
+type Option func(*GeoGuard)
+
+// WithHistoryDepth sets how many recent records are fetched from stores
+// implementing storage.RecentHistoryStore. Values below 1 are ignored.
+// Default: 10.
+//
+// The depth bounds HistoryRule input, the baseline selector's candidates,
+// and GeoContext.PreviousIPCoords (one GeoIP lookup per distinct prefix).
+func WithHistoryDepth(n int) Option {
+	return func(g *GeoGuard) {
+		if n >= 1 {
+			g.historyDepth = n
+		}
+	}
+}
+
+// WithBaselineSelector chooses which historical record stateful rules compare against.
+//
+// The selector receives the user's recent records (most recent first, up to
+// the configured history depth) and returns the record to pass to rules as
+// lastRecord. Returning nil treats the login as a first login.
+//
+// Default: the most recent record (equivalent to HistoryStore.GetLastRecord).
+//
+// Fallback: if the store does not implement storage.RecentHistoryStore, or
+// fetching recent records fails, the selector is not called and the most
+// recent record is used.
+//
+// Example (ignore logins from a known data center network):
+//
+//	engine.WithBaselineSelector(func(recent []*models.LoginRecord) *models.LoginRecord {
+//	    for _, r := range recent {
+//	        if r.ASN != 16509 {
+//	            return r
+//	        }
+//	    }
+//	    return nil
+//	})
+func WithBaselineSelector(selector func(recent []*models.LoginRecord) *models.LoginRecord) Option {
+	return func(g *GeoGuard) {
+		g.baselineSelector = selector
+	}
+}
+
+// WithCoordinateStorage opts in to storing device GPS on the LoginRecord,
+// rounded to the given number of decimal places.
+//
+// Privacy Trade-off:
+// By default coordinates are never persisted. Enabling this stores a
+// rounded copy of the device GPS so history-based GPS rules (e.g.,
+// RepeatedGPSRule) can run. Choose the coarsest precision that serves
+// your rules and disclose the storage in your privacy policy.
+//
+// Precision guide (decimals -> approximate resolution):
+//   - 2 -> 1.1 km
+//   - 3 -> 110 m
+//   - 4 -> 11 m
+//   - 5 -> 1.1 m (recommended for spoof detection; real GPS jitters at this scale)
+//
+// Values outside 0-8 are clamped.
+func WithCoordinateStorage(decimals int) Option {
+	return func(g *GeoGuard) {
+		g.storeCoordinates = true
+		g.coordinateDecimals = min(max(decimals, 0), 8)
+	}
+}
+
+// WithSeverityMap assigns integrator-defined severity labels to violations.
+//
+// The map is keyed by rule Name() (e.g., "Impossible Travel (Velocity Check)")
+// and its values are free-form labels such as "log", "alert", or "block".
+// This decouples operational routing from numeric scores; use
+// RiskResult.ViolationsBySeverity to select violations for each pipeline.
+//
+// Rules missing from the map produce violations with an empty Severity.
+// The map is copied; later changes by the caller have no effect.
+func WithSeverityMap(severities map[string]string) Option {
+	return func(g *GeoGuard) {
+		g.severities = maps.Clone(severities)
+	}
+}
+
+// WithMinViolationScore hides low-impact violations from RiskResult.Violations.
+//
+// Only violations whose score magnitude is at or above minScore are listed
+// (credits such as -20 are compared by magnitude). Filtered violations still
+// count toward TotalRiskScore, so the total stays accurate while dashboards
+// and logs show only the important signals.
+//
+// Default: 0 (all violations are listed).
+func WithMinViolationScore(minScore int) Option {
+	return func(g *GeoGuard) {
+		g.minViolationScore = minScore
+	}
+}
+
+// WithClock replaces the engine clock (default: time.Now).
+//
+// The clock timestamps records when Input.Timestamp is zero and is exposed
+// to rules as GeoContext.EvaluatedAt. Useful for tests and deterministic replays.
+func WithClock(clock func() time.Time) Option {
+	return func(g *GeoGuard) {
+		if clock != nil {
+			g.clock = clock
+		}
+	}
+}
+
+// WithBlockThreshold sets RiskResult.IsBlocked when TotalRiskScore reaches threshold.
+//
+// The engine never blocks by itself; IsBlocked is a convenience for callers
+// that want a single configured threshold. Values below 1 disable it (default).
+func WithBlockThreshold(threshold int) Option {
+	return func(g *GeoGuard) {
+		g.blockThreshold = threshold
+	}
+}
+
+// WithMaxTotalScore caps TotalRiskScore at maxScore.
+//
+// With many rules the raw total can far exceed 100, which makes it hard to
+// read as a single risk number. The cap applies to the aggregate only:
+// Violations still report each rule's full contribution, so the result stays
+// explainable. The block threshold and EWMA smoothing see the capped total.
+//
+// Values below 1 disable the cap (default).
+func WithMaxTotalScore(maxScore int) Option {
+	return func(g *GeoGuard) {
+		g.maxTotalScore = maxScore
+	}
+}
+
+// WithEWMA maintains a per-user exponentially-weighted moving average of
+// TotalRiskScore, reported as RiskResult.SmoothedScore.
+//
+// A single spiky score moves the average only partially, while sustained
+// elevated risk escalates it, which suits adaptive throttling.
+//
+// Alpha semantics:
+//   - smoothed = alpha*TotalRiskScore + (1-alpha)*previousSmoothed
+//   - Higher alpha reacts faster; lower alpha smooths more (recommend 0.3)
+//   - Values outside (0, 1] are ignored (EWMA stays disabled)
+//
+// State:
+// The previous average is read from the user's last record
+// (LoginRecord.SmoothedRiskScore), so the caller must save the returned
+// record as usual. On a user's first login the average cold-starts at the
+// current TotalRiskScore. Records saved before EWMA was enabled carry an
+// average of 0, so existing users ramp up from 0 instead.
+func WithEWMA(alpha float64) Option {
+	return func(g *GeoGuard) {
+		if alpha > 0 && alpha <= 1 {
+			g.ewmaAlpha = alpha
+		}
+	}
+}
+
+// roundCoordinate rounds a coordinate to the given number of decimal places.
+func roundCoordinate(value float64, decimals int) float64 {
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}
@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"sync"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+)
+
+// EnableParallelEvaluation toggles concurrent rule evaluation.
+//
+// When enabled, Validate evaluates rules in separate goroutines and waits for
+// all of them, which lowers latency when rules block (remote lookups,
+// network-backed feeds). Disabled by default, so single-threaded users are
+// unaffected.
+//
+// Guarantees:
+//   - Violations keep rule insertion order, exactly as in sequential mode
+//   - GeoContext and records are shared read-only between goroutines
+//   - Recent history is loaded once, before the fan-out
+//
+// Custom rules must be goroutine-safe when this mode is enabled: a rule may
+// run concurrently with other rules, and with itself across Validate calls.
+func (g *GeoGuard) EnableParallelEvaluation(enabled bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.settings.parallel = enabled
+}
+
+// scoreRulesParallel evaluates rules concurrently and returns their outcomes
+// indexed like rules.
+func (g *GeoGuard) scoreRulesParallel(ruleList []rules.Rule, eval *evaluation) ([]ruleOutcome, error) {
+	if err := eval.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Lazy history loading mutates eval; do it once up front instead
+	if !eval.historyLoaded {
+		for _, rule := range ruleList {
+			if _, ok := rule.(rules.HistoryRule); ok {
+				eval.history, eval.historyLoaded = g.loadHistory(eval.ctx, eval.userID)
+				break
+			}
+		}
+	}
+
+	outcomes := make([]ruleOutcome, len(ruleList))
+	var wg sync.WaitGroup
+	for i, rule := range ruleList {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outcomes[i].score, outcomes[i].err = g.evaluateRule(rule, eval)
+		}()
+	}
+	wg.Wait()
+
+	if err := eval.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return outcomes, nil
+}
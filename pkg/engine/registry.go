@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+)
+
+// RemoveRule unregisters every rule (active or shadow) whose Name() equals name.
+//
+// Rule names are not required to be unique; all matches are removed.
+// Returns true if at least one rule was removed.
+func (g *GeoGuard) RemoveRule(name string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	removed := false
+	keptRules := g.rules[:0]
+	keptWeights := g.weights[:0]
+	for i, rule := range g.rules {
+		if rule.Name() == name {
+			removed = true
+			continue
+		}
+		keptRules = append(keptRules, rule)
+		keptWeights = append(keptWeights, g.weights[i])
+	}
+	clear(g.rules[len(keptRules):])
+	g.rules, g.weights = keptRules, keptWeights
+
+	keptShadow := g.shadowRules[:0]
+	for _, rule := range g.shadowRules {
+		if rule.Name() == name {
+			removed = true
+			continue
+		}
+		keptShadow = append(keptShadow, rule)
+	}
+	clear(g.shadowRules[len(keptShadow):])
+	g.shadowRules = keptShadow
+
+	return removed
+}
+
+// DisableRule skips every rule (active or shadow) named name until EnableRule is called.
+//
+// Disabled rules remain registered, so an operator can silence a noisy rule
+// during an incident and re-enable it later without redeploying. Like
+// RemoveRule, this affects all rules sharing the name. Disabling a name that
+// is not (yet) registered is allowed and applies to rules added later.
+func (g *GeoGuard) DisableRule(name string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.disabled == nil {
+		g.disabled = make(map[string]bool)
+	}
+	g.disabled[name] = true
+}
+
+// EnableRule re-enables rules previously disabled with DisableRule.
+func (g *GeoGuard) EnableRule(name string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.disabled, name)
+}
+
+// ListRules returns the names of all registered rules in evaluation order:
+// active rules first, then shadow rules. Disabled rules are included.
+func (g *GeoGuard) ListRules() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	names := make([]string, 0, len(g.rules)+len(g.shadowRules))
+	for _, rule := range g.rules {
+		names = append(names, rule.Name())
+	}
+	for _, rule := range g.shadowRules {
+		names = append(names, rule.Name())
+	}
+	return names
+}
+
+// enabledRules snapshots the active rules (with weights) and shadow rules,
+// excluding disabled ones, so Validate never races with registry changes.
+func (g *GeoGuard) enabledRules() (active []rules.Rule, weights []float64, shadow []rules.Rule) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	active = make([]rules.Rule, 0, len(g.rules))
+	weights = make([]float64, 0, len(g.rules))
+	for i, rule := range g.rules {
+		if g.disabled[rule.Name()] {
+			continue
+		}
+		active = append(active, rule)
+		weights = append(weights, g.weights[i])
+	}
+
+	shadow = make([]rules.Rule, 0, len(g.shadowRules))
+	for _, rule := range g.shadowRules {
+		if !g.disabled[rule.Name()] {
+			shadow = append(shadow, rule)
+		}
+	}
+	return active, weights, shadow
+}
+
+// AddRulesFromConfig builds rules from declarative config and registers them.
+//
+// Each config is built with registry (nil uses rules.DefaultRegistry), then
+// added with AddShadowRule if Shadow is set, or AddRuleWithWeight otherwise
+// (Weight 0 means 1.0). Building is all-or-nothing: if any entry fails,
+// no rule is added and the error names the failing entry.
+//
+// Example:
+//
+//	var configs []rules.RuleConfig
+//	if err := json.Unmarshal(data, &configs); err != nil {
+//	    return err
+//	}
+//	if err := guard.AddRulesFromConfig(nil, configs); err != nil {
+//	    return err
+//	}
+func (g *GeoGuard) AddRulesFromConfig(registry *rules.Registry, configs []rules.RuleConfig) error {
+	if registry == nil {
+		registry = rules.DefaultRegistry()
+	}
+	built, err := registry.BuildFromConfig(configs)
+	if err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i, rule := range built {
+		if configs[i].Shadow {
+			g.shadowRules = append(g.shadowRules, rule)
+			continue
+		}
+		weight := configs[i].Weight
+		if weight == 0 {
+			weight = 1.0
+		}
+		g.rules = append(g.rules, rule)
+		g.weights = append(g.weights, weight)
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+)
+
+// redacted replaces secret values in snapshots.
+const redacted = "[REDACTED]"
+
+// secretFieldMarkers identify rule fields whose values must never appear in a snapshot.
+var secretFieldMarkers = []string{"secret", "salt", "password", "token", "apikey", "credential"}
+
+// Snapshot is a serializable view of an engine's active configuration.
+//
+// Snapshots support change management: capture one per deployment, store it
+// as JSON, and review Diff output to audit rule-tuning history. Secrets are
+// redacted, and function-valued settings (resolvers, selectors, clocks) are
+// described only by type.
+type Snapshot struct {
+	Rules       []RuleSnapshot `json:"rules"`
+	ShadowRules []RuleSnapshot `json:"shadow_rules"`
+	Disabled    []string       `json:"disabled"`
+	Options     map[string]any `json:"options"`
+}
+
+// RuleSnapshot describes one registered rule.
+type RuleSnapshot struct {
+	Name   string         `json:"name"`
+	Type   string         `json:"type"`
+	Weight float64        `json:"weight"`
+	Params map[string]any `json:"params"`
+}
+
+// Change is a single difference between two snapshots.
+// Old is nil for additions and New is nil for removals.
+type Change struct {
+	Path string `json:"path"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+// ConfigSnapshot captures the engine's rules, weights, disabled rules, and options.
+//
+// Rule parameters come from rules.ParameterizedRule when implemented, and
+// from the rule's exported fields otherwise. Fields whose names suggest a
+// secret (salt, token, password, ...) are redacted.
+func (g *GeoGuard) ConfigSnapshot() Snapshot {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	snapshot := Snapshot{
+		Rules:       make([]RuleSnapshot, 0, len(g.rules)),
+		ShadowRules: make([]RuleSnapshot, 0, len(g.shadowRules)),
+		Disabled:    make([]string, 0, len(g.disabled)),
+		Options: map[string]any{
+			"geoip_available":     g.geoService != nil,
+			"history_store":       fmt.Sprintf("%T", g.historyStore),
+			"history_depth":       g.historyDepth,
+			"baseline_selector":   g.baselineSelector != nil,
+			"store_coordinates":   g.storeCoordinates,
+			"coordinate_decimals": g.coordinateDecimals,
+			"severities":          normalizeParam(reflect.ValueOf(g.severities), 0),
+			"min_violation_score": g.minViolationScore,
+			"block_threshold":     g.blockThreshold,
+			"max_total_score":     g.maxTotalScore,
+			"ewma_alpha":          g.ewmaAlpha,
+			"adaptive_scoring":    g.settings.adaptive,
+			"parallel":            g.settings.parallel,
+			"score_aggregator":    g.settings.scoreAggregator != nil,
+			"context_enrichers":   len(g.enrichers),
+			"observer":            g.settings.observer != nil,
+			"first_login_policy":  g.settings.firstLogin != nil,
+		},
+	}
+
+	for i, rule := range g.rules {
+		snapshot.Rules = append(snapshot.Rules, snapshotRule(rule, g.weights[i]))
+	}
+	for _, rule := range g.shadowRules {
+		snapshot.ShadowRules = append(snapshot.ShadowRules, snapshotRule(rule, 1.0))
+	}
+	for name := range g.disabled {
+		snapshot.Disabled = append(snapshot.Disabled, name)
+	}
+	sort.Strings(snapshot.Disabled)
+
+	return snapshot
+}
+
+// Diff lists the differences from s to other, sorted by path.
+//
+// Paths look like "options.block_threshold", "rules.Velocity.weight", or
+// "rules.Velocity.params.MaxSpeedKmh". Rules are matched by name; repeated
+// names get a "#2", "#3", ... suffix in registration order.
+func (s Snapshot) Diff(other Snapshot) []Change {
+	before, after := s.flatten(), other.flatten()
+
+	paths := make([]string, 0, len(before)+len(after))
+	for path := range before {
+		paths = append(paths, path)
+	}
+	for path := range after {
+		if _, ok := before[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	changes := make([]Change, 0)
+	for _, path := range paths {
+		oldValue, hadOld := before[path]
+		newValue, hasNew := after[path]
+		if hadOld && hasNew && sameValue(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, Change{Path: path, Old: oldValue, New: newValue})
+	}
+	return changes
+}
+
+// flatten maps every leaf of the snapshot to its path.
+func (s Snapshot) flatten() map[string]any {
+	flat := make(map[string]any)
+	for key, value := range s.Options {
+		flat["options."+key] = value
+	}
+	flattenRules(flat, "rules", s.Rules)
+	flattenRules(flat, "shadow_rules", s.ShadowRules)
+	for _, name := range s.Disabled {
+		flat["disabled."+name] = true
+	}
+	return flat
+}
+
+// flattenRules adds rule entries under prefix, disambiguating repeated names.
+func flattenRules(flat map[string]any, prefix string, ruleList []RuleSnapshot) {
+	seen := make(map[string]int)
+	for _, rule := range ruleList {
+		seen[rule.Name]++
+		key := prefix + "." + rule.Name
+		if n := seen[rule.Name]; n > 1 {
+			key = fmt.Sprintf("%s#%d", key, n)
+		}
+
+		flat[key+".type"] = rule.Type
+		flat[key+".weight"] = rule.Weight
+		for param, value := range rule.Params {
+			flat[key+".params."+param] = value
+		}
+	}
+}
+
+// sameValue compares two snapshot values by their JSON encoding, so values
+// decoded from a stored snapshot compare equal to freshly captured ones.
+func sameValue(a, b any) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return string(encodedA) == string(encodedB)
+}
+
+// snapshotRule describes a single rule.
+func snapshotRule(rule rules.Rule, weight float64) RuleSnapshot {
+	snapshot := RuleSnapshot{
+		Name:   rule.Name(),
+		Type:   fmt.Sprintf("%T", rule),
+		Weight: weight,
+		Params: make(map[string]any),
+	}
+
+	if parameterized, ok := rule.(rules.ParameterizedRule); ok {
+		for key, value := range parameterized.Params() {
+			if isSecretField(key) {
+				value = redacted
+			}
+			snapshot.Params[key] = value
+		}
+		return snapshot
+	}
+
+	v := reflect.ValueOf(rule)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return snapshot
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if isSecretField(field.Name) {
+			snapshot.Params[field.Name] = redacted
+			continue
+		}
+		snapshot.Params[field.Name] = normalizeParam(v.Field(i), 0)
+	}
+	return snapshot
+}
+
+// isSecretField reports whether a parameter name suggests a secret value.
+func isSecretField(name string) bool {
+	lower := strings.ToLower(name)
+	for _, marker := range secretFieldMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxParamDepth bounds recursion into nested rule parameters.
+const maxParamDepth = 6
+
+// normalizeParam converts a rule field into a JSON-serializable value.
+// Functions and channels are described by type; maps with non-string keys
+// are re-keyed with fmt.Sprint; durations are rendered as strings.
+func normalizeParam(v reflect.Value, depth int) any {
+	if !v.IsValid() {
+		return nil
+	}
+	if depth > maxParamDepth {
+		return v.Type().String()
+	}
+
+	switch v.Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		if v.IsNil() {
+			return nil
+		}
+		return v.Type().String()
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return normalizeParam(v.Elem(), depth+1)
+	case reflect.Struct:
+		if t, ok := v.Interface().(time.Time); ok {
+			return t
+		}
+		fields := make(map[string]any)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if isSecretField(field.Name) {
+				fields[field.Name] = redacted
+				continue
+			}
+			fields[field.Name] = normalizeParam(v.Field(i), depth+1)
+		}
+		return fields
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		entries := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entries[fmt.Sprint(iter.Key().Interface())] = normalizeParam(iter.Value(), depth+1)
+		}
+		return entries
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = normalizeParam(v.Index(i), depth+1)
+		}
+		return items
+	case reflect.Int64:
+		if d, ok := v.Interface().(time.Duration); ok {
+			return d.String()
+		}
+		return v.Int()
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,29 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// AnalyzeStateless evaluates a login without touching the history store.
+//
+// Use it for anonymous or pre-login traffic (e.g., scoring a sign-up or a
+// password-reset request) where there is no account history to compare
+// against, or where a store round-trip is not worth its latency.
+//
+// Compared to Validate:
+//   - No history is read: lastRecord is nil and GeoContext has no previous
+//     location
+//   - Rules reporting Stateful() == true are skipped and listed in
+//     RiskResult.RuleErrors as skipped (they would compare against history,
+//     or, like SharedIPRule, query the store themselves)
+//   - No LoginRecord is returned and EWMA smoothing is not applied
+//   - The observer (SetObserver) is not called
+//
+// Stateless rules (geofencing, data center, timezone, IP-GPS, ...) behave
+// exactly as in Validate.
+func (g *GeoGuard) AnalyzeStateless(input Input) (*models.RiskResult, error) {
+	result, _, err := g.validate(context.Background(), input, true)
+	return result, err
+}
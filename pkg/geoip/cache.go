@@ -0,0 +1,154 @@
+package geoip
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCacheTTL is how long NewServiceWithCache keeps a cached lookup.
+// MaxMind databases update weekly, so an hour trades negligible staleness
+// for a high hit rate.
+const DefaultCacheTTL = time.Hour
+
+// CacheStats reports lookup cache effectiveness.
+type CacheStats struct {
+	Hits    uint64 // Lookups answered from the cache
+	Misses  uint64 // Lookups that read the database
+	Entries int    // Entries currently cached
+}
+
+// HitRate returns Hits / (Hits + Misses), or 0 before any lookup.
+func (c CacheStats) HitRate() float64 {
+	total := c.Hits + c.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(total)
+}
+
+// asnEntry is a cached GetASN result.
+type asnEntry struct {
+	number uint
+	org    string
+}
+
+// cacheItem is one LRU entry.
+type cacheItem struct {
+	key     string
+	value   any
+	expires time.Time
+}
+
+// lookupCache is a concurrency-safe LRU cache with per-entry expiry.
+// Only successful lookups are cached.
+type lookupCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List               // Front = most recently used
+	entries map[string]*list.Element // Key -> element in order
+	now     func() time.Time
+
+	generation uint64 // Incremented by purge; see put
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// newLookupCache creates a cache holding up to size entries for ttl.
+func newLookupCache(size int, ttl time.Duration) *lookupCache {
+	return &lookupCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+		now:     time.Now,
+	}
+}
+
+// get returns a live cached value and records a hit or miss.
+func (c *lookupCache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	item := element.Value.(*cacheItem)
+	if c.now().After(item.expires) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	c.hits.Add(1)
+	return item.value, true
+}
+
+// currentGeneration returns the purge generation. Read it before the
+// database lookup whose result is passed to put.
+func (c *lookupCache) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// put stores a value, evicting the least recently used entry when full.
+//
+// generation is the value of currentGeneration taken before the lookup.
+// If the cache was purged since (a Reload swapped the databases while the
+// lookup was in flight), the value may come from the old databases and is
+// dropped.
+func (c *lookupCache) put(key string, value any, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	expires := c.now().Add(c.ttl)
+	if element, ok := c.entries[key]; ok {
+		item := element.Value.(*cacheItem)
+		item.value, item.expires = value, expires
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheItem{key: key, value: value, expires: expires})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheItem).key)
+	}
+}
+
+// purge drops all entries (e.g., after the databases change).
+func (c *lookupCache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[string]*list.Element, c.size)
+	c.generation++
+}
+
+// stats returns the current counters.
+func (c *lookupCache) stats() CacheStats {
+	c.mu.Lock()
+	entries := c.order.Len()
+	c.mu.Unlock()
+
+	return CacheStats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Entries: entries,
+	}
+}
@@ -0,0 +1,164 @@
+package geoip
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLookupCacheDropsPutsFromBeforePurge(t *testing.T) {
+	cache := newLookupCache(10, time.Hour)
+
+	// A lookup starts, then Reload purges the cache before it finishes
+	generation := cache.currentGeneration()
+	cache.purge()
+	cache.put("loc:81.2.69.142", "stale", generation)
+
+	if _, ok := cache.get("loc:81.2.69.142"); ok {
+		t.Fatal("value read before the purge was cached")
+	}
+
+	cache.put("loc:81.2.69.142", "fresh", cache.currentGeneration())
+	if value, ok := cache.get("loc:81.2.69.142"); !ok || value != "fresh" {
+		t.Fatalf("get = %v, %v; want fresh, true", value, ok)
+	}
+}
+
+func TestLookupCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newLookupCache(2, time.Hour)
+	generation := cache.currentGeneration()
+
+	cache.put("a", 1, generation)
+	cache.put("b", 2, generation)
+	cache.get("a") // "b" is now least recently used
+	cache.put("c", 3, generation)
+
+	if _, ok := cache.get("b"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.get(key); !ok {
+			t.Errorf("%q was evicted", key)
+		}
+	}
+	if entries := cache.stats().Entries; entries != 2 {
+		t.Errorf("Entries = %d, want 2", entries)
+	}
+}
+
+func TestLookupCacheExpiresEntries(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := newLookupCache(10, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.put("a", 1, cache.currentGeneration())
+
+	now = now.Add(time.Minute)
+	if _, ok := cache.get("a"); !ok {
+		t.Fatal("entry expired at exactly its TTL")
+	}
+
+	now = now.Add(time.Second)
+	if _, ok := cache.get("a"); ok {
+		t.Fatal("entry served after its TTL")
+	}
+	if entries := cache.stats().Entries; entries != 0 {
+		t.Errorf("Entries = %d after expiry, want 0", entries)
+	}
+}
+
+func TestLookupCacheConcurrentAccess(t *testing.T) {
+	const workers, lookups = 8, 1000
+	cache := newLookupCache(64, time.Hour)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < lookups; i++ {
+				key := fmt.Sprintf("loc:10.0.%d.%d", w, i%100)
+				if _, ok := cache.get(key); !ok {
+					cache.put(key, i, cache.currentGeneration())
+				}
+				if i%250 == 0 {
+					cache.purge()
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	stats := cache.stats()
+	if stats.Hits+stats.Misses != workers*lookups {
+		t.Errorf("Hits+Misses = %d, want %d", stats.Hits+stats.Misses, workers*lookups)
+	}
+	if stats.Entries > 64 {
+		t.Errorf("Entries = %d, exceeds size 64", stats.Entries)
+	}
+}
+
+func BenchmarkLookupCache(b *testing.B) {
+	cache := newLookupCache(1024, time.Hour)
+	generation := cache.currentGeneration()
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("loc:81.2.%d.%d", i/256, i%256)
+		cache.put(keys[i], &GeoData{}, generation)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.get(keys[i%len(keys)])
+	}
+}
+
+// BenchmarkServiceGetLocation compares database lookups with and without the
+// cache on a workload where each IP recurs, as returning users do. It needs
+// real databases: set GEOGUARD_CITY_DB and GEOGUARD_ASN_DB, or place them in
+// data/ at the repository root (as the examples do).
+//
+//	go test ./pkg/geoip -run '^$' -bench ServiceGetLocation
+func BenchmarkServiceGetLocation(b *testing.B) {
+	cityDB := benchmarkDBPath(b, "GEOGUARD_CITY_DB", "../../data/GeoLite2-City.mmdb")
+	asnDB := benchmarkDBPath(b, "GEOGUARD_ASN_DB", "../../data/GeoLite2-ASN.mmdb")
+
+	// 1,000 distinct public IPs, each seen many times
+	ips := make([]string, 1000)
+	for i := range ips {
+		ips[i] = fmt.Sprintf("81.%d.%d.%d", 2+i/65536, (i/256)%256, i%256)
+	}
+
+	for _, cacheSize := range []int{0, 100, 1000} {
+		b.Run(fmt.Sprintf("cache=%d", cacheSize), func(b *testing.B) {
+			service, err := NewService(cityDB, asnDB)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer service.Close()
+			service.EnableCache(cacheSize, DefaultCacheTTL)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				service.GetLocation(ips[i%len(ips)])
+			}
+			b.StopTimer()
+			b.ReportMetric(service.CacheStats().HitRate(), "hit-rate")
+		})
+	}
+}
+
+// benchmarkDBPath returns the database path from env, else fallback, and
+// skips the benchmark if neither exists.
+func benchmarkDBPath(b *testing.B, env, fallback string) string {
+	path := os.Getenv(env)
+	if path == "" {
+		path = fallback
+	}
+	if _, err := os.Stat(path); err != nil {
+		b.Skipf("%s not found; set %s to run this benchmark", path, env)
+	}
+	return path
+}
@@ -0,0 +1,106 @@
+package geoip
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ErrNoClientIP is returned by ClientIPFromHeaders when the request carries
+// none of the supported client IP headers.
+var ErrNoClientIP = errors.New("no client IP header")
+
+// ClientIPFromHeaders extracts the real client IP from proxy headers, for
+// callers that do not have a framework helper such as gin's ClientIP.
+//
+// Behavior:
+//   - X-Forwarded-For (all header lines, in order) is walked right-to-left.
+//     Entries inside trustedProxies are skipped; the first untrusted entry
+//     is the client. Each proxy appends the address it received the request
+//     from, so the rightmost untrusted entry is the last one a trusted proxy
+//     vouched for; entries to its left are client-controlled.
+//   - If every entry is trusted, the leftmost one is returned.
+//   - Without a non-empty X-Forwarded-For, CF-Connecting-IP and then
+//     True-Client-IP are used.
+//   - The result is normalized (see NormalizeIP).
+//
+// Returns ErrNoClientIP when no header is present, and an error wrapping
+// ErrInvalidIP when an entry reached by the walk does not parse. A
+// malformed chain is rejected rather than skipped, since it
+// means the header was not written by a trusted proxy.
+//
+// Limitations:
+//   - Headers are only meaningful when the request came from a trusted
+//     proxy. Check http.Request.RemoteAddr first and use it directly for
+//     direct connections; otherwise clients can set any address they like.
+//   - CF-Connecting-IP and True-Client-IP are set by Cloudflare and Akamai.
+//     Behind other proxies a client can supply them, so strip them at the
+//     edge if you do not use those CDNs.
+//
+// Example:
+//
+//	ip, err := geoip.ClientIPFromHeaders(r.Header, trustedProxies)
+//	if err != nil {
+//	    ip = r.RemoteAddr // NormalizeIP strips the port
+//	}
+func ClientIPFromHeaders(headers http.Header, trustedProxies []net.IPNet) (string, error) {
+	forwarded := strings.Join(headers.Values("X-Forwarded-For"), ",")
+	if ip, err := clientIPFromForwardedFor(forwarded, trustedProxies); !errors.Is(err, ErrNoClientIP) {
+		return ip, err
+	}
+
+	for _, name := range []string{"CF-Connecting-IP", "True-Client-IP"} {
+		value := strings.TrimSpace(headers.Get(name))
+		if value == "" {
+			continue
+		}
+		ip, err := NormalizeIP(value)
+		if err != nil {
+			return "", fmt.Errorf("%s %q: %w", name, value, err)
+		}
+		return ip, nil
+	}
+
+	return "", ErrNoClientIP
+}
+
+// clientIPFromForwardedFor walks a comma-separated X-Forwarded-For chain
+// right-to-left and returns the first address outside trustedProxies.
+// Returns ErrNoClientIP for an empty chain.
+func clientIPFromForwardedFor(chain string, trustedProxies []net.IPNet) (string, error) {
+	entries := strings.Split(chain, ",")
+
+	var ip string
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := strings.TrimSpace(entries[i])
+		if entry == "" {
+			continue
+		}
+		normalized, err := NormalizeIP(entry)
+		if err != nil {
+			return "", fmt.Errorf("X-Forwarded-For entry %q: %w", entry, err)
+		}
+		ip = normalized
+		if !IsTrustedProxy(net.ParseIP(ip), trustedProxies) {
+			return ip, nil
+		}
+	}
+
+	if ip == "" {
+		return "", ErrNoClientIP
+	}
+	return ip, nil
+}
+
+// IsTrustedProxy reports whether ip falls inside any of the trusted networks.
+// A nil ip is never trusted.
+func IsTrustedProxy(ip net.IP, trustedProxies []net.IPNet) bool {
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
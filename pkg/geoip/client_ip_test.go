@@ -0,0 +1,33 @@
+package geoip
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIsTrustedProxy(t *testing.T) {
+	var networks []net.IPNet
+	for _, cidr := range []string{"10.0.0.0/8", "2001:db8::/32"} {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			t.Fatalf("ParseCIDR(%q): %v", cidr, err)
+		}
+		networks = append(networks, *network)
+	}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.1.2.3", true},
+		{"2001:db8::1", true},
+		{"81.2.69.142", false},
+		{"2001:db9::1", false},
+		{"not-an-ip", false},
+	}
+	for _, tt := range tests {
+		if got := IsTrustedProxy(net.ParseIP(tt.ip), networks); got != tt.want {
+			t.Errorf("IsTrustedProxy(%q) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
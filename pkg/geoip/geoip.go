@@ -1,10 +1,16 @@
 package geoip
 
 import (
+	"errors"
 	"fmt"
 	"net"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/oschwald/geoip2-golang"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/internal/ipaddr"
 )
 
 // GeoData contains geographic information derived from an IP address.
@@ -17,13 +23,73 @@ type GeoData struct {
 	Latitude      float64 // City centroid latitude (ephemeral use only)
 	Longitude     float64 // City centroid longitude (ephemeral use only)
 	Timezone      string  // IANA timezone (e.g., "Europe/Istanbul")
+
+	// CountryConfidence is MaxMind's confidence (0-100) that CountryCode is correct.
+	// Only GeoIP2 Enterprise databases provide it; zero means unavailable.
+	CountryConfidence uint8
+
+	// ConnectionType is MaxMind's connection type (e.g., "Cellular", "Cable/DSL").
+	// Provided by GeoIP2 Enterprise or a loaded Connection-Type database; empty otherwise.
+	ConnectionType string
+
+	// AccuracyRadius is MaxMind's radius (km) around Latitude/Longitude within
+	// which the IP is likely located. Zero means unavailable.
+	AccuracyRadius uint16
+
+	// Subdivision is the ISO 3166-2 code of the largest subdivision
+	// (state, province, region) without the country prefix (e.g., "CA" for
+	// California). Empty when unknown.
+	Subdivision string
+
+	// PostalCode is the postal code associated with the IP (ephemeral use only).
+	// Coverage varies by country; empty when unknown.
+	PostalCode string
+}
+
+// AnonymousInfo contains anonymizer flags from a GeoIP2 Anonymous-IP database.
+type AnonymousInfo struct {
+	IsAnonymous        bool // Any of the flags below is set
+	IsAnonymousVPN     bool // Known commercial VPN provider
+	IsHostingProvider  bool // Hosting or VPS provider
+	IsPublicProxy      bool // Public proxy
+	IsResidentialProxy bool // Residential proxy network
+	IsTorExitNode      bool // Tor exit node
 }
 
+// ErrNoAnonymousDB is returned by GetAnonymousInfo when no Anonymous-IP database is loaded.
+var ErrNoAnonymousDB = errors.New("anonymous IP database not loaded")
+
+// ErrInvalidIP is returned for input that does not parse as an IP address.
+// The message deliberately omits the input, so errors are safe to log.
+var ErrInvalidIP = ipaddr.ErrInvalid
+
 // Service provides GeoIP and ASN lookup functionality using MaxMind databases.
 // It wraps the MaxMind GeoIP2 reader for city and ASN lookups.
+//
+// All methods are safe for concurrent use, including Reload while lookups
+// are in flight.
 type Service struct {
+	// mu guards the readers below so Reload never exposes a closed reader
+	mu sync.RWMutex
+
 	cityReader *geoip2.Reader
 	asnReader  *geoip2.Reader
+
+	// isEnterprise reports whether the city database is a GeoIP2 Enterprise
+	// database, which adds confidence scores to the standard city data.
+	isEnterprise bool
+
+	// connectionTypeReader is an optional GeoIP2 Connection-Type database.
+	connectionTypeReader *geoip2.Reader
+
+	// anonymousReader is an optional GeoIP2 Anonymous-IP database.
+	anonymousReader *geoip2.Reader
+
+	// reloadErr is the result of the last WatchAndReload attempt.
+	reloadErr error
+
+	// cache is an optional LRU cache for GetLocation and GetASN (nil = disabled).
+	cache *lookupCache
 }
 
 // NewService creates a new GeoIP service with the specified database files.
@@ -47,20 +113,144 @@ func NewService(cityDBPath, asnDBPath string) (*Service, error) {
 	}
 
 	return &Service{
-		cityReader: cityReader,
-		asnReader:  asnReader,
+		cityReader:   cityReader,
+		asnReader:    asnReader,
+		isEnterprise: strings.Contains(cityReader.Metadata().DatabaseType, "Enterprise"),
 	}, nil
 }
 
+// NewServiceWithCache creates a GeoIP service whose GetLocation and GetASN
+// results are cached in an LRU of up to cacheSize entries (location and ASN
+// results share it), each kept for DefaultCacheTTL.
+//
+// The engine looks up the current IP and, for stateful rules, the previous
+// login's prefix on every request; both recur constantly (returning users,
+// shared NAT and carrier gateways), so most lookups become cache hits. Check
+// the achieved hit rate with CacheStats and size the cache accordingly;
+// each entry costs a few hundred bytes. BenchmarkServiceGetLocation compares
+// cached and uncached lookups against your own databases; a hit costs about
+// as much as a map read, and an LRU smaller than the working set of IPs
+// yields almost no hits.
+func NewServiceWithCache(cityDBPath, asnDBPath string, cacheSize int) (*Service, error) {
+	s, err := NewService(cityDBPath, asnDBPath)
+	if err != nil {
+		return nil, err
+	}
+	s.EnableCache(cacheSize, DefaultCacheTTL)
+	return s, nil
+}
+
+// EnableCache caches up to size successful GetLocation and GetASN results for
+// ttl. Lookup errors are never cached. A size below 1 disables the cache.
+// Call it before the service is in use.
+func (s *Service) EnableCache(size int, ttl time.Duration) {
+	if size < 1 {
+		s.cache = nil
+		return
+	}
+	s.cache = newLookupCache(size, ttl)
+}
+
+// CacheStats returns lookup cache counters; all zero when caching is disabled.
+func (s *Service) CacheStats() CacheStats {
+	if s.cache == nil {
+		return CacheStats{}
+	}
+	return s.cache.stats()
+}
+
 // Close releases the database file handles.
 // Should be called when the service is no longer needed.
 func (s *Service) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.cityReader != nil {
 		s.cityReader.Close()
 	}
 	if s.asnReader != nil {
 		s.asnReader.Close()
 	}
+	if s.connectionTypeReader != nil {
+		s.connectionTypeReader.Close()
+	}
+	if s.anonymousReader != nil {
+		s.anonymousReader.Close()
+	}
+}
+
+// OpenConnectionTypeDB loads an optional GeoIP2 Connection-Type database.
+//
+// Once loaded, GetLocation fills GeoData.ConnectionType for non-Enterprise
+// city databases (Enterprise databases already include it).
+func (s *Service) OpenConnectionTypeDB(path string) error {
+	reader, err := geoip2.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open connection type database: %v", err)
+	}
+
+	s.mu.Lock()
+	old := s.connectionTypeReader
+	s.connectionTypeReader = reader
+	s.mu.Unlock()
+
+	if old != nil {
+		old.Close()
+	}
+	s.purgeCache()
+	return nil
+}
+
+// OpenAnonymousIPDB loads an optional GeoIP2 Anonymous-IP database.
+//
+// Once loaded, GetAnonymousInfo reports VPN, proxy, hosting, and Tor flags.
+// Setups with only the city and ASN databases are unaffected.
+func (s *Service) OpenAnonymousIPDB(path string) error {
+	reader, err := geoip2.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open anonymous IP database: %v", err)
+	}
+
+	s.mu.Lock()
+	old := s.anonymousReader
+	s.anonymousReader = reader
+	s.mu.Unlock()
+
+	if old != nil {
+		old.Close()
+	}
+	return nil
+}
+
+// GetAnonymousInfo returns anonymizer flags for an IP address.
+// Returns ErrNoAnonymousDB if OpenAnonymousIPDB has not been called.
+func (s *Service) GetAnonymousInfo(ipAddress string) (AnonymousInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.anonymousReader == nil {
+		return AnonymousInfo{}, ErrNoAnonymousDB
+	}
+
+	normalized, err := NormalizeIP(ipAddress)
+	if err != nil {
+		return AnonymousInfo{}, err
+	}
+	ip := net.ParseIP(normalized)
+
+	record, err := s.anonymousReader.AnonymousIP(ip)
+	if err != nil {
+		return AnonymousInfo{}, err
+	}
+
+	return AnonymousInfo{
+		IsAnonymous:        record.IsAnonymous,
+		IsAnonymousVPN:     record.IsAnonymousVPN,
+		IsHostingProvider:  record.IsHostingProvider,
+		IsPublicProxy:      record.IsPublicProxy,
+		IsResidentialProxy: record.IsResidentialProxy,
+		IsTorExitNode:      record.IsTorExitNode,
+	}, nil
 }
 
 // GetLocation returns geographic data for an IP address.
@@ -69,10 +259,49 @@ func (s *Service) Close() {
 //
 // Privacy Note: Coordinates should never be persisted. Store only
 // the CityGeonameID and CountryCode for privacy compliance.
+//
+// Returns ErrPrivateIP for private or reserved addresses (see IsPrivateIP).
 func (s *Service) GetLocation(ipAddress string) (*GeoData, error) {
+	ipAddress, err := NormalizeIP(ipAddress)
+	if err != nil {
+		return nil, err
+	}
+	if s.cache == nil {
+		return s.lookupLocation(ipAddress)
+	}
+
+	key := "loc:" + ipAddress
+	if cached, ok := s.cache.get(key); ok {
+		data := *cached.(*GeoData)
+		return &data, nil
+	}
+
+	generation := s.cache.currentGeneration()
+	data, err := s.lookupLocation(ipAddress)
+	if err != nil {
+		return nil, err
+	}
+	cached := *data
+	s.cache.put(key, &cached, generation)
+	return data, nil
+}
+
+// lookupLocation reads location data from the databases.
+// ipAddress must already be normalized (see NormalizeIP).
+func (s *Service) lookupLocation(ipAddress string) (*GeoData, error) {
 	ip := net.ParseIP(ipAddress)
 	if ip == nil {
-		return nil, fmt.Errorf("invalid IP address: %s", ipAddress)
+		return nil, ErrInvalidIP
+	}
+	if IsPrivateIP(ipAddress) {
+		return nil, ErrPrivateIP
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.isEnterprise {
+		return s.getEnterpriseLocation(ip)
 	}
 
 	record, err := s.cityReader.City(ip)
@@ -80,23 +309,98 @@ func (s *Service) GetLocation(ipAddress string) (*GeoData, error) {
 		return nil, err
 	}
 
-	return &GeoData{
-		CountryCode:   record.Country.IsoCode,
-		CityName:      record.City.Names["en"],
-		CityGeonameID: uint(record.City.GeoNameID),
-		Latitude:      record.Location.Latitude,
-		Longitude:     record.Location.Longitude,
-		Timezone:      record.Location.TimeZone,
-	}, nil
+	data := &GeoData{
+		CountryCode:    record.Country.IsoCode,
+		CityName:       record.City.Names["en"],
+		CityGeonameID:  uint(record.City.GeoNameID),
+		Latitude:       record.Location.Latitude,
+		Longitude:      record.Location.Longitude,
+		Timezone:       record.Location.TimeZone,
+		AccuracyRadius: record.Location.AccuracyRadius,
+		PostalCode:     record.Postal.Code,
+	}
+	if len(record.Subdivisions) > 0 {
+		data.Subdivision = record.Subdivisions[0].IsoCode
+	}
+
+	// Optional database: a failed lookup leaves ConnectionType empty
+	if s.connectionTypeReader != nil {
+		if conn, err := s.connectionTypeReader.ConnectionType(ip); err == nil {
+			data.ConnectionType = conn.ConnectionType
+		}
+	}
+
+	return data, nil
+}
+
+// getEnterpriseLocation performs a GeoIP2 Enterprise lookup.
+// Enterprise records carry the same fields as City plus confidence scores.
+// The caller must hold s.mu.
+func (s *Service) getEnterpriseLocation(ip net.IP) (*GeoData, error) {
+	record, err := s.cityReader.Enterprise(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &GeoData{
+		CountryCode:       record.Country.IsoCode,
+		CityName:          record.City.Names["en"],
+		CityGeonameID:     record.City.GeoNameID,
+		Latitude:          record.Location.Latitude,
+		Longitude:         record.Location.Longitude,
+		Timezone:          record.Location.TimeZone,
+		CountryConfidence: record.Country.Confidence,
+		ConnectionType:    record.Traits.ConnectionType,
+		AccuracyRadius:    record.Location.AccuracyRadius,
+		PostalCode:        record.Postal.Code,
+	}
+	if len(record.Subdivisions) > 0 {
+		data.Subdivision = record.Subdivisions[0].IsoCode
+	}
+
+	return data, nil
 }
 
 // GetASN returns the Autonomous System Number and organization name for an IP.
 // ASN data helps identify the network operator (ISP, cloud provider, etc.).
+// Returns ErrPrivateIP for private or reserved addresses (see IsPrivateIP).
 func (s *Service) GetASN(ipAddress string) (uint, string, error) {
+	ipAddress, err := NormalizeIP(ipAddress)
+	if err != nil {
+		return 0, "", err
+	}
+	if s.cache == nil {
+		return s.lookupASN(ipAddress)
+	}
+
+	key := "asn:" + ipAddress
+	if cached, ok := s.cache.get(key); ok {
+		entry := cached.(asnEntry)
+		return entry.number, entry.org, nil
+	}
+
+	generation := s.cache.currentGeneration()
+	number, org, err := s.lookupASN(ipAddress)
+	if err != nil {
+		return 0, "", err
+	}
+	s.cache.put(key, asnEntry{number: number, org: org}, generation)
+	return number, org, nil
+}
+
+// lookupASN reads ASN data from the database.
+// ipAddress must already be normalized (see NormalizeIP).
+func (s *Service) lookupASN(ipAddress string) (uint, string, error) {
 	ip := net.ParseIP(ipAddress)
 	if ip == nil {
-		return 0, "", fmt.Errorf("invalid IP address: %s", ipAddress)
+		return 0, "", ErrInvalidIP
 	}
+	if IsPrivateIP(ipAddress) {
+		return 0, "", ErrPrivateIP
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	record, err := s.asnReader.ASN(ip)
 	if err != nil {
@@ -104,4 +408,4 @@ func (s *Service) GetASN(ipAddress string) (uint, string, error) {
 	}
 
 	return uint(record.AutonomousSystemNumber), record.AutonomousSystemOrganization, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,25 @@
+package geoip
+
+import "github.com/gokaycavdar/go-geoguard/pkg/internal/ipaddr"
+
+// NormalizeIP returns the canonical text form of an IP address, so the same
+// client always produces the same lookup and the same masked prefix.
+//
+// Accepted forms:
+//   - Plain IPv4 and IPv6: "88.230.100.50", "2001:DB8::1" -> "2001:db8::1"
+//   - IPv4-mapped IPv6: "::ffff:88.230.100.50" -> "88.230.100.50"
+//   - Bracketed IPv6: "[2001:db8::1]" -> "2001:db8::1"
+//   - Host and port, as in http.Request.RemoteAddr:
+//     "88.230.100.50:54321" -> "88.230.100.50", "[2001:db8::1]:443" -> "2001:db8::1"
+//   - Zone identifiers are dropped: "fe80::1%eth0" -> "fe80::1"
+//   - Surrounding whitespace is ignored
+//
+// Malformed input returns ErrInvalidIP: hostnames ("example.com:443"),
+// empty strings, CIDRs, and host:port pairs whose port is missing or not a
+// number in 0-65535. An unbracketed IPv6 address is never split on its last
+// colon, so "2001:db8::1:443" is the address 2001:db8::1:443, not a port.
+//
+// The lookup methods and rules.MaskIP normalize their input with it.
+func NormalizeIP(s string) (string, error) {
+	return ipaddr.Normalize(s)
+}
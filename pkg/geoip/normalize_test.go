@@ -0,0 +1,58 @@
+package geoip
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeIP(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"88.230.100.50", "88.230.100.50"},
+		{"  88.230.100.50\t", "88.230.100.50"},
+		{"2001:DB8::1", "2001:db8::1"},
+		{"2001:db8:0:0:0:0:0:1", "2001:db8::1"},
+		{"::ffff:88.230.100.50", "88.230.100.50"},
+		{"[2001:db8::1]", "2001:db8::1"},
+		{"[::ffff:88.230.100.50]", "88.230.100.50"},
+		{"fe80::1%eth0", "fe80::1"},
+		{"[fe80::1%eth0]:443", "fe80::1"},
+		{"88.230.100.50:54321", "88.230.100.50"},
+		{"[2001:db8::1]:443", "2001:db8::1"},
+		{"2001:db8::1:443", "2001:db8::1:443"}, // Unbracketed IPv6 is never split
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := NormalizeIP(tt.in)
+			if err != nil {
+				t.Fatalf("NormalizeIP(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeIP(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeIPInvalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"example.com",
+		"example.com:443",
+		"88.230.100.0/24",
+		"88.230.100.50:",
+		"88.230.100.50:http",
+		"88.230.100.50:65536",
+		"[2001:db8::1]:-1",
+		"256.1.1.1",
+	} {
+		t.Run(in, func(t *testing.T) {
+			got, err := NormalizeIP(in)
+			if !errors.Is(err, ErrInvalidIP) {
+				t.Errorf("NormalizeIP(%q) = %q, %v; want ErrInvalidIP", in, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package geoip
+
+import (
+	"errors"
+	"net"
+)
+
+// ErrPrivateIP is returned by lookups for private or reserved addresses,
+// which GeoIP databases cannot locate. Behind a misconfigured proxy the
+// client IP is often such an address (e.g., the proxy's own 10.x address).
+var ErrPrivateIP = errors.New("private or reserved IP address")
+
+// sharedAddressSpace is RFC 6598 carrier-grade NAT space (100.64.0.0/10).
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPrivateIP reports whether ip is a private or reserved address that
+// cannot be geolocated:
+//   - Private: 10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16, fc00::/7
+//   - Loopback: 127.0.0.0/8, ::1
+//   - Link-local: 169.254.0.0/16, fe80::/10
+//   - Carrier-grade NAT: 100.64.0.0/10
+//   - Unspecified: 0.0.0.0, ::
+//
+// The input is normalized first (see NormalizeIP).
+// Returns false for unparseable input.
+func IsPrivateIP(ip string) bool {
+	normalized, err := NormalizeIP(ip)
+	if err != nil {
+		return false
+	}
+	parsed := net.ParseIP(normalized)
+	return parsed.IsPrivate() || parsed.IsLoopback() || parsed.IsLinkLocalUnicast() ||
+		parsed.IsUnspecified() || sharedAddressSpace.Contains(parsed)
+}
@@ -0,0 +1,44 @@
+package geoip
+
+// Provider is the GeoIP backend the engine uses for lookups.
+//
+// Service implements it with MaxMind databases. Other databases (e.g.,
+// IP2Location) or test stubs can be plugged into engine.New by
+// implementing these two methods.
+//
+// Implementations must be safe for concurrent use. Returned GeoData is used
+// ephemerally and must not be retained by the engine or rules.
+type Provider interface {
+	// Lookup returns geographic data for an IP address.
+	Lookup(ip string) (*GeoData, error)
+
+	// LookupASN returns the Autonomous System Number and organization name.
+	LookupASN(ip string) (uint, string, error)
+}
+
+// AnonymousProvider is an optional interface for providers that report
+// anonymizer flags (VPN, proxy, hosting, Tor).
+//
+// The engine detects it via type assertion and fills GeoContext.AnonymousIP
+// when the lookup succeeds.
+type AnonymousProvider interface {
+	Provider
+
+	// LookupAnonymous returns anonymizer flags for an IP address.
+	LookupAnonymous(ip string) (AnonymousInfo, error)
+}
+
+// Lookup is GetLocation. Implements Provider interface.
+func (s *Service) Lookup(ip string) (*GeoData, error) {
+	return s.GetLocation(ip)
+}
+
+// LookupASN is GetASN. Implements Provider interface.
+func (s *Service) LookupASN(ip string) (uint, string, error) {
+	return s.GetASN(ip)
+}
+
+// LookupAnonymous is GetAnonymousInfo. Implements AnonymousProvider interface.
+func (s *Service) LookupAnonymous(ip string) (AnonymousInfo, error) {
+	return s.GetAnonymousInfo(ip)
+}
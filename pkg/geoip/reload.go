@@ -0,0 +1,128 @@
+package geoip
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// DefaultReloadInterval is the WatchAndReload polling interval used when the
+// given interval is not positive.
+const DefaultReloadInterval = time.Hour
+
+// Reload replaces the city and ASN databases without downtime.
+//
+// The new databases are opened first; on error the current ones stay in
+// use. The readers are then swapped under a lock, so concurrent lookups see
+// either the old or the new databases and never a closed reader, and the
+// old readers are closed. Cached lookups are purged, and lookups that were
+// in flight during the swap do not repopulate the cache.
+//
+// Optional databases (Connection-Type, Anonymous-IP) are kept; reload them
+// with OpenConnectionTypeDB and OpenAnonymousIPDB.
+func (s *Service) Reload(cityDBPath, asnDBPath string) error {
+	cityReader, err := geoip2.Open(cityDBPath)
+	if err != nil {
+		return fmt.Errorf("failed to open city database: %v", err)
+	}
+
+	asnReader, err := geoip2.Open(asnDBPath)
+	if err != nil {
+		cityReader.Close()
+		return fmt.Errorf("failed to open ASN database: %v", err)
+	}
+
+	s.mu.Lock()
+	oldCity, oldASN := s.cityReader, s.asnReader
+	s.cityReader = cityReader
+	s.asnReader = asnReader
+	s.isEnterprise = strings.Contains(cityReader.Metadata().DatabaseType, "Enterprise")
+	s.mu.Unlock()
+
+	// No lookup holds the old readers once the write lock was acquired
+	if oldCity != nil {
+		oldCity.Close()
+	}
+	if oldASN != nil {
+		oldASN.Close()
+	}
+	s.purgeCache()
+	return nil
+}
+
+// WatchAndReload polls the database files every interval and calls Reload
+// when either modification time changes (e.g., after a geoipupdate run).
+//
+// A failed reload (such as a file caught mid-write) keeps the current
+// databases and is retried on the next poll; the error is available from
+// LastReloadError. Call the returned function to stop watching.
+// An interval of zero or less uses DefaultReloadInterval.
+//
+// Example:
+//
+//	stop := geoService.WatchAndReload(cityPath, asnPath, time.Hour)
+//	defer stop()
+func (s *Service) WatchAndReload(cityDBPath, asnDBPath string, interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+
+	done := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		lastCity, lastASN := modTime(cityDBPath), modTime(asnDBPath)
+		for {
+			select {
+			case <-ticker.C:
+				city, asn := modTime(cityDBPath), modTime(asnDBPath)
+				if city.Equal(lastCity) && asn.Equal(lastASN) {
+					continue
+				}
+
+				err := s.Reload(cityDBPath, asnDBPath)
+				s.mu.Lock()
+				s.reloadErr = err
+				s.mu.Unlock()
+				if err == nil {
+					lastCity, lastASN = city, asn
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// LastReloadError returns the error from the most recent WatchAndReload
+// reload attempt, or nil if it succeeded.
+func (s *Service) LastReloadError() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.reloadErr
+}
+
+// purgeCache drops cached lookups after the databases change.
+func (s *Service) purgeCache() {
+	if s.cache != nil {
+		s.cache.purge()
+	}
+}
+
+// modTime returns a file's modification time, or the zero time if it cannot be read.
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,225 @@
+// Package httpmw runs GeoGuard as net/http middleware.
+//
+// It works with any router built on http.Handler (net/http, chi, gorilla)
+// and, through their adapters, with echo and gin. Only the standard library
+// is used, so importing it adds no framework dependencies.
+package httpmw
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/engine"
+	"github.com/gokaycavdar/go-geoguard/pkg/geoip"
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+	"github.com/gokaycavdar/go-geoguard/pkg/storage"
+)
+
+// Options configures Middleware. Only UserID is required; Middleware panics
+// without it.
+type Options struct {
+	// UserID returns the user attempting to log in (required).
+	// Requests for which it returns "" are passed through unvalidated.
+	UserID func(r *http.Request) string
+
+	// TrustedProxies are the networks of your load balancers and reverse
+	// proxies. When the direct peer (RemoteAddr) is inside one, the client
+	// IP is taken from proxy headers (see geoip.ClientIPFromHeaders);
+	// otherwise RemoteAddr is used. Empty = always use RemoteAddr.
+	TrustedProxies []net.IPNet
+
+	// ClientIP overrides client IP extraction; TrustedProxies is then ignored.
+	ClientIP func(r *http.Request) string
+
+	// Signals returns the frontend-derived signals (GPS, timezone), e.g.
+	// from headers set by your login page (see HeaderSignals). Nil = none.
+	Signals func(r *http.Request) ClientSignals
+
+	// BlockThreshold short-circuits requests scoring at or above it
+	// (0 = disabled). Results with RiskResult.IsBlocked set (see
+	// engine.WithBlockThreshold) are always short-circuited.
+	BlockThreshold int
+
+	// Blocked writes the response for short-circuited requests; the result
+	// is available via ResultFromContext. Nil = 403 Forbidden.
+	Blocked http.Handler
+
+	// Error handles Validate failures. Nil = 500 Internal Server Error
+	// (fail closed); call the next handler here to fail open instead.
+	Error func(w http.ResponseWriter, r *http.Request, err error)
+
+	// Store, if set, saves the LoginRecord of every request that is not
+	// short-circuited. Leave nil to save it yourself after authentication
+	// (see RecordFromContext), e.g. with the login outcome set.
+	Store storage.HistoryStore
+}
+
+// ClientSignals are the frontend-derived inputs for engine.Input.
+type ClientSignals struct {
+	Latitude  float64 // Device GPS latitude (0 with Longitude 0 = not provided)
+	Longitude float64 // Device GPS longitude
+	Timezone  string  // IANA timezone, e.g. "Europe/Istanbul"
+}
+
+// HeaderSignals reads ClientSignals from request headers. Empty header
+// names are skipped; unparseable coordinates are treated as not provided.
+//
+// Example:
+//
+//	opts.Signals = httpmw.HeaderSignals("X-Geo-Lat", "X-Geo-Lon", "X-Timezone")
+func HeaderSignals(latitudeHeader, longitudeHeader, timezoneHeader string) func(r *http.Request) ClientSignals {
+	return func(r *http.Request) ClientSignals {
+		var signals ClientSignals
+		if timezoneHeader != "" {
+			signals.Timezone = strings.TrimSpace(r.Header.Get(timezoneHeader))
+		}
+		if latitudeHeader == "" || longitudeHeader == "" {
+			return signals
+		}
+
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(r.Header.Get(latitudeHeader)), 64)
+		lon, lonErr := strconv.ParseFloat(strings.TrimSpace(r.Header.Get(longitudeHeader)), 64)
+		if latErr == nil && lonErr == nil && lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180 {
+			signals.Latitude, signals.Longitude = lat, lon
+		}
+		return signals
+	}
+}
+
+// contextKey is unexported so only this package can set the values.
+type contextKey int
+
+const (
+	resultKey contextKey = iota
+	recordKey
+)
+
+// ResultFromContext returns the RiskResult stored by Middleware.
+// Returns false for requests that were not validated.
+func ResultFromContext(ctx context.Context) (*models.RiskResult, bool) {
+	result, ok := ctx.Value(resultKey).(*models.RiskResult)
+	return result, ok
+}
+
+// RecordFromContext returns the privacy-safe LoginRecord produced by
+// Middleware, for saving after authentication. Returns false for requests
+// that were not validated.
+func RecordFromContext(ctx context.Context) (*models.LoginRecord, bool) {
+	record, ok := ctx.Value(recordKey).(*models.LoginRecord)
+	return record, ok
+}
+
+// Middleware returns middleware that validates each request with guard.
+//
+// Behavior:
+//   - Builds engine.Input from the request: client IP (see Options),
+//     User-Agent, Accept-Language, and the optional ClientSignals
+//   - Runs guard.ValidateContext bounded by the request context
+//   - Stores the RiskResult and LoginRecord in the request context
+//   - Short-circuits blocked requests (see Options.BlockThreshold);
+//     otherwise saves the record (if Options.Store is set) and calls next
+//
+// Usage:
+//
+//	mw := httpmw.Middleware(guard, httpmw.Options{
+//	    UserID:         func(r *http.Request) string { return r.FormValue("user_id") },
+//	    BlockThreshold: 100,
+//	})
+//	mux.Handle("/login", mw(loginHandler))
+//
+// A nil opts.UserID is a programmer error, not a runtime condition:
+// Middleware panics when it is called, so a misconfigured server fails at
+// startup rather than on the first request.
+//
+// Limitations:
+//   - UserID runs before next; reading a form or JSON body there consumes
+//     it unless you buffer it
+func Middleware(guard *engine.GeoGuard, opts Options) func(http.Handler) http.Handler {
+	if opts.UserID == nil {
+		panic("httpmw: Options.UserID is required")
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID := opts.UserID(r)
+			if userID == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			input := engine.Input{
+				UserID:         userID,
+				IPAddress:      opts.clientIP(r),
+				UserAgent:      r.UserAgent(),
+				AcceptLanguage: r.Header.Get("Accept-Language"),
+			}
+			if opts.Signals != nil {
+				signals := opts.Signals(r)
+				input.Latitude = signals.Latitude
+				input.Longitude = signals.Longitude
+				input.ClientTimezone = signals.Timezone
+			}
+
+			result, record, err := guard.ValidateContext(r.Context(), input)
+			if err != nil {
+				opts.handleError(w, r, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), resultKey, result)
+			ctx = context.WithValue(ctx, recordKey, record)
+			r = r.WithContext(ctx)
+
+			if result.IsBlocked || (opts.BlockThreshold > 0 && result.TotalRiskScore >= opts.BlockThreshold) {
+				opts.blocked(w, r)
+				return
+			}
+
+			if opts.Store != nil {
+				if err := opts.Store.SaveRecord(record); err != nil {
+					opts.handleError(w, r, err)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP extracts the client IP as configured; see Options.TrustedProxies.
+func (o *Options) clientIP(r *http.Request) string {
+	if o.ClientIP != nil {
+		return o.ClientIP(r)
+	}
+	if len(o.TrustedProxies) == 0 {
+		return r.RemoteAddr
+	}
+
+	peer, err := geoip.NormalizeIP(r.RemoteAddr)
+	if err != nil || !geoip.IsTrustedProxy(net.ParseIP(peer), o.TrustedProxies) {
+		return r.RemoteAddr
+	}
+	if ip, err := geoip.ClientIPFromHeaders(r.Header, o.TrustedProxies); err == nil {
+		return ip
+	}
+	return r.RemoteAddr
+}
+
+func (o *Options) blocked(w http.ResponseWriter, r *http.Request) {
+	if o.Blocked != nil {
+		o.Blocked.ServeHTTP(w, r)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+}
+
+func (o *Options) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if o.Error != nil {
+		o.Error(w, r, err)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
@@ -0,0 +1,149 @@
+package httpmw
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/engine"
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+	"github.com/gokaycavdar/go-geoguard/pkg/storage"
+)
+
+func userFromHeader(r *http.Request) string {
+	return r.Header.Get("X-User")
+}
+
+// serve runs one request through Middleware and returns the request next
+// received (nil if next was not called).
+func serve(t *testing.T, guard *engine.GeoGuard, opts Options, r *http.Request) (*httptest.ResponseRecorder, *http.Request) {
+	t.Helper()
+	var reached *http.Request
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = r
+	})
+
+	w := httptest.NewRecorder()
+	Middleware(guard, opts)(next).ServeHTTP(w, r)
+	return w, reached
+}
+
+func TestMiddlewarePassesThroughWithoutUserID(t *testing.T) {
+	guard := engine.NewWithoutGeo(storage.NewMemoryStore())
+	r := httptest.NewRequest(http.MethodPost, "/login", nil)
+
+	w, reached := serve(t, guard, Options{UserID: userFromHeader}, r)
+	if reached == nil {
+		t.Fatal("next was not called")
+	}
+	if _, ok := ResultFromContext(reached.Context()); ok {
+		t.Error("request without a user ID was validated")
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestMiddlewareBlocks(t *testing.T) {
+	guard := engine.NewWithoutGeo(storage.NewMemoryStore())
+	guard.AddRule(rules.Func("Always Risky", "test rule", func(input, last *models.LoginRecord) (int, error) {
+		return 80, nil
+	}))
+
+	tests := []struct {
+		name      string
+		threshold int
+		blocked   bool
+	}{
+		{"below threshold", 100, false},
+		{"at threshold", 80, true},
+		{"disabled", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var blockedScore int
+			opts := Options{
+				UserID:         userFromHeader,
+				BlockThreshold: tt.threshold,
+				Blocked: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					result, _ := ResultFromContext(r.Context())
+					blockedScore = result.TotalRiskScore
+					w.WriteHeader(http.StatusTooManyRequests)
+				}),
+			}
+			r := httptest.NewRequest(http.MethodPost, "/login", nil)
+			r.Header.Set("X-User", "alice")
+
+			w, reached := serve(t, guard, opts, r)
+			if blocked := reached == nil; blocked != tt.blocked {
+				t.Fatalf("blocked = %v, want %v", blocked, tt.blocked)
+			}
+			if tt.blocked && (w.Code != http.StatusTooManyRequests || blockedScore != 80) {
+				t.Errorf("status = %d, score = %d; want Blocked handler with score 80", w.Code, blockedScore)
+			}
+		})
+	}
+}
+
+func TestMiddlewareDefaultBlockedResponse(t *testing.T) {
+	guard := engine.NewWithoutGeo(storage.NewMemoryStore(), engine.WithBlockThreshold(50))
+	guard.AddRule(rules.Func("Always Risky", "test rule", func(input, last *models.LoginRecord) (int, error) {
+		return 80, nil
+	}))
+	r := httptest.NewRequest(http.MethodPost, "/login", nil)
+	r.Header.Set("X-User", "alice")
+
+	w, reached := serve(t, guard, Options{UserID: userFromHeader}, r)
+	if reached != nil || w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, next called = %v; want 403 without next", w.Code, reached != nil)
+	}
+}
+
+func TestMiddlewareClientIP(t *testing.T) {
+	_, proxies, err := net.ParseCIDR("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	guard := engine.NewWithoutGeo(storage.NewMemoryStore())
+	opts := Options{UserID: userFromHeader, TrustedProxies: []net.IPNet{*proxies}}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		wantPrefix string
+	}{
+		{"trusted proxy", "10.0.0.1:4711", "81.2.69.0/24"},
+		{"untrusted peer", "192.0.2.10:4711", "192.0.2.0/24"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/login", nil)
+			r.RemoteAddr = tt.remoteAddr
+			r.Header.Set("X-User", "alice")
+			r.Header.Set("X-Forwarded-For", "81.2.69.142")
+
+			_, reached := serve(t, guard, opts, r)
+			if reached == nil {
+				t.Fatal("next was not called")
+			}
+			record, ok := RecordFromContext(reached.Context())
+			if !ok {
+				t.Fatal("no LoginRecord in context")
+			}
+			if record.MaskedIPPrefix != tt.wantPrefix {
+				t.Errorf("MaskedIPPrefix = %q, want %q", record.MaskedIPPrefix, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestMiddlewarePanicsWithoutUserID(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Middleware did not panic with a nil UserID")
+		}
+	}()
+	Middleware(engine.NewWithoutGeo(storage.NewMemoryStore()), Options{})
+}
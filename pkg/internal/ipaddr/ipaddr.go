@@ -0,0 +1,54 @@
+// Package ipaddr canonicalizes IP address strings for the geoip and rules
+// packages, so lookups and masking agree without the rules depending on the
+// GeoIP service. Its API is re-exported as geoip.NormalizeIP.
+package ipaddr
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalid is returned for input that does not parse as an IP address
+// (exported as geoip.ErrInvalidIP). The message deliberately omits the
+// input, so errors are safe to log.
+var ErrInvalid = errors.New("invalid IP address")
+
+// Normalize returns the canonical text form of an IP address.
+// See geoip.NormalizeIP for the accepted forms.
+func Normalize(s string) (string, error) {
+	s = strings.TrimSpace(s)
+
+	// Bare addresses first; SplitHostPort would reject unbracketed IPv6
+	if addr, ok := parseAddr(s); ok {
+		return addr, nil
+	}
+
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		return "", ErrInvalid
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return "", ErrInvalid
+	}
+	if addr, ok := parseAddr(host); ok {
+		return addr, nil
+	}
+	return "", ErrInvalid
+}
+
+// parseAddr parses an optionally bracketed address and returns its
+// canonical form without zone, with IPv4-mapped addresses unmapped.
+func parseAddr(s string) (string, bool) {
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		s = s[1 : len(s)-1]
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return "", false
+	}
+	return addr.WithZone("").Unmap().String(), true
+}
@@ -0,0 +1,140 @@
+// Package metrics exports GeoGuard evaluation metrics to Prometheus.
+//
+// It is a separate Go module so the prometheus/client_golang dependency
+// stays out of the core library: only applications that import this package
+// pull it in.
+//
+// Usage:
+//
+//	collector := metrics.NewCollector()
+//	prometheus.MustRegister(collector)
+//	guard.SetObserver(collector.Observe)
+//
+// Metrics (all prefixed with Namespace, "geoguard"):
+//
+//	geoguard_evaluations_total{level}          Counter   Validate calls by risk level
+//	                                                     ("low", "medium", "high", "critical", or "error")
+//	geoguard_blocked_total                     Counter   Evaluations with RiskResult.IsBlocked set
+//	geoguard_rule_triggers_total{rule}         Counter   Listed violations by rule name
+//	geoguard_rule_skips_total{rule}            Counter   Rule errors and skips by rule name
+//	geoguard_risk_score                        Histogram TotalRiskScore (buckets: DefaultScoreBuckets)
+//	geoguard_evaluation_duration_seconds       Histogram Validate latency (buckets: prometheus.DefBuckets)
+//
+// Labels are bounded: level has five values and rule takes the registered
+// rule names. No user ID, IP prefix, or country is used as a label, which
+// keeps cardinality low and metrics free of personal data.
+package metrics
+
+import (
+	"github.com/gokaycavdar/go-geoguard/pkg/engine"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Namespace prefixes every metric name.
+const Namespace = "geoguard"
+
+// errorLevel is the level label for Validate calls that returned an error.
+const errorLevel = "error"
+
+// DefaultScoreBuckets are the geoguard_risk_score histogram buckets. They
+// bracket the default risk level thresholds (50, 100, 150).
+var DefaultScoreBuckets = []float64{0, 10, 25, 50, 75, 100, 150, 200, 300}
+
+// Collector turns engine observations into Prometheus metrics.
+// It implements prometheus.Collector and is safe for concurrent use.
+type Collector struct {
+	evaluations  *prometheus.CounterVec
+	blocked      prometheus.Counter
+	ruleTriggers *prometheus.CounterVec
+	ruleSkips    *prometheus.CounterVec
+	scores       prometheus.Histogram
+	durations    prometheus.Histogram
+}
+
+// NewCollector creates a collector using DefaultScoreBuckets.
+// Register it with a prometheus.Registerer and pass Observe to
+// engine.GeoGuard.SetObserver.
+func NewCollector() *Collector {
+	return NewCollectorWithBuckets(DefaultScoreBuckets)
+}
+
+// NewCollectorWithBuckets creates a collector with custom geoguard_risk_score
+// buckets, e.g. when a score cap or custom aggregator changes the score range.
+func NewCollectorWithBuckets(scoreBuckets []float64) *Collector {
+	return &Collector{
+		evaluations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "evaluations_total",
+			Help:      "Validate calls by risk level (\"error\" for failed calls).",
+		}, []string{"level"}),
+		blocked: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "blocked_total",
+			Help:      "Evaluations that reached the block threshold.",
+		}),
+		ruleTriggers: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "rule_triggers_total",
+			Help:      "Violations reported per rule.",
+		}, []string{"rule"}),
+		ruleSkips: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "rule_skips_total",
+			Help:      "Rules that errored or were skipped for missing data.",
+		}, []string{"rule"}),
+		scores: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "risk_score",
+			Help:      "Distribution of TotalRiskScore.",
+			Buckets:   scoreBuckets,
+		}),
+		durations: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "evaluation_duration_seconds",
+			Help:      "Validate latency in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+	}
+}
+
+// Observe records one evaluation. Its signature matches engine.Observer.
+func (c *Collector) Observe(event engine.ObservationEvent) {
+	c.durations.Observe(event.Duration.Seconds())
+
+	if event.Err != nil {
+		c.evaluations.WithLabelValues(errorLevel).Inc()
+		return
+	}
+
+	c.evaluations.WithLabelValues(event.Level.String()).Inc()
+	c.scores.Observe(float64(event.TotalRiskScore))
+	if event.IsBlocked {
+		c.blocked.Inc()
+	}
+	for _, rule := range event.TriggeredRules {
+		c.ruleTriggers.WithLabelValues(rule).Inc()
+	}
+	for _, rule := range event.SkippedRules {
+		c.ruleSkips.WithLabelValues(rule).Inc()
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.evaluations.Describe(ch)
+	c.blocked.Describe(ch)
+	c.ruleTriggers.Describe(ch)
+	c.ruleSkips.Describe(ch)
+	c.scores.Describe(ch)
+	c.durations.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.evaluations.Collect(ch)
+	c.blocked.Collect(ch)
+	c.ruleTriggers.Collect(ch)
+	c.ruleSkips.Collect(ch)
+	c.scores.Collect(ch)
+	c.durations.Collect(ch)
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/engine"
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestCollectorObserve(t *testing.T) {
+	collector := NewCollector()
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(collector); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	collector.Observe(engine.ObservationEvent{
+		TotalRiskScore: 120,
+		Level:          models.RiskHigh,
+		TriggeredRules: []string{"Impossible Travel", "VPN/Proxy Detection"},
+		Duration:       time.Millisecond,
+	})
+	collector.Observe(engine.ObservationEvent{
+		TotalRiskScore: 30,
+		Level:          models.RiskLow,
+		TriggeredRules: []string{"Impossible Travel"},
+		Duration:       time.Millisecond,
+	})
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+
+	triggers := map[string]float64{}
+	var scoreCount uint64
+	var scoreSum float64
+	for _, family := range families {
+		switch family.GetName() {
+		case "geoguard_rule_triggers_total":
+			for _, metric := range family.GetMetric() {
+				triggers[metric.GetLabel()[0].GetValue()] = metric.GetCounter().GetValue()
+			}
+		case "geoguard_risk_score":
+			histogram := family.GetMetric()[0].GetHistogram()
+			scoreCount, scoreSum = histogram.GetSampleCount(), histogram.GetSampleSum()
+		}
+	}
+
+	if triggers["Impossible Travel"] != 2 || triggers["VPN/Proxy Detection"] != 1 {
+		t.Errorf("rule triggers = %v, want Impossible Travel=2, VPN/Proxy Detection=1", triggers)
+	}
+	if scoreCount != 2 || scoreSum != 150 {
+		t.Errorf("risk score histogram count/sum = %d/%v, want 2/150", scoreCount, scoreSum)
+	}
+}
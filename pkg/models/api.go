@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// API response status values derived from the total risk score.
+const (
+	StatusAllowed = "allowed" // Score below 50
+	StatusReview  = "review"  // Score 50-99
+	StatusBlocked = "blocked" // Score 100+ or IsBlocked set
+)
+
+// APIResponse is a stable, privacy-safe JSON shape for returning risk assessments.
+//
+// It standardizes integration across HTTP frameworks and gRPC gateways so
+// each project does not hand-build response maps.
+//
+// Privacy Guarantee:
+//   - Never contains raw IP addresses (only the masked prefix)
+//   - Never contains coordinates (not even opt-in stored device GPS)
+//   - Never contains the device fingerprint hash
+type APIResponse struct {
+	Status          string           `json:"status"`
+	RiskScore       int              `json:"risk_score"`
+	NormalizedScore float64          `json:"normalized_score"` // RiskScore scaled to 0.0-1.0 (capped at 100)
+	EvaluationID    string           `json:"evaluation_id"`
+	Violations      []APIViolation   `json:"violations"`
+	Record          *APIRecordDetail `json:"record,omitempty"`
+}
+
+// APIViolation is the JSON shape of a single triggered rule.
+type APIViolation struct {
+	Rule     string `json:"rule"`
+	Category string `json:"category,omitempty"`
+	Score    int    `json:"score"`
+	Reason   string `json:"reason"`
+}
+
+// APIRecordDetail is the privacy-safe subset of a LoginRecord exposed to clients.
+type APIRecordDetail struct {
+	MaskedIPPrefix string    `json:"masked_ip_prefix"`
+	CountryCode    string    `json:"country_code,omitempty"`
+	ASN            uint      `json:"asn,omitempty"`
+	OrgName        string    `json:"org_name,omitempty"`
+	IPTimezone     string    `json:"ip_timezone,omitempty"`
+	ClientTimezone string    `json:"client_timezone,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// ToAPIResponse converts the result into a stable, privacy-safe API response.
+// The record is optional; pass nil to omit record details.
+func (r *RiskResult) ToAPIResponse(record *LoginRecord) APIResponse {
+	response := APIResponse{
+		Status:          StatusAllowed,
+		RiskScore:       r.TotalRiskScore,
+		NormalizedScore: float64(min(max(r.TotalRiskScore, 0), 100)) / 100,
+		EvaluationID:    r.EvaluationID,
+		Violations:      make([]APIViolation, 0, len(r.Violations)),
+	}
+
+	switch {
+	case r.IsBlocked || r.TotalRiskScore >= 100:
+		response.Status = StatusBlocked
+	case r.TotalRiskScore >= 50:
+		response.Status = StatusReview
+	}
+
+	for _, v := range r.Violations {
+		response.Violations = append(response.Violations, APIViolation{
+			Rule:     v.RuleName,
+			Category: v.Category,
+			Score:    v.RiskScore,
+			Reason:   v.Reason,
+		})
+	}
+
+	if record != nil {
+		response.Record = &APIRecordDetail{
+			MaskedIPPrefix: record.MaskedIPPrefix,
+			CountryCode:    record.CountryCode,
+			ASN:            record.ASN,
+			OrgName:        record.OrgName,
+			IPTimezone:     record.IPTimezone,
+			ClientTimezone: record.ClientTimezone,
+			Timestamp:      record.Timestamp,
+		}
+	}
+
+	return response
+}
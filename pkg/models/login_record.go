@@ -2,43 +2,119 @@ package models
 
 import "time"
 
+// CurrentSchemaVersion is the LoginRecord schema version written by this release.
+//
+// Compatibility Policy:
+//   - Fields are only ever added, never renamed or repurposed
+//   - Each new field bumps the version and documents its default for older records
+//   - Stores call Migrate after decoding so older records upgrade transparently
+//   - Stateful rules treat zero-valued fields as "unknown" and skip comparison
+//
+// Version History:
+//   - 0: Records written before versioning (treated as version 1)
+//   - 1: Initial schema (masked IP, coarse location, ASN, fingerprint, timezones)
+//   - 2: Outcome (older records default to OutcomeUnknown)
+//   - 3: DeviceLatitude/DeviceLongitude (older records default to 0, i.e. not stored)
+//   - 4: Platform (older records default to "", i.e. unknown)
+//   - 5: SmoothedRiskScore (older records default to 0)
+//   - 6: ConnectionType (older records default to "", i.e. unknown)
+const CurrentSchemaVersion = 6
+
+// Outcome records whether a login attempt succeeded.
+// Applications that log failed attempts set this before saving the record.
+type Outcome string
+
+const (
+	OutcomeUnknown Outcome = ""        // Not reported by the application
+	OutcomeSuccess Outcome = "success" // Credentials accepted
+	OutcomeFailure Outcome = "failure" // Credentials rejected
+)
+
+// ConnectionTypeCellular is the LoginRecord.ConnectionType MaxMind reports
+// for mobile carrier networks.
+const ConnectionTypeCellular = "Cellular"
+
 // LoginRecord represents a user's login event with privacy-safe data.
 //
 // Privacy-by-Design (GDPR/KVKK Compliance):
 //   - Raw IP addresses are NEVER stored; only masked prefixes (IPv4: /24, IPv6: /64)
 //   - Precise coordinates (lat/lon) are NEVER persisted; used ephemerally at runtime only
+//     (device GPS may be stored rounded only if explicitly enabled, see DeviceLatitude)
 //   - Only coarse location identifiers (CountryCode, CityGeonameID) are stored
 //   - Raw UserAgent is NEVER stored; only hashed fingerprint for device tracking
 //
 // This record is designed to be safely persisted in any storage backend
-// while maintaining full functionality for security analysis.
+// while maintaining full functionality for security analysis. JSON tags use
+// snake_case names; Timestamp serializes as RFC 3339.
 type LoginRecord struct {
+	// SchemaVersion identifies the layout this record was written with.
+	// Zero indicates a record persisted before versioning was introduced.
+	SchemaVersion int `json:"schema_version"`
+
 	// UserID uniquely identifies the user (provided by the integrating application).
-	UserID string
+	UserID string `json:"user_id"`
 
 	// Timestamp records when this login event occurred.
-	Timestamp time.Time
+	Timestamp time.Time `json:"timestamp"`
 
 	// MaskedIPPrefix is the anonymized IP address (IPv4: /24, IPv6: /64).
 	// Raw IP addresses are never stored - they exist only ephemerally during request processing.
 	// Example: "192.168.1.0/24" or "2001:db8::/64"
-	MaskedIPPrefix string
+	MaskedIPPrefix string `json:"masked_ip_prefix"`
 
 	// Coarse Location Identifiers (Privacy-Safe)
 	// Precise coordinates are never stored - only city-level identifiers.
-	CountryCode   string // ISO 3166-1 alpha-2 country code (e.g., "US", "TR")
-	CityGeonameID uint   // GeoNames city identifier for city-level granularity
+	CountryCode   string `json:"country_code,omitempty"`    // ISO 3166-1 alpha-2 country code (e.g., "US", "TR")
+	CityGeonameID uint   `json:"city_geoname_id,omitempty"` // GeoNames city identifier for city-level granularity
 
 	// Network Information
-	ASN     uint   // Autonomous System Number of the network
-	OrgName string // Organization name from ASN (e.g., "Google LLC", "Amazon AWS")
+	ASN     uint   `json:"asn,omitempty"`      // Autonomous System Number of the network
+	OrgName string `json:"org_name,omitempty"` // Organization name from ASN (e.g., "Google LLC", "Amazon AWS")
+
+	// ConnectionType from GeoIP (e.g., "Cellular", "Cable/DSL"); empty when unavailable.
+	ConnectionType string `json:"connection_type,omitempty"`
 
 	// Device Fingerprint (Privacy-Safe)
 	// Raw UserAgent is NEVER stored - only the hash for device change detection.
 	// This prevents tracking while still enabling security analysis.
-	FingerprintHash string // SHA256 hash of UserAgent + AcceptLanguage
+	FingerprintHash string `json:"fingerprint_hash,omitempty"` // SHA256 hash of UserAgent + AcceptLanguage
+	Platform        string `json:"platform,omitempty"`         // Coarse OS family parsed from UserAgent (e.g., "windows", "ios")
 
 	// Timezone Information (for VPN/proxy detection)
-	IPTimezone     string // Timezone derived from IP geolocation (e.g., "Europe/Amsterdam")
-	ClientTimezone string // Timezone reported by client browser (e.g., "Europe/Istanbul")
-}
\ No newline at end of file
+	IPTimezone     string `json:"ip_timezone,omitempty"`     // Timezone derived from IP geolocation (e.g., "Europe/Amsterdam")
+	ClientTimezone string `json:"client_timezone,omitempty"` // Timezone reported by client browser (e.g., "Europe/Istanbul")
+
+	// Outcome of the attempt (success/failure), if reported by the application.
+	// Enables rules that correlate failed attempts with later successes.
+	Outcome Outcome `json:"outcome,omitempty"`
+
+	// Opt-in Device Coordinates (Privacy Trade-off)
+	// Zero unless the engine is configured with engine.WithCoordinateStorage,
+	// in which case device GPS is stored rounded to the configured precision.
+	// Enables GPS-history rules such as spoof detection via repeated readings.
+	DeviceLatitude  float64 `json:"device_latitude,omitempty"`
+	DeviceLongitude float64 `json:"device_longitude,omitempty"`
+
+	// SmoothedRiskScore is the user's EWMA risk score after this login.
+	// Zero unless the engine is configured with engine.WithEWMA.
+	SmoothedRiskScore float64 `json:"smoothed_risk_score,omitempty"`
+}
+
+// Migrate upgrades a decoded record to CurrentSchemaVersion in place.
+//
+// Fields introduced after the record's version keep their zero values, which
+// stateful rules interpret as "unknown". Records from a newer schema are left
+// untouched so a rolling deployment never downgrades data.
+func (r *LoginRecord) Migrate() {
+	if r.SchemaVersion >= CurrentSchemaVersion {
+		return
+	}
+
+	// Version 0 -> 1: No field changes, records only gain a version stamp.
+	// Version 1 -> 2: Outcome defaults to OutcomeUnknown (zero value).
+	// Version 2 -> 3: Device coordinates default to 0 (not stored).
+	// Version 3 -> 4: Platform defaults to "" (unknown).
+	// Version 4 -> 5: SmoothedRiskScore defaults to 0 (no prior average).
+	// Version 5 -> 6: ConnectionType defaults to "" (unknown).
+	r.SchemaVersion = CurrentSchemaVersion
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// v1Record is a record as serialized by a schema version 1 release.
+const v1Record = `{
+	"schema_version": 1,
+	"user_id": "alice",
+	"timestamp": "2025-03-01T08:30:00Z",
+	"masked_ip_prefix": "88.230.100.0/24",
+	"country_code": "TR",
+	"city_geoname_id": 745044,
+	"asn": 9121,
+	"org_name": "Turk Telekom",
+	"fingerprint_hash": "3f1c",
+	"ip_timezone": "Europe/Istanbul",
+	"client_timezone": "Europe/Istanbul"
+}`
+
+func TestMigrateUpgradesV1Record(t *testing.T) {
+	var record LoginRecord
+	if err := json.Unmarshal([]byte(v1Record), &record); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	record.Migrate()
+
+	want := LoginRecord{
+		SchemaVersion:   CurrentSchemaVersion,
+		UserID:          "alice",
+		Timestamp:       time.Date(2025, 3, 1, 8, 30, 0, 0, time.UTC),
+		MaskedIPPrefix:  "88.230.100.0/24",
+		CountryCode:     "TR",
+		CityGeonameID:   745044,
+		ASN:             9121,
+		OrgName:         "Turk Telekom",
+		FingerprintHash: "3f1c",
+		IPTimezone:      "Europe/Istanbul",
+		ClientTimezone:  "Europe/Istanbul",
+		// Fields added after version 1 stay at their "unknown" zero values
+	}
+	if record != want {
+		t.Errorf("migrated record =\n%+v\nwant\n%+v", record, want)
+	}
+}
+
+func TestMigrateUnversionedRecord(t *testing.T) {
+	var record LoginRecord
+	if err := json.Unmarshal([]byte(`{"user_id":"alice","masked_ip_prefix":"88.230.100.0/24"}`), &record); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	record.Migrate()
+
+	if record.SchemaVersion != CurrentSchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", record.SchemaVersion, CurrentSchemaVersion)
+	}
+}
+
+func TestMigrateLeavesNewerRecords(t *testing.T) {
+	record := LoginRecord{SchemaVersion: CurrentSchemaVersion + 1, UserID: "alice"}
+	record.Migrate()
+
+	if record.SchemaVersion != CurrentSchemaVersion+1 {
+		t.Errorf("SchemaVersion = %d, want %d (never downgraded)", record.SchemaVersion, CurrentSchemaVersion+1)
+	}
+}
@@ -1,5 +1,7 @@
 package models
 
+import "encoding/json"
+
 // RiskResult contains the complete output of a security analysis.
 // It aggregates scores from all evaluated rules and provides an explainable result.
 //
@@ -12,26 +14,138 @@ type RiskResult struct {
 	//   - 0-50: Low risk (normal behavior)
 	//   - 50-100: Medium risk (some anomalies detected)
 	//   - 100+: High risk (multiple security indicators)
-	TotalRiskScore int
+	//
+	// Rules may return negative scores (credits), but the total is floored at 0.
+	TotalRiskScore int `json:"total_risk_score"`
 
 	// Violations contains details of each rule that contributed to the score.
 	// This enables explainable security decisions and audit trails.
-	Violations []Violation
+	Violations []Violation `json:"violations"`
+
+	// ShadowViolations lists triggered shadow (dry-run) rules.
+	// These never contribute to TotalRiskScore or the block decision.
+	ShadowViolations []Violation `json:"shadow_violations,omitempty"`
+
+	// SmoothedScore is the user's exponentially-weighted moving average of
+	// TotalRiskScore, including this login. Zero unless engine.WithEWMA is set.
+	SmoothedScore float64 `json:"smoothed_score,omitempty"`
+
+	// RawRiskScore and BaselineScore are set when adaptive scoring is enabled
+	// (engine.GeoGuard.EnableAdaptiveScoring): RawRiskScore is the absolute
+	// score and BaselineScore the user's previous average, and TotalRiskScore
+	// reports the deviation above that baseline.
+	RawRiskScore  int     `json:"raw_risk_score,omitempty"`
+	BaselineScore float64 `json:"baseline_score,omitempty"`
+
+	// EvaluationID uniquely identifies this analysis for log correlation.
+	EvaluationID string `json:"evaluation_id"`
+
+	// RuleErrors lists active rules that could not run: rules that returned an
+	// error and rules skipped because required data was missing. A rule absent
+	// from both Violations and RuleErrors ran and passed.
+	RuleErrors []RuleError `json:"rule_errors,omitempty"`
+
+	// GeoUnavailable reports that the engine ran without GeoIP data
+	// (see engine.NewWithoutGeo): location rules were skipped.
+	GeoUnavailable bool `json:"geo_unavailable,omitempty"`
+
+	// PrivateIP reports that the login IP is private or reserved (RFC 1918,
+	// loopback, link-local, ...; see geoip.IsPrivateIP). Geolocation was
+	// skipped rather than failed, so location fields are empty. This usually
+	// means a reverse proxy is not forwarding the client address.
+	PrivateIP bool `json:"private_ip,omitempty"`
+
+	// GeoLookupFailed reports that the GeoIP location lookup returned an error
+	// (e.g., a malformed IP or a database read failure). Location fields are
+	// empty, so location rules passed without evidence; callers may treat
+	// this as elevated risk. Private IPs and no-geo mode do not set it.
+	GeoLookupFailed bool `json:"geo_lookup_failed,omitempty"`
+
+	// GeoLookupError is the lookup error message when GeoLookupFailed is set.
+	GeoLookupError string `json:"geo_lookup_error,omitempty"`
+
+	// FirstLogin reports that the user had no login history. Set only when
+	// a first-login policy is configured (engine.GeoGuard.SetFirstLoginPolicy).
+	FirstLogin bool `json:"first_login,omitempty"`
 
 	// IsBlocked is a convenience field that can be set by the engine
 	// based on a configured threshold. Default threshold is typically 100.
-	IsBlocked bool
+	IsBlocked bool `json:"is_blocked"`
+}
+
+// MarshalJSON encodes the result with its snake_case field names plus the
+// derived "risk_level" (see Level, using the default thresholds).
+func (r RiskResult) MarshalJSON() ([]byte, error) {
+	// riskResult has the same fields but no methods, avoiding recursion
+	type riskResult RiskResult
+	return json.Marshal(struct {
+		riskResult
+		RiskLevel RiskLevel `json:"risk_level"`
+	}{
+		riskResult: riskResult(r),
+		RiskLevel:  r.Level(),
+	})
 }
 
+// Violation categories group rules by the kind of signal they evaluate.
+// Rules report their category via an optional Category() method.
+const (
+	CategoryLocation = "location" // Geographic consistency (geofencing, travel, country)
+	CategoryNetwork  = "network"  // Network origin (data center, proxy, VPN heuristics)
+	CategoryDevice   = "device"   // Device and client characteristics
+	CategoryBehavior = "behavior" // Login patterns over time
+	CategoryTrust    = "trust"    // Caller-supplied trust adjustments
+)
+
 // Violation represents a single rule that was triggered during analysis.
 // Each violation is self-explanatory and can be logged for audit purposes.
 type Violation struct {
 	// RuleName is the unique identifier of the triggered rule.
-	RuleName string
+	RuleName string `json:"rule"`
+
+	// Category groups the rule by signal type (e.g., CategoryLocation).
+	// Empty for rules that do not declare a category.
+	Category string `json:"category,omitempty"`
+
+	// Severity is the integrator-defined label for this rule (e.g., "alert").
+	// Assigned from the engine's severity map; empty when the rule is unmapped.
+	Severity string `json:"severity,omitempty"`
 
 	// RiskScore is the points added by this specific rule.
-	RiskScore int
+	// Negative values are credits that reduce the total (e.g., trusted users).
+	RiskScore int `json:"score"`
 
 	// Reason provides a human-readable explanation of why this rule triggered.
-	Reason string
-}
\ No newline at end of file
+	Reason string `json:"reason"`
+
+	// Details holds machine-readable values behind the score (e.g.,
+	// "speed_kmh", or "from_country"/"to_country") for rules implementing
+	// rules.ExplainableRule. Values are derived scalars or stored record
+	// fields, never coordinates or raw headers. Nil for other rules.
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// RuleError records an active rule that could not be evaluated.
+type RuleError struct {
+	// RuleName is the Name() of the rule that could not run.
+	RuleName string `json:"rule"`
+
+	// Skipped is true when the rule did not run because required data was
+	// missing (e.g., no-geo mode), rather than failing with an error.
+	Skipped bool `json:"skipped"`
+
+	// Message describes the error or the missing data.
+	Message string `json:"message"`
+}
+
+// ViolationsBySeverity returns the violations labeled with the given severity.
+// Use an empty string to select violations from unmapped rules.
+func (r *RiskResult) ViolationsBySeverity(severity string) []Violation {
+	matched := make([]Violation, 0)
+	for _, v := range r.Violations {
+		if v.Severity == severity {
+			matched = append(matched, v)
+		}
+	}
+	return matched
+}
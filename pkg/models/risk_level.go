@@ -0,0 +1,60 @@
+package models
+
+// RiskLevel is a coarse classification of a risk score.
+// It standardizes decisioning across services and keeps logs comparable.
+type RiskLevel int
+
+const (
+	RiskLow      RiskLevel = iota // Normal behavior
+	RiskMedium                    // Some anomalies detected
+	RiskHigh                      // Multiple security indicators
+	RiskCritical                  // Strong, corroborated indicators
+)
+
+// DefaultRiskThresholds are the lower bounds of RiskMedium, RiskHigh, and RiskCritical.
+var DefaultRiskThresholds = [3]int{50, 100, 150}
+
+// String returns the lowercase level name ("low", "medium", "high", "critical").
+func (l RiskLevel) String() string {
+	switch l {
+	case RiskLow:
+		return "low"
+	case RiskMedium:
+		return "medium"
+	case RiskHigh:
+		return "high"
+	case RiskCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the level as its name, so it serializes cleanly in JSON.
+func (l RiskLevel) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// Level maps TotalRiskScore to a RiskLevel.
+//
+// Thresholds are the lower bounds of RiskMedium, RiskHigh, and RiskCritical,
+// in that order. Omitted thresholds use DefaultRiskThresholds (50, 100, 150),
+// so Level(40) only lowers the medium boundary.
+//
+// Example:
+//
+//	if result.Level() >= models.RiskHigh {
+//	    requireMFA()
+//	}
+func (r *RiskResult) Level(thresholds ...int) RiskLevel {
+	bounds := DefaultRiskThresholds
+	copy(bounds[:], thresholds)
+
+	level := RiskLow
+	for i, bound := range bounds {
+		if r.TotalRiskScore >= bound {
+			level = RiskLevel(i + 1)
+		}
+	}
+	return level
+}
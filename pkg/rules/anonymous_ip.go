@@ -0,0 +1,92 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// AnonymousIPRule flags logins from anonymizing networks using MaxMind data.
+//
+// The heuristic VPN signals (TimezoneRule, DataCenterRule) infer anonymizers
+// from side effects. This rule reads MaxMind's GeoIP2 Anonymous-IP database
+// directly, which labels commercial VPNs, public and residential proxies,
+// hosting providers, and Tor exit nodes.
+//
+// Behavior:
+//   - Triggers when any anonymizer flag is set for the current IP
+//   - With IgnoreHosting, hosting-only hits pass (avoids double counting
+//     with DataCenterRule)
+//   - Returns ErrMissingData when no Anonymous-IP database is loaded, so the
+//     engine reports the rule as skipped rather than passed
+//
+// Requirements:
+//   - geoip.Service.OpenAnonymousIPDB with a GeoIP2-Anonymous-IP database
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule interface
+//   - Flags are passed via GeoContext (never persisted)
+type AnonymousIPRule struct {
+	IgnoreHosting bool // Do not trigger on hosting-provider-only hits
+	RiskScore     int  // Points to add when an anonymizer is detected
+}
+
+// NewAnonymousIPRule creates a new anonymizing network detection rule.
+func NewAnonymousIPRule(score int) *AnonymousIPRule {
+	return &AnonymousIPRule{RiskScore: score}
+}
+
+func (a *AnonymousIPRule) Name() string {
+	return "Anonymous IP"
+}
+
+func (a *AnonymousIPRule) Description() string {
+	return "Detects VPN, proxy, hosting, and Tor IPs via the GeoIP2 Anonymous-IP database."
+}
+
+func (a *AnonymousIPRule) Category() string {
+	return models.CategoryNetwork
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because this rule requires ephemeral anonymizer flags via ValidateWithGeo.
+func (a *AnonymousIPRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo checks the anonymizer flags for the current IP.
+// Implements EphemeralGeoRule interface.
+func (a *AnonymousIPRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	info := ctx.AnonymousIP
+	if info == nil {
+		return 0, fmt.Errorf("%w: anonymous IP database not loaded", ErrMissingData)
+	}
+
+	anonymizer := info.IsAnonymousVPN || info.IsPublicProxy ||
+		info.IsResidentialProxy || info.IsTorExitNode
+	if !a.IgnoreHosting {
+		anonymizer = anonymizer || info.IsAnonymous || info.IsHostingProvider
+	}
+
+	if anonymizer {
+		return a.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports the anonymizer flags set for the IP.
+// Implements ExplainableRule interface.
+func (a *AnonymousIPRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	info := ctx.AnonymousIP
+	if info == nil {
+		return nil
+	}
+	return map[string]any{
+		"anonymous_vpn":     info.IsAnonymousVPN,
+		"hosting_provider":  info.IsHostingProvider,
+		"public_proxy":      info.IsPublicProxy,
+		"residential_proxy": info.IsResidentialProxy,
+		"tor_exit_node":     info.IsTorExitNode,
+	}
+}
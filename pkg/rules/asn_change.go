@@ -0,0 +1,77 @@
+package rules
+
+import (
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// ASNChangeRule detects when a user's network operator changes between logins.
+//
+// This is a stateful rule that complements CountryMismatchRule at a finer
+// granularity: a switch from a home ISP to an unfamiliar operator within the
+// same country often precedes account takeover, yet leaves the country
+// unchanged.
+//
+// Behavior:
+//   - Compares the current ASN with the previous login's ASN
+//   - Triggers when both are known (non-zero) and differ
+//   - Ignores the first login
+//
+// Note: Legitimate users change networks routinely (home Wi-Fi, mobile
+// data, office). Keep the score low and combine it with other signals.
+type ASNChangeRule struct {
+	RiskScore int // Points to add when the ASN differs from the previous login
+}
+
+// NewASNChangeRule creates a new network operator change detection rule.
+func NewASNChangeRule(score int) *ASNChangeRule {
+	return &ASNChangeRule{RiskScore: score}
+}
+
+func (a *ASNChangeRule) Name() string {
+	return "ASN Change"
+}
+
+func (a *ASNChangeRule) Description() string {
+	return "Detects when the network operator (ASN) differs from the previous login."
+}
+
+func (a *ASNChangeRule) Category() string {
+	return models.CategoryNetwork
+}
+
+// Stateful reports that this rule requires historical login data.
+func (a *ASNChangeRule) Stateful() bool {
+	return true
+}
+
+func (a *ASNChangeRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
+	// First login or no historical data
+	if last == nil {
+		return 0, nil
+	}
+
+	// Cannot compare if ASN data is missing (e.g., no ASN database)
+	if last.ASN == 0 || input.ASN == 0 {
+		return 0, nil
+	}
+
+	if input.ASN != last.ASN {
+		return a.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports the previous and current networks.
+// Implements ExplainableRule interface.
+func (a *ASNChangeRule) Explain(ctx GeoContext, input models.LoginRecord, last *models.LoginRecord) map[string]any {
+	if last == nil {
+		return nil
+	}
+	return map[string]any{
+		"from_asn": last.ASN,
+		"from_org": last.OrgName,
+		"to_asn":   input.ASN,
+		"to_org":   input.OrgName,
+	}
+}
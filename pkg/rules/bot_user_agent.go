@@ -0,0 +1,111 @@
+package rules
+
+import (
+	"strings"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// BotUserAgentRule detects automation tools by their User-Agent.
+//
+// HTTP libraries and headless browsers announce themselves (curl/8.4.0,
+// python-requests/2.31, Go-http-client/1.1, HeadlessChrome). Credential
+// stuffing tools often keep these defaults, so a login from one is a strong
+// signal on its own, even from a residential network where DataCenterRule
+// stays silent.
+//
+// Behavior:
+//   - Case-insensitive substring match against Signatures
+//   - An empty or missing User-Agent also triggers when FlagEmpty is set
+//     (the default), since real browsers always send one
+//   - Override or extend via the Signatures field (see DefaultBotSignatures)
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule to receive the raw header via GeoContext
+//   - The User-Agent is never persisted; only the fingerprint hash is stored
+type BotUserAgentRule struct {
+	Signatures []string // Substrings identifying automation tools (case-insensitive)
+	FlagEmpty  bool     // Trigger on an empty User-Agent
+	RiskScore  int      // Points to add when an automation tool is detected
+}
+
+// NewBotUserAgentRule creates a new automation User-Agent rule using
+// DefaultBotSignatures and flagging empty User-Agents.
+func NewBotUserAgentRule(score int) *BotUserAgentRule {
+	return &BotUserAgentRule{
+		Signatures: DefaultBotSignatures(),
+		FlagEmpty:  true,
+		RiskScore:  score,
+	}
+}
+
+// DefaultBotSignatures returns the default automation signatures
+// (curl, wget, python-requests, Go-http-client, headless browsers, ...).
+// Each call returns a fresh slice that callers may modify.
+func DefaultBotSignatures() []string {
+	return append([]string(nil), defaultBotSignatures...)
+}
+
+func (b *BotUserAgentRule) Name() string {
+	return "Bot User-Agent"
+}
+
+func (b *BotUserAgentRule) Description() string {
+	return "Detects automation tools and empty User-Agents."
+}
+
+func (b *BotUserAgentRule) Category() string {
+	return models.CategoryDevice
+}
+
+// RequiresGeoIP reports that this rule only reads the User-Agent header, so
+// it keeps running in no-geo mode. Implements GeoIPRule interface.
+func (b *BotUserAgentRule) RequiresGeoIP() bool {
+	return false
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because the raw User-Agent is only available via ValidateWithGeo.
+func (b *BotUserAgentRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo classifies the raw User-Agent.
+// Implements EphemeralGeoRule interface.
+func (b *BotUserAgentRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	switch classifyUserAgent(ctx.UserAgent, b.Signatures) {
+	case userAgentBot:
+		return b.RiskScore, nil
+	case userAgentEmpty:
+		if b.FlagEmpty {
+			return b.RiskScore, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// Explain reports the matched bot signature, or that the User-Agent was empty.
+// The User-Agent itself is never included.
+// Implements ExplainableRule interface.
+func (b *BotUserAgentRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	switch classifyUserAgent(ctx.UserAgent, b.Signatures) {
+	case userAgentBot:
+		return map[string]any{"signature": matchedSignature(ctx.UserAgent, b.Signatures)}
+	case userAgentEmpty:
+		return map[string]any{"empty_user_agent": true}
+	}
+	return nil
+}
+
+// matchedSignature returns the first signature contained in userAgent
+// (case-insensitive), or "" if none is.
+func matchedSignature(userAgent string, signatures []string) string {
+	ua := strings.ToLower(userAgent)
+	for _, signature := range signatures {
+		if strings.Contains(ua, strings.ToLower(signature)) {
+			return signature
+		}
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+package rules
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// BusinessHoursRule flags logins outside an allowed local time-of-day window.
+//
+// For corporate accounts a login at 3 AM local time is anomalous: either the
+// credentials are used by someone in another timezone, or by automation.
+//
+// Timezone Precedence:
+//   - ClientTimezone (browser-reported) is used first, because it reflects
+//     the user's local clock even behind a VPN
+//   - IPTimezone (GeoIP) is the fallback when the client sent none or an
+//     invalid one; set PreferIPTimezone to reverse the order (the client
+//     timezone is caller-controlled and can be spoofed)
+//   - Without any valid timezone the rule is skipped
+//
+// Window:
+//   - Allowed hours are [StartHour, EndHour): StartHour inclusive, EndHour
+//     exclusive, e.g. 8 and 18 allow 08:00 through 17:59
+//   - Windows may wrap midnight: 22 and 6 allow 22:00 through 05:59
+//   - StartHour == EndHour allows the whole day (the rule never triggers)
+type BusinessHoursRule struct {
+	StartHour        int  // First allowed local hour (0-23)
+	EndHour          int  // First disallowed local hour after the window (0-24)
+	PreferIPTimezone bool // Use IPTimezone before ClientTimezone
+	RiskScore        int  // Points to add when the login is outside the window
+}
+
+// NewBusinessHoursRule creates a new time-of-day rule.
+//
+// Parameters:
+//   - startHour: First allowed local hour (e.g., 8)
+//   - endHour: First disallowed local hour (e.g., 18); may be below startHour
+//     for windows wrapping midnight
+//   - score: Risk points to add when triggered
+func NewBusinessHoursRule(startHour, endHour int, score int) *BusinessHoursRule {
+	return &BusinessHoursRule{
+		StartHour: startHour,
+		EndHour:   endHour,
+		RiskScore: score,
+	}
+}
+
+func (b *BusinessHoursRule) Name() string {
+	return "Business Hours"
+}
+
+func (b *BusinessHoursRule) Description() string {
+	return fmt.Sprintf("Flags logins outside %02d:00-%02d:00 local time.", b.StartHour, b.EndHour)
+}
+
+func (b *BusinessHoursRule) Category() string {
+	return models.CategoryBehavior
+}
+
+func (b *BusinessHoursRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	if b.StartHour < 0 || b.StartHour > 23 || b.EndHour < 0 || b.EndHour > 24 {
+		return 0, fmt.Errorf("invalid business hours %d-%d", b.StartHour, b.EndHour)
+	}
+	if b.StartHour == b.EndHour%24 {
+		return 0, nil
+	}
+
+	local, ok := b.localTime(input)
+	if !ok {
+		return 0, nil
+	}
+	hour := local.Hour()
+
+	var allowed bool
+	if b.StartHour < b.EndHour {
+		allowed = hour >= b.StartHour && hour < b.EndHour
+	} else {
+		// Window wraps midnight
+		allowed = hour >= b.StartHour || hour < b.EndHour
+	}
+
+	if !allowed {
+		return b.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports the local hour and the timezone it was computed in.
+// Implements ExplainableRule interface.
+func (b *BusinessHoursRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	local, ok := b.localTime(input)
+	if !ok {
+		return nil
+	}
+	return map[string]any{
+		"local_hour": local.Hour(),
+		"timezone":   local.Location().String(),
+	}
+}
+
+// localTime returns the login time in the user's local timezone.
+func (b *BusinessHoursRule) localTime(input models.LoginRecord) (time.Time, bool) {
+	loc := b.location(input)
+	if loc == nil {
+		return time.Time{}, false
+	}
+
+	at := input.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return at.In(loc), true
+}
+
+// location resolves the timezone according to the precedence rules.
+// Returns nil if no valid timezone is available.
+func (b *BusinessHoursRule) location(input models.LoginRecord) *time.Location {
+	candidates := []string{input.ClientTimezone, input.IPTimezone}
+	if b.PreferIPTimezone {
+		candidates[0], candidates[1] = candidates[1], candidates[0]
+	}
+
+	for _, name := range candidates {
+		if name == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package rules
+
+import (
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// CityChangeRule detects when a user logs in from a different city.
+//
+// This is a stateful rule that complements CountryMismatchRule at a finer
+// granularity: a sudden move between cities within the same country is not
+// a country change, yet is still a useful signal.
+//
+// Behavior:
+//   - Compares the GeoNames city ID of the current and previous login
+//   - Triggers when both IDs are known (non-zero) and differ
+//   - An unknown city on either side skips the rule (GeoIP often resolves
+//     only the country, especially for mobile networks)
+//
+// Privacy-by-Design:
+//   - Uses only the stored CityGeonameID; no coordinates are needed
+//
+// Note: IP geolocation at city level is imprecise; neighboring cities and
+// carrier gateways cause legitimate changes. Keep the score low, or set
+// HalfLife so changes since long ago count less (see RecencyWeight).
+type CityChangeRule struct {
+	RiskScore int           // Points to add when the city differs from the previous login
+	HalfLife  time.Duration // Score half-life by previous login age (0 = no decay)
+}
+
+// NewCityChangeRule creates a new city change detection rule.
+func NewCityChangeRule(score int) *CityChangeRule {
+	return &CityChangeRule{RiskScore: score}
+}
+
+func (c *CityChangeRule) Name() string {
+	return "City Change"
+}
+
+func (c *CityChangeRule) Description() string {
+	return "Detects when login city differs from previous login."
+}
+
+func (c *CityChangeRule) Category() string {
+	return models.CategoryLocation
+}
+
+// Stateful reports that this rule requires historical login data.
+func (c *CityChangeRule) Stateful() bool {
+	return true
+}
+
+func (c *CityChangeRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
+	// First login or no historical data
+	if last == nil {
+		return 0, nil
+	}
+
+	// Cannot compare if either city is unknown
+	if last.CityGeonameID == 0 || input.CityGeonameID == 0 {
+		return 0, nil
+	}
+
+	if input.CityGeonameID != last.CityGeonameID {
+		return decayScore(c.RiskScore, input.Timestamp.Sub(last.Timestamp), c.HalfLife), nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports the previous and current cities (GeoNames IDs).
+// Implements ExplainableRule interface.
+func (c *CityChangeRule) Explain(ctx GeoContext, input models.LoginRecord, last *models.LoginRecord) map[string]any {
+	if last == nil {
+		return nil
+	}
+	return map[string]any{
+		"from_city_geoname_id": last.CityGeonameID,
+		"to_city_geoname_id":   input.CityGeonameID,
+		"elapsed_minutes":      roundTenth(input.Timestamp.Sub(last.Timestamp).Minutes()),
+	}
+}
@@ -0,0 +1,107 @@
+package rules
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// CityChurnRule detects unstable geolocation: many distinct cities in a short window.
+//
+// A CityGeonameID that changes on nearly every login (even within one country)
+// indicates unstable geolocation, typical of proxy pools, rotating residential
+// proxies, or carrier-grade NAT. This is a network-stability signal distinct
+// from velocity: each hop may be individually plausible.
+//
+// Behavior:
+//   - Counts distinct non-zero CityGeonameIDs within Window, including the current login
+//   - Unknown cities (ID 0) are ignored
+//   - Triggers when the count exceeds MaxDistinctCities
+//   - No-op without recent-history support (storage.RecentHistoryStore)
+//
+// Tuning:
+// Mobile-heavy user bases legitimately see more city churn (carrier gateways
+// geolocate inconsistently); raise MaxDistinctCities accordingly.
+//
+// Implements HistoryRule interface.
+type CityChurnRule struct {
+	MaxDistinctCities int           // Maximum distinct cities allowed within Window
+	Window            time.Duration // Lookback window
+	RiskScore         int           // Points to add when rule triggers
+}
+
+// NewCityChurnRule creates a new city churn detection rule.
+//
+// Parameters:
+//   - maxDistinctCities: Maximum distinct cities within the window (recommend 3)
+//   - window: Lookback window (recommend 24 hours)
+//   - score: Risk points to add when triggered
+func NewCityChurnRule(maxDistinctCities int, window time.Duration, score int) *CityChurnRule {
+	return &CityChurnRule{
+		MaxDistinctCities: maxDistinctCities,
+		Window:            window,
+		RiskScore:         score,
+	}
+}
+
+func (c *CityChurnRule) Name() string {
+	return "City Churn"
+}
+
+func (c *CityChurnRule) Description() string {
+	return fmt.Sprintf("Detects more than %d distinct cities within %s.", c.MaxDistinctCities, c.Window)
+}
+
+func (c *CityChurnRule) Category() string {
+	return models.CategoryNetwork
+}
+
+// Stateful reports that this rule requires historical login data.
+func (c *CityChurnRule) Stateful() bool {
+	return true
+}
+
+// Validate is a no-op: churn cannot be measured from a single previous record.
+func (c *CityChurnRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithHistory counts distinct cities within the window.
+// Implements HistoryRule interface.
+func (c *CityChurnRule) ValidateWithHistory(input models.LoginRecord, history []*models.LoginRecord) (int, error) {
+	if c.distinctCities(input, history) > c.MaxDistinctCities {
+		return c.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// ExplainWithHistory reports the number of distinct cities in the window.
+// Implements HistoryExplainableRule interface.
+func (c *CityChurnRule) ExplainWithHistory(input models.LoginRecord, history []*models.LoginRecord) map[string]any {
+	return map[string]any{
+		"distinct_cities":     c.distinctCities(input, history),
+		"max_distinct_cities": c.MaxDistinctCities,
+		"window_minutes":      c.Window.Minutes(),
+	}
+}
+
+// distinctCities counts the cities of input and of history within Window.
+func (c *CityChurnRule) distinctCities(input models.LoginRecord, history []*models.LoginRecord) int {
+	cities := make(map[uint]struct{})
+	if input.CityGeonameID != 0 {
+		cities[input.CityGeonameID] = struct{}{}
+	}
+
+	for _, record := range history {
+		if record.CityGeonameID == 0 {
+			continue
+		}
+		if input.Timestamp.Sub(record.Timestamp) > c.Window {
+			continue
+		}
+		cities[record.CityGeonameID] = struct{}{}
+	}
+	return len(cities)
+}
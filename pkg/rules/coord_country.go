@@ -0,0 +1,97 @@
+package rules
+
+import (
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// CoordCountryConsistencyRule flags GeoIP results whose coordinates contradict their country.
+//
+// GeoIP databases occasionally return a country code together with
+// coordinates that fall in a different country (stale data, border-region
+// centroids, country-level fallbacks). Geofencing and velocity act on those
+// coordinates, so contradictory enrichment can produce misleading violations.
+// This data-quality guard reverse-geocodes the IP coordinates and flags the
+// login when the derived country disagrees with CountryCode.
+//
+// Behavior:
+//   - Skips when Resolver is nil, IP coordinates are missing, or CountryCode is empty
+//   - Skips when the resolver cannot place the coordinates in a country (e.g., at sea)
+//   - Triggers when the resolved country differs from CountryCode
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule interface
+//   - IP coordinates are resolved ephemerally and never persisted
+type CoordCountryConsistencyRule struct {
+	Resolver  CountryResolver // Reverse geocoder for IP coordinates
+	RiskScore int             // Points to add when coordinates and country disagree
+}
+
+// NewCoordCountryConsistencyRule creates a new GeoIP coordinate/country consistency rule.
+//
+// Parameters:
+//   - resolver: Reverse geocoder backed by the integrator's boundary data
+//   - score: Risk points to add when triggered
+func NewCoordCountryConsistencyRule(resolver CountryResolver, score int) *CoordCountryConsistencyRule {
+	return &CoordCountryConsistencyRule{
+		Resolver:  resolver,
+		RiskScore: score,
+	}
+}
+
+func (c *CoordCountryConsistencyRule) Name() string {
+	return "GeoIP Coordinate Consistency"
+}
+
+func (c *CoordCountryConsistencyRule) Description() string {
+	return "Checks that GeoIP coordinates fall within the reported IP country."
+}
+
+func (c *CoordCountryConsistencyRule) Category() string {
+	return models.CategoryLocation
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because this rule requires ephemeral coordinates via ValidateWithGeo.
+func (c *CoordCountryConsistencyRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo compares the country at the IP coordinates with CountryCode.
+// Implements EphemeralGeoRule interface.
+func (c *CoordCountryConsistencyRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	if c.Resolver == nil || input.CountryCode == "" {
+		return 0, nil
+	}
+
+	// Skip if IP location is not available
+	if ctx.IPLatitude == 0 && ctx.IPLongitude == 0 {
+		return 0, nil
+	}
+
+	resolved, err := c.Resolver.CountryAt(ctx.IPLatitude, ctx.IPLongitude)
+	if err != nil {
+		return 0, err
+	}
+
+	if resolved == "" || resolved == input.CountryCode {
+		return 0, nil
+	}
+
+	return c.RiskScore, nil
+}
+
+// Explain reports the reported IP country and the country at its coordinates.
+// Implements ExplainableRule interface.
+func (c *CoordCountryConsistencyRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	if c.Resolver == nil {
+		return nil
+	}
+	resolved, err := c.Resolver.CountryAt(ctx.IPLatitude, ctx.IPLongitude)
+	if err != nil {
+		return nil
+	}
+	return map[string]any{
+		"ip_country":         input.CountryCode,
+		"coordinate_country": resolved,
+	}
+}
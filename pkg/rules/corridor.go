@@ -0,0 +1,140 @@
+package rules
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// CorridorRule scores country changes along known high-risk corridors.
+//
+// Certain origin -> destination country pairs are disproportionately
+// fraudulent for a given business. Unlike CountryMismatchRule, which adds a
+// flat score for any change, this rule only fires for configured pairs and
+// adds the score associated with that specific corridor.
+//
+// This is a stateful rule that requires historical login data.
+//
+// Configuration:
+//   - Corridors are directional: {"TR", "NG"} does not imply {"NG", "TR"}
+//   - DefaultCorridorRule ships empty; corridors are business-specific
+//   - LoadCorridorRule reads corridors from a CSV file
+type CorridorRule struct {
+	Corridors map[[2]string]int // {previous, current} country pair -> points to add
+}
+
+// NewCorridorRule creates a rule from an explicit corridor map.
+//
+// Example:
+//
+//	rule := rules.NewCorridorRule(map[[2]string]int{
+//	    {"US", "NG"}: 60,
+//	    {"GB", "RU"}: 50,
+//	})
+func NewCorridorRule(corridors map[[2]string]int) *CorridorRule {
+	return &CorridorRule{Corridors: corridors}
+}
+
+// DefaultCorridorRule creates a rule with no corridors configured.
+// Corridors are business-specific; add them via Corridors or LoadCorridorRule.
+func DefaultCorridorRule() *CorridorRule {
+	return NewCorridorRule(make(map[[2]string]int))
+}
+
+// LoadCorridorRule loads corridors from a CSV file.
+//
+// Supported format:
+//   - One corridor per line: FROM,TO,SCORE (e.g., "US,NG,60")
+//   - Country codes are ISO 3166-1 alpha-2 (case-insensitive)
+//   - Lines starting with # are ignored (comments)
+//
+// Example:
+//
+//	rule, err := rules.LoadCorridorRule("data/corridors.csv")
+func LoadCorridorRule(filePath string) (*CorridorRule, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	corridors := make(map[[2]string]int)
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		score, err := strconv.Atoi(strings.TrimSpace(fields[2]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid corridor score %q: %v", fields[2], err)
+		}
+
+		from := strings.ToUpper(strings.TrimSpace(fields[0]))
+		to := strings.ToUpper(strings.TrimSpace(fields[1]))
+		corridors[[2]string{from, to}] = score
+	}
+
+	return NewCorridorRule(corridors), nil
+}
+
+func (c *CorridorRule) Name() string {
+	return "High-Risk Corridor"
+}
+
+func (c *CorridorRule) Description() string {
+	return "Detects country changes along configured high-risk corridors."
+}
+
+func (c *CorridorRule) Category() string {
+	return models.CategoryLocation
+}
+
+// Stateful reports that this rule requires historical login data.
+func (c *CorridorRule) Stateful() bool {
+	return true
+}
+
+func (c *CorridorRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
+	// First login or no historical data
+	if last == nil {
+		return 0, nil
+	}
+
+	// Cannot compare if country data is missing
+	if last.CountryCode == "" || input.CountryCode == "" {
+		return 0, nil
+	}
+
+	// Only country changes can match a corridor
+	if input.CountryCode == last.CountryCode {
+		return 0, nil
+	}
+
+	return c.Corridors[[2]string{last.CountryCode, input.CountryCode}], nil
+}
+
+// Explain reports the country pair of the corridor.
+// Implements ExplainableRule interface.
+func (c *CorridorRule) Explain(ctx GeoContext, input models.LoginRecord, last *models.LoginRecord) map[string]any {
+	if last == nil {
+		return nil
+	}
+	return map[string]any{
+		"from_country": last.CountryCode,
+		"to_country":   input.CountryCode,
+	}
+}
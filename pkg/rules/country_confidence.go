@@ -0,0 +1,112 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// CountryConfidenceRule weighs GPS agreement with the IP country by GeoIP confidence.
+//
+// Binary checks treat every IP country as equally reliable. GeoIP2 Enterprise
+// databases report how confident they are in the country, which allows an
+// evidence-weighted signal:
+//   - Agreement: GPS confirms the IP country, so the score is reduced.
+//     The credit scales with confidence: -AgreementCredit * confidence/100
+//   - Disagreement: GPS contradicts the IP country, so the score is raised.
+//     The penalty is weighted by inverse confidence, from 1x DisagreementScore
+//     at confidence 100 up to 2x at confidence 0
+//
+// Requirements:
+//   - IP country confidence (GeoIP2 Enterprise database; GeoLite2 lacks it)
+//   - Device GPS and a Resolver to reverse-geocode it
+//   - Skips when any of these is unavailable
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule interface
+//   - GPS coordinates are resolved ephemerally and never persisted
+type CountryConfidenceRule struct {
+	Resolver          CountryResolver // Reverse geocoder for GPS coordinates
+	AgreementCredit   int             // Maximum points subtracted on agreement
+	DisagreementScore int             // Base points added on disagreement
+}
+
+// NewCountryConfidenceRule creates a new confidence-weighted GPS agreement rule.
+//
+// Parameters:
+//   - resolver: Reverse geocoder for device GPS coordinates
+//   - agreementCredit: Maximum points subtracted when GPS agrees (e.g., 20)
+//   - disagreementScore: Base points added when GPS disagrees (e.g., 40)
+func NewCountryConfidenceRule(resolver CountryResolver, agreementCredit, disagreementScore int) *CountryConfidenceRule {
+	return &CountryConfidenceRule{
+		Resolver:          resolver,
+		AgreementCredit:   agreementCredit,
+		DisagreementScore: disagreementScore,
+	}
+}
+
+func (c *CountryConfidenceRule) Name() string {
+	return "Country Confidence vs GPS"
+}
+
+func (c *CountryConfidenceRule) Description() string {
+	return fmt.Sprintf("Weighs GPS agreement with the IP country by GeoIP confidence (-%d to +%d).", c.AgreementCredit, 2*c.DisagreementScore)
+}
+
+func (c *CountryConfidenceRule) Category() string {
+	return models.CategoryLocation
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because this rule requires ephemeral coordinates via ValidateWithGeo.
+func (c *CountryConfidenceRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo compares the GPS country with the confidence-weighted IP country.
+// Implements EphemeralGeoRule interface.
+func (c *CountryConfidenceRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	// Skip without confidence data or IP country
+	if ctx.IPCountryConfidence == 0 || input.CountryCode == "" {
+		return 0, nil
+	}
+
+	// Skip without GPS or a way to resolve it
+	if c.Resolver == nil || (ctx.DeviceLatitude == 0 && ctx.DeviceLongitude == 0) {
+		return 0, nil
+	}
+
+	gpsCountry, err := c.Resolver.CountryAt(ctx.DeviceLatitude, ctx.DeviceLongitude)
+	if err != nil {
+		return 0, err
+	}
+	if gpsCountry == "" {
+		return 0, nil
+	}
+
+	confidence := float64(min(ctx.IPCountryConfidence, 100)) / 100
+
+	if gpsCountry == input.CountryCode {
+		return -int(math.Round(float64(c.AgreementCredit) * confidence)), nil
+	}
+
+	return int(math.Round(float64(c.DisagreementScore) * (2 - confidence))), nil
+}
+
+// Explain reports the IP and GPS countries and the GeoIP confidence.
+// Implements ExplainableRule interface.
+func (c *CountryConfidenceRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	if c.Resolver == nil {
+		return nil
+	}
+	gpsCountry, err := c.Resolver.CountryAt(ctx.DeviceLatitude, ctx.DeviceLongitude)
+	if err != nil {
+		return nil
+	}
+	return map[string]any{
+		"ip_country":         input.CountryCode,
+		"gps_country":        gpsCountry,
+		"country_confidence": ctx.IPCountryConfidence,
+	}
+}
@@ -1,6 +1,8 @@
 package rules
 
 import (
+	"time"
+
 	"github.com/gokaycavdar/go-geoguard/pkg/models"
 )
 
@@ -16,8 +18,22 @@ import (
 //
 // Note: Country changes may be legitimate (travel, VPN for work).
 // This rule should contribute to a risk score, not block outright.
+//
+// Decay:
+// When HalfLife is set, the score decays with the age of the previous login
+// (see RecencyWeight): a country change since yesterday counts more than one
+// since last year, when travel is the likelier explanation.
+//
+// Travel Grace:
+// When MinTravelHours is set, a country change is flagged only if it
+// happened sooner than MinTravelHours after the previous login. A traveler
+// who logs in at home, flies, and logs in abroad the next day passes; a
+// country switch within minutes does not. Use VelocityRule for a
+// distance-aware check; this option needs no coordinates or GeoIP lookups.
 type CountryMismatchRule struct {
-	RiskScore int // Points to add when country differs from previous login
+	RiskScore      int           // Points to add when country differs from previous login
+	HalfLife       time.Duration // Score half-life by previous login age (0 = no decay)
+	MinTravelHours float64       // Changes at least this long after the previous login pass (0 = always flag)
 }
 
 // CountryMismatch creates a new country change detection rule.
@@ -25,6 +41,20 @@ func CountryMismatch(score int) *CountryMismatchRule {
 	return &CountryMismatchRule{RiskScore: score}
 }
 
+// NewCountryMismatchRuleWithTravel creates a country change rule that
+// tolerates changes plausible for travel.
+//
+// Parameters:
+//   - minHoursBetween: Minimum hours between logins for a country change to
+//     count as travel (e.g., 2 for short-haul flights)
+//   - score: Risk points to add when the change happened sooner
+func NewCountryMismatchRuleWithTravel(minHoursBetween float64, score int) *CountryMismatchRule {
+	return &CountryMismatchRule{
+		RiskScore:      score,
+		MinTravelHours: minHoursBetween,
+	}
+}
+
 func (c *CountryMismatchRule) Name() string {
 	return "Country Change"
 }
@@ -33,6 +63,15 @@ func (c *CountryMismatchRule) Description() string {
 	return "Detects when login country differs from previous login."
 }
 
+func (c *CountryMismatchRule) Category() string {
+	return models.CategoryLocation
+}
+
+// Stateful reports that this rule requires historical login data.
+func (c *CountryMismatchRule) Stateful() bool {
+	return true
+}
+
 func (c *CountryMismatchRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
 	// First login or no historical data
 	if last == nil {
@@ -46,8 +85,32 @@ func (c *CountryMismatchRule) Validate(input models.LoginRecord, last *models.Lo
 
 	// Country changed since last login
 	if input.CountryCode != last.CountryCode {
-		return c.RiskScore, nil
+		elapsed := input.Timestamp.Sub(last.Timestamp)
+
+		// Enough time passed to travel between the countries
+		if c.MinTravelHours > 0 && elapsed.Hours() >= c.MinTravelHours {
+			return 0, nil
+		}
+
+		return decayScore(c.RiskScore, elapsed, c.HalfLife), nil
 	}
 
 	return 0, nil
-}
\ No newline at end of file
+}
+
+// Explain reports the previous and current countries.
+// Implements ExplainableRule interface.
+func (c *CountryMismatchRule) Explain(ctx GeoContext, input models.LoginRecord, last *models.LoginRecord) map[string]any {
+	if last == nil {
+		return nil
+	}
+	details := map[string]any{
+		"from_country":    last.CountryCode,
+		"to_country":      input.CountryCode,
+		"elapsed_minutes": roundTenth(input.Timestamp.Sub(last.Timestamp).Minutes()),
+	}
+	if c.MinTravelHours > 0 {
+		details["min_travel_hours"] = c.MinTravelHours
+	}
+	return details
+}
@@ -0,0 +1,116 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// PolicyMode selects how CountryPolicyRule interprets its country list.
+type PolicyMode string
+
+const (
+	PolicyAllow PolicyMode = "allow" // Only listed countries pass (allowlist)
+	PolicyBlock PolicyMode = "block" // Listed countries trigger (blocklist)
+)
+
+// CountryPolicyRule enforces an absolute country access policy.
+//
+// Unlike CountryMismatchRule, which compares against the previous login,
+// this rule is stateless: it checks input.CountryCode against a fixed list.
+// It supports compliance requirements such as "service only available in
+// the EU" (allowlist) or sanctions screening (blocklist).
+//
+// Behavior:
+//   - PolicyAllow: triggers when the country is NOT in Countries
+//   - PolicyBlock: triggers when the country IS in Countries
+//   - Country codes are ISO 3166-1 alpha-2 and compared case-insensitively
+//   - An unknown country (empty CountryCode, e.g. unresolvable IP) triggers
+//     only when FailOnUnknown is set
+//
+// Note: The country comes from GeoIP, so VPNs and proxies bypass this rule.
+// Combine it with DataCenterRule or OpenProxyRule for enforcement.
+type CountryPolicyRule struct {
+	Countries     map[string]bool // Uppercase ISO country codes
+	Mode          PolicyMode      // PolicyAllow or PolicyBlock
+	FailOnUnknown bool            // Trigger when the country cannot be determined
+	RiskScore     int             // Points to add when the policy is violated
+}
+
+// NewCountryPolicyRule creates a country allowlist or blocklist rule.
+// Unknown countries pass by default; set FailOnUnknown to reject them.
+//
+// Parameters:
+//   - countries: ISO 3166-1 alpha-2 codes (e.g., "DE", "fr")
+//   - mode: PolicyAllow or PolicyBlock
+//   - score: Risk points to add when the policy is violated
+//
+// Example:
+//
+//	euOnly := rules.NewCountryPolicyRule([]string{"DE", "FR", "NL"}, rules.PolicyAllow, 100)
+//	euOnly.FailOnUnknown = true
+func NewCountryPolicyRule(countries []string, mode PolicyMode, score int) *CountryPolicyRule {
+	set := make(map[string]bool, len(countries))
+	for _, country := range countries {
+		if code := strings.ToUpper(strings.TrimSpace(country)); code != "" {
+			set[code] = true
+		}
+	}
+
+	return &CountryPolicyRule{
+		Countries: set,
+		Mode:      mode,
+		RiskScore: score,
+	}
+}
+
+func (c *CountryPolicyRule) Name() string {
+	return "Country Policy"
+}
+
+func (c *CountryPolicyRule) Description() string {
+	if c.Mode == PolicyBlock {
+		return fmt.Sprintf("Flags logins from %d blocked countries.", len(c.Countries))
+	}
+	return fmt.Sprintf("Flags logins from outside %d allowed countries.", len(c.Countries))
+}
+
+func (c *CountryPolicyRule) Category() string {
+	return models.CategoryLocation
+}
+
+func (c *CountryPolicyRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	if input.CountryCode == "" {
+		if c.FailOnUnknown {
+			return c.RiskScore, nil
+		}
+		return 0, nil
+	}
+
+	listed := c.Countries[strings.ToUpper(input.CountryCode)]
+
+	switch c.Mode {
+	case PolicyAllow:
+		if !listed {
+			return c.RiskScore, nil
+		}
+	case PolicyBlock:
+		if listed {
+			return c.RiskScore, nil
+		}
+	default:
+		return 0, fmt.Errorf("unknown country policy mode %q", c.Mode)
+	}
+
+	return 0, nil
+}
+
+// Explain reports the evaluated country and the policy mode.
+// Implements ExplainableRule interface.
+func (c *CountryPolicyRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	return map[string]any{
+		"country": input.CountryCode,
+		"mode":    string(c.Mode),
+	}
+}
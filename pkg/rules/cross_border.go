@@ -0,0 +1,146 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// CrossBorderRule flags IP and GPS locations deep inside two different countries.
+//
+// A plain country comparison misfires in border towns: an IP geolocated a few
+// kilometers across the border from the device is routine (cross-border
+// carriers, regional ISPs). What is suspicious is an IP deep inside country A
+// while GPS is deep inside country B.
+//
+// Heuristic:
+//   - Reverse-geocode both the IP and device coordinates via Resolver
+//   - If the countries differ, estimate each point's distance to its nearest border
+//   - Trigger only when BOTH points are at least MinBorderDistanceKm inside
+//     their respective countries
+//
+// Behavior:
+//   - Skips when Resolver is nil, GPS is unavailable, or IP location is unavailable
+//   - Skips when either point cannot be placed in a country
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule interface
+//   - Coordinates are resolved ephemerally and never persisted
+type CrossBorderRule struct {
+	Resolver            BorderResolver // Reverse geocoder with border distance estimation
+	MinBorderDistanceKm float64        // Minimum depth inside each country to trigger
+	RiskScore           int            // Points to add when rule triggers
+}
+
+// NewCrossBorderRule creates a new cross-border IP/GPS detection rule.
+//
+// Parameters:
+//   - resolver: Integrator-supplied reverse geocoder with border distances
+//   - minBorderDistanceKm: Depth inside each country required to trigger (recommend 50)
+//   - score: Risk points to add when triggered
+func NewCrossBorderRule(resolver BorderResolver, minBorderDistanceKm float64, score int) *CrossBorderRule {
+	return &CrossBorderRule{
+		Resolver:            resolver,
+		MinBorderDistanceKm: minBorderDistanceKm,
+		RiskScore:           score,
+	}
+}
+
+func (c *CrossBorderRule) Name() string {
+	return "Cross-Border IP/GPS"
+}
+
+func (c *CrossBorderRule) Description() string {
+	return fmt.Sprintf("Detects IP and GPS more than %.0f km inside different countries.", c.MinBorderDistanceKm)
+}
+
+func (c *CrossBorderRule) Category() string {
+	return models.CategoryLocation
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because this rule requires ephemeral coordinates via ValidateWithGeo.
+func (c *CrossBorderRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo compares the countries of the IP and device locations.
+// Implements EphemeralGeoRule interface.
+func (c *CrossBorderRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	if c.Resolver == nil {
+		return 0, nil
+	}
+
+	// Skip if GPS is not available
+	if ctx.DeviceLatitude == 0 && ctx.DeviceLongitude == 0 {
+		return 0, nil
+	}
+
+	// Skip if IP location is not available
+	if ctx.IPLatitude == 0 && ctx.IPLongitude == 0 {
+		return 0, nil
+	}
+
+	ipCountry, err := c.Resolver.CountryAt(ctx.IPLatitude, ctx.IPLongitude)
+	if err != nil {
+		return 0, err
+	}
+	gpsCountry, err := c.Resolver.CountryAt(ctx.DeviceLatitude, ctx.DeviceLongitude)
+	if err != nil {
+		return 0, err
+	}
+
+	if ipCountry == "" || gpsCountry == "" || ipCountry == gpsCountry {
+		return 0, nil
+	}
+
+	// Border-town tolerance: both points must be well inside their countries
+	ipDepth, err := c.Resolver.BorderDistanceKm(ctx.IPLatitude, ctx.IPLongitude)
+	if err != nil {
+		return 0, err
+	}
+	if ipDepth < c.MinBorderDistanceKm {
+		return 0, nil
+	}
+
+	gpsDepth, err := c.Resolver.BorderDistanceKm(ctx.DeviceLatitude, ctx.DeviceLongitude)
+	if err != nil {
+		return 0, err
+	}
+	if gpsDepth < c.MinBorderDistanceKm {
+		return 0, nil
+	}
+
+	return c.RiskScore, nil
+}
+
+// Explain reports both countries and how far inside them IP and GPS are.
+// Implements ExplainableRule interface.
+func (c *CrossBorderRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	if c.Resolver == nil {
+		return nil
+	}
+	ipCountry, err := c.Resolver.CountryAt(ctx.IPLatitude, ctx.IPLongitude)
+	if err != nil {
+		return nil
+	}
+	gpsCountry, err := c.Resolver.CountryAt(ctx.DeviceLatitude, ctx.DeviceLongitude)
+	if err != nil {
+		return nil
+	}
+	ipDepth, err := c.Resolver.BorderDistanceKm(ctx.IPLatitude, ctx.IPLongitude)
+	if err != nil {
+		return nil
+	}
+	gpsDepth, err := c.Resolver.BorderDistanceKm(ctx.DeviceLatitude, ctx.DeviceLongitude)
+	if err != nil {
+		return nil
+	}
+	return map[string]any{
+		"ip_country":             ipCountry,
+		"gps_country":            gpsCountry,
+		"ip_border_distance_km":  roundTenth(ipDepth),
+		"gps_border_distance_km": roundTenth(gpsDepth),
+		"min_border_distance_km": c.MinBorderDistanceKm,
+	}
+}
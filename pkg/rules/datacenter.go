@@ -1,6 +1,9 @@
 package rules
 
 import (
+	"maps"
+	"sync"
+
 	"github.com/gokaycavdar/go-geoguard/pkg/models"
 )
 
@@ -36,7 +39,31 @@ func DataCenter(blacklist map[uint]string, score int) *DataCenterRule {
 // DefaultDataCenterRule creates a rule with common cloud provider ASNs.
 // This includes major providers like AWS, Google Cloud, Azure, etc.
 func DefaultDataCenterRule(score int) *DataCenterRule {
-	blacklist := map[uint]string{
+	return DataCenter(defaultDataCenterASNs(), score)
+}
+
+// defaultASNs is the built-in ASN list, built once on first use.
+// Never handed out directly; see defaultDataCenterASNs.
+var (
+	defaultASNs     map[uint]string
+	defaultASNsOnce sync.Once
+)
+
+// defaultDataCenterASNs returns the built-in list of cloud/hosting provider ASNs.
+// Shared by rules that need to recognize data center networks.
+//
+// The shared list is built once and each caller receives a defensive copy,
+// so mutating one rule's BlacklistedASNs never leaks into another rule.
+func defaultDataCenterASNs() map[uint]string {
+	defaultASNsOnce.Do(func() {
+		defaultASNs = buildDefaultDataCenterASNs()
+	})
+	return maps.Clone(defaultASNs)
+}
+
+// buildDefaultDataCenterASNs constructs the built-in ASN list.
+func buildDefaultDataCenterASNs() map[uint]string {
+	return map[uint]string{
 		// Major Cloud Providers
 		16509:  "Amazon.com (AWS)",
 		14618:  "Amazon.com (AWS)",
@@ -63,7 +90,6 @@ func DefaultDataCenterRule(score int) *DataCenterRule {
 		46606: "Unified Layer",
 		36352: "ColoCrossing",
 	}
-	return DataCenter(blacklist, score)
 }
 
 func (d *DataCenterRule) Name() string {
@@ -74,6 +100,10 @@ func (d *DataCenterRule) Description() string {
 	return "Detects if IP belongs to a known cloud/hosting provider."
 }
 
+func (d *DataCenterRule) Category() string {
+	return models.CategoryNetwork
+}
+
 func (d *DataCenterRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
 	if input.ASN == 0 {
 		return 0, nil
@@ -84,4 +114,13 @@ func (d *DataCenterRule) Validate(input models.LoginRecord, lastRecord *models.L
 	}
 
 	return 0, nil
-}
\ No newline at end of file
+}
+
+// Explain reports the matched ASN and its organization.
+// Implements ExplainableRule interface.
+func (d *DataCenterRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	return map[string]any{
+		"asn": input.ASN,
+		"org": input.OrgName,
+	}
+}
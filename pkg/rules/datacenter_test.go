@@ -0,0 +1,38 @@
+package rules
+
+import "testing"
+
+func TestDefaultDataCenterASNsAreIsolated(t *testing.T) {
+	const aws = 16509
+
+	first := DefaultDataCenterRule(20)
+	delete(first.BlacklistedASNs, aws)
+	first.BlacklistedASNs[64512] = "Private test ASN"
+
+	second := DefaultDataCenterRule(20)
+	if _, ok := second.BlacklistedASNs[aws]; !ok {
+		t.Error("deleting from one rule's list removed the ASN from a new rule")
+	}
+	if _, ok := second.BlacklistedASNs[64512]; ok {
+		t.Error("adding to one rule's list leaked into a new rule")
+	}
+	if _, ok := NewGPSFromDatacenterRule(20).DataCenterASNs[aws]; !ok {
+		t.Error("mutating a DataCenterRule changed GPSFromDatacenterRule's list")
+	}
+	if _, ok := defaultDataCenterASNs()[aws]; !ok {
+		t.Error("mutating a rule changed the shared default list")
+	}
+}
+
+func BenchmarkDefaultDataCenterRule(b *testing.B) {
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			DefaultDataCenterRule(20)
+		}
+	})
+	b.Run("rebuilt", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			DataCenter(buildDefaultDataCenterASNs(), 20)
+		}
+	})
+}
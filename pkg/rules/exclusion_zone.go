@@ -0,0 +1,91 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// ExclusionZoneRule flags logins originating inside a restricted geographic area.
+//
+// This is the logical complement of GeofencingRule: instead of flagging
+// locations outside an allowed circle, it flags locations within a
+// forbidden one.
+//
+// Use cases:
+//   - Sanctioned or embargoed regions
+//   - Known fraud hotspots
+//   - Competitor campuses or other locations that should never log in
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule interface
+//   - Coordinates are passed via GeoContext (never persisted)
+type ExclusionZoneRule struct {
+	CenterLat float64 // Latitude of the restricted area center
+	CenterLon float64 // Longitude of the restricted area center
+	RadiusKm  float64 // Restricted radius in kilometers
+	RiskScore int     // Points to add when inside the restricted area
+}
+
+// NewExclusionZoneRule creates a new restricted-area rule.
+//
+// Parameters:
+//   - lat, lon: Center coordinates of the restricted area
+//   - radiusKm: Restricted radius in kilometers
+//   - score: Risk points to add when the IP location is inside the area
+func NewExclusionZoneRule(lat, lon, radiusKm float64, score int) *ExclusionZoneRule {
+	return &ExclusionZoneRule{
+		CenterLat: lat,
+		CenterLon: lon,
+		RadiusKm:  radiusKm,
+		RiskScore: score,
+	}
+}
+
+func (e *ExclusionZoneRule) Name() string {
+	return "Exclusion Zone"
+}
+
+func (e *ExclusionZoneRule) Description() string {
+	return fmt.Sprintf("Flags locations within %.1f km of a restricted area.", e.RadiusKm)
+}
+
+func (e *ExclusionZoneRule) Category() string {
+	return models.CategoryLocation
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because this rule requires ephemeral coordinates via ValidateWithGeo.
+func (e *ExclusionZoneRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo checks whether the IP location falls inside the restricted area.
+// Implements EphemeralGeoRule interface.
+func (e *ExclusionZoneRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	// Cannot validate without coordinates
+	if ctx.IPLatitude == 0 && ctx.IPLongitude == 0 {
+		return 0, nil
+	}
+
+	distance := haversine(e.CenterLat, e.CenterLon, ctx.IPLatitude, ctx.IPLongitude)
+
+	// Trigger if inside the restricted radius
+	if distance <= e.RadiusKm {
+		return e.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports the distance from the restricted area center.
+// Implements ExplainableRule interface.
+func (e *ExclusionZoneRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	if ctx.IPLatitude == 0 && ctx.IPLongitude == 0 {
+		return nil
+	}
+	return map[string]any{
+		"distance_km": roundTenth(haversine(e.CenterLat, e.CenterLon, ctx.IPLatitude, ctx.IPLongitude)),
+		"radius_km":   e.RadiusKm,
+	}
+}
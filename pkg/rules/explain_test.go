@@ -0,0 +1,167 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+func TestBuiltinRulesAreExplainable(t *testing.T) {
+	square := [][2]float64{{0, 0}, {0, 1}, {1, 1}, {1, 0}}
+	builtins := []Rule{
+		NewAnonymousIPRule(1), NewASNChangeRule(1), NewBotUserAgentRule(1),
+		NewBusinessHoursRule(9, 17, 1), NewCityChangeRule(1), NewCityChurnRule(3, time.Hour, 1),
+		NewCoordCountryConsistencyRule(nil, 1), DefaultCorridorRule(), NewCountryConfidenceRule(nil, 1, 1),
+		CountryMismatch(1), NewCountryPolicyRule([]string{"TR"}, PolicyAllow, 1), NewCrossBorderRule(nil, 50, 1),
+		DefaultDataCenterRule(1), NewExclusionZoneRule(0, 0, 1, 1), NewFailedAttemptShiftRule(3, time.Hour, 1),
+		Fingerprint(1), Geofencing(0, 0, 1, 1), NewGPSFromDatacenterRule(1), NewGPSTimezoneRule(1),
+		NewHeaderConsistencyRule(1), NewHomeDistanceRule(100, 1), IPGPS(100, 1),
+		NewLanguageCountryRule(nil, 1), NewLocaleTimezoneRule(1), NewLocationConsensusRule(nil, 1),
+		NewLoginFrequencyRule(5, time.Hour, 1), NewLongitudeTimezoneRule(3, 1), NewMobileStationaryGPSRule(3, 1),
+		OpenProxy(nil, 1), NewOrgChangeRule(1), NewPerCountryRadiusRule(nil, 1), NewPingPongRule(24, 1),
+		NewPlatformSwitchRule(2, time.Hour, 1), NewPolygonGeofenceRule(square, 1), NewPrivateIPRule(1),
+		NewRepeatedGPSRule(3, 1), NewReplayRule(time.Second, 1), NewRoundGPSHistoryRule(3, 1),
+		NewSharedIPRule(10, time.Hour, 1), NewSubnetReputationRule(nil, 50, 1), NewTimestampSanityRule(time.Hour, 1),
+		&TorExitRule{}, NewTrustAdjustmentRule(1), NewUninhabitableRule(1), NewUnlocatableIPRule(1),
+		Timezone(1), Velocity(900, 1),
+	}
+	for _, rule := range builtins {
+		_, explainable := rule.(ExplainableRule)
+		_, historyExplainable := rule.(HistoryExplainableRule)
+		if !explainable && !historyExplainable {
+			t.Errorf("%T implements neither ExplainableRule nor HistoryExplainableRule", rule)
+		}
+	}
+}
+
+func TestExplainDetails(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	last := &models.LoginRecord{
+		Timestamp:       now.Add(-time.Hour),
+		CountryCode:     "TR",
+		FingerprintHash: "aaa",
+		Platform:        PlatformWindows,
+	}
+	input := models.LoginRecord{
+		Timestamp:       now,
+		MaskedIPPrefix:  "185.220.101.0/24",
+		CountryCode:     "RU",
+		FingerprintHash: "bbb",
+		Platform:        PlatformLinux,
+		ClientTimezone:  "America/New_York",
+	}
+
+	tests := []struct {
+		name string
+		rule ExplainableRule
+		ctx  GeoContext
+		want map[string]any
+	}{
+		{
+			name: "corridor",
+			rule: NewCorridorRule(map[[2]string]int{{"TR", "RU"}: 30}),
+			want: map[string]any{"from_country": "TR", "to_country": "RU"},
+		},
+		{
+			name: "fingerprint",
+			rule: Fingerprint(20),
+			want: map[string]any{
+				"from_fingerprint": "aaa", "to_fingerprint": "bbb",
+				"from_platform": PlatformWindows, "to_platform": PlatformLinux,
+			},
+		},
+		{
+			name: "open proxy",
+			rule: DefaultOpenProxyRule(40),
+			want: map[string]any{"masked_ip_prefix": "185.220.101.0/24"},
+		},
+		{
+			name: "language country",
+			rule: NewLanguageCountryRule(nil, 15),
+			ctx:  GeoContext{AcceptLanguage: "zh-CN,zh;q=0.9,ja;q=0.8"},
+			want: map[string]any{"country": "RU", "languages": []string{"zh", "ja"}},
+		},
+		{
+			name: "locale timezone",
+			rule: NewLocaleTimezoneRule(15),
+			ctx:  GeoContext{AcceptLanguage: "ja-JP,ja;q=0.9"},
+			want: map[string]any{"language": "ja", "client_timezone": "America/New_York"},
+		},
+		{
+			name: "bot user agent",
+			rule: NewBotUserAgentRule(40),
+			ctx:  GeoContext{UserAgent: "python-requests/2.31.0"},
+			want: map[string]any{"signature": "python-requests"},
+		},
+		{
+			name: "empty user agent",
+			rule: NewBotUserAgentRule(40),
+			want: map[string]any{"empty_user_agent": true},
+		},
+		{
+			name: "replay",
+			rule: NewReplayRule(2*time.Hour, 10),
+			want: map[string]any{"elapsed_seconds": 3600.0, "min_interval_seconds": 7200.0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Explain(tt.ctx, input, last); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Explain = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExplainWithHistoryDetails(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	input := models.LoginRecord{Timestamp: now, CountryCode: "TR", CityGeonameID: 1, Platform: PlatformIOS}
+	history := []*models.LoginRecord{
+		{Timestamp: now.Add(-10 * time.Minute), CountryCode: "BR", CityGeonameID: 2, Platform: PlatformAndroid, Outcome: models.OutcomeFailure},
+		{Timestamp: now.Add(-20 * time.Minute), CountryCode: "BR", CityGeonameID: 3, Platform: PlatformWindows, Outcome: models.OutcomeFailure},
+		{Timestamp: now.Add(-30 * time.Minute), CountryCode: "CN", CityGeonameID: 3, Platform: PlatformWindows, Outcome: models.OutcomeFailure},
+		{Timestamp: now.Add(-48 * time.Hour), CountryCode: "CN", CityGeonameID: 4, Platform: PlatformMacOS}, // Outside every window
+	}
+
+	tests := []struct {
+		name string
+		rule HistoryExplainableRule
+		want map[string]any
+	}{
+		{
+			name: "city churn",
+			rule: NewCityChurnRule(2, time.Hour, 20),
+			want: map[string]any{"distinct_cities": 3, "max_distinct_cities": 2, "window_minutes": 60.0},
+		},
+		{
+			name: "platform switch",
+			rule: NewPlatformSwitchRule(2, time.Hour, 20),
+			want: map[string]any{
+				"platforms":     []string{PlatformAndroid, PlatformIOS, PlatformWindows},
+				"max_platforms": 2, "window_minutes": 60.0,
+			},
+		},
+		{
+			name: "failed attempt shift",
+			rule: NewFailedAttemptShiftRule(1, time.Hour, 30),
+			want: map[string]any{"failure_country": "BR", "failed_attempts": 2, "country": "TR", "window_minutes": 60.0},
+		},
+		{
+			name: "login frequency",
+			rule: NewLoginFrequencyRule(3, time.Hour, 10),
+			want: map[string]any{"logins": 4, "max_logins": 3, "window_minutes": 60.0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if score, _ := tt.rule.ValidateWithHistory(input, history); score == 0 {
+				t.Fatal("rule did not trigger")
+			}
+			if got := tt.rule.ExplainWithHistory(input, history); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExplainWithHistory = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
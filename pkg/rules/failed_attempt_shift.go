@@ -0,0 +1,145 @@
+package rules
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// FailedAttemptShiftRule detects a login following a cluster of failed attempts elsewhere.
+//
+// A common account-takeover pattern: several failed attempts from the
+// attacker's location, then a success once the password is guessed. When
+// failed attempts within the window cluster in a different country than the
+// current login, the current login may be the attacker's success (or the
+// legitimate user logging in while an attack is underway).
+//
+// Requirements:
+//   - The application must save records for failed attempts with
+//     Outcome = models.OutcomeFailure (via engine.Input.Outcome)
+//   - The store must implement storage.RecentHistoryStore
+//   - Without logged failures the rule never triggers (no-op)
+//
+// Behavior:
+//   - Logins marked as failures are not evaluated themselves
+//   - Skips when the current country is unknown
+//   - Without recent-history support, only the single last record is checked
+//
+// Implements HistoryRule interface.
+type FailedAttemptShiftRule struct {
+	MinFailures int           // Failed attempts from one other country required to trigger
+	Window      time.Duration // How far back failed attempts are considered
+	RiskScore   int           // Points to add when rule triggers
+}
+
+// NewFailedAttemptShiftRule creates a new failed-then-success location shift rule.
+//
+// Parameters:
+//   - minFailures: Failed attempts from another country required (recommend 3)
+//   - window: Lookback window for failed attempts (recommend 1 hour)
+//   - score: Risk points to add when triggered
+func NewFailedAttemptShiftRule(minFailures int, window time.Duration, score int) *FailedAttemptShiftRule {
+	return &FailedAttemptShiftRule{
+		MinFailures: minFailures,
+		Window:      window,
+		RiskScore:   score,
+	}
+}
+
+func (f *FailedAttemptShiftRule) Name() string {
+	return "Failed Attempts From Other Location"
+}
+
+func (f *FailedAttemptShiftRule) Description() string {
+	return fmt.Sprintf("Detects logins following %d+ failed attempts from another country within %s.", f.MinFailures, f.Window)
+}
+
+func (f *FailedAttemptShiftRule) Category() string {
+	return models.CategoryBehavior
+}
+
+// Stateful reports that this rule requires historical login data.
+func (f *FailedAttemptShiftRule) Stateful() bool {
+	return true
+}
+
+// Validate falls back to checking only the last record when history is unavailable.
+func (f *FailedAttemptShiftRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
+	if last == nil {
+		return 0, nil
+	}
+	return f.ValidateWithHistory(input, []*models.LoginRecord{last})
+}
+
+// ValidateWithHistory counts recent failed attempts per country.
+// Implements HistoryRule interface.
+func (f *FailedAttemptShiftRule) ValidateWithHistory(input models.LoginRecord, history []*models.LoginRecord) (int, error) {
+	if country, _ := f.failureCountry(input, history); country != "" {
+		return f.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports the failures found in the last record alone.
+// Implements ExplainableRule interface.
+func (f *FailedAttemptShiftRule) Explain(ctx GeoContext, input models.LoginRecord, last *models.LoginRecord) map[string]any {
+	if last == nil {
+		return nil
+	}
+	return f.ExplainWithHistory(input, []*models.LoginRecord{last})
+}
+
+// ExplainWithHistory reports the country of the failed attempts and their count.
+// Implements HistoryExplainableRule interface.
+func (f *FailedAttemptShiftRule) ExplainWithHistory(input models.LoginRecord, history []*models.LoginRecord) map[string]any {
+	country, failures := f.failureCountry(input, history)
+	if country == "" {
+		return nil
+	}
+	return map[string]any{
+		"failure_country": country,
+		"failed_attempts": failures,
+		"country":         input.CountryCode,
+		"window_minutes":  f.Window.Minutes(),
+	}
+}
+
+// failureCountry returns the other country with the most failed attempts
+// within Window (at least MinFailures), and that count. Returns "" if none.
+func (f *FailedAttemptShiftRule) failureCountry(input models.LoginRecord, history []*models.LoginRecord) (string, int) {
+	// Failed attempts are evidence, not the login being assessed
+	if input.Outcome == models.OutcomeFailure {
+		return "", 0
+	}
+
+	// Cannot compare without a current location
+	if input.CountryCode == "" {
+		return "", 0
+	}
+
+	failuresByCountry := make(map[string]int)
+	for _, record := range history {
+		if record.Outcome != models.OutcomeFailure || record.CountryCode == "" {
+			continue
+		}
+		if input.Timestamp.Sub(record.Timestamp) > f.Window {
+			continue
+		}
+		failuresByCountry[record.CountryCode]++
+	}
+
+	var shifted string
+	failures := 0
+	for country, count := range failuresByCountry {
+		if country == input.CountryCode || count < f.MinFailures {
+			continue
+		}
+		// Ties go to the lower country code so the result is deterministic
+		if count > failures || (count == failures && country < shifted) {
+			shifted, failures = country, count
+		}
+	}
+	return shifted, failures
+}
@@ -38,6 +38,15 @@ func (f *FingerprintRule) Description() string {
 	return "Detects changes in device fingerprint (UserAgent + Language hash)."
 }
 
+func (f *FingerprintRule) Category() string {
+	return models.CategoryDevice
+}
+
+// Stateful reports that this rule requires historical login data.
+func (f *FingerprintRule) Stateful() bool {
+	return true
+}
+
 func (f *FingerprintRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
 	// First login - nothing to compare
 	if last == nil {
@@ -52,10 +61,24 @@ func (f *FingerprintRule) Validate(input models.LoginRecord, last *models.LoginR
 	return 0, nil
 }
 
+// Explain reports the previous and current fingerprints and platforms.
+// Implements ExplainableRule interface.
+func (f *FingerprintRule) Explain(ctx GeoContext, input models.LoginRecord, last *models.LoginRecord) map[string]any {
+	if last == nil {
+		return nil
+	}
+	return map[string]any{
+		"from_fingerprint": last.FingerprintHash,
+		"to_fingerprint":   input.FingerprintHash,
+		"from_platform":    last.Platform,
+		"to_platform":      input.Platform,
+	}
+}
+
 // GenerateFingerprintHash creates a SHA256 hash from UserAgent and Language.
 // This function should be called by the engine when creating LoginRecords.
 func GenerateFingerprintHash(userAgent, language string) string {
 	data := userAgent + "|" + language
 	hash := sha256.Sum256([]byte(data))
 	return hex.EncodeToString(hash[:])
-}
\ No newline at end of file
+}
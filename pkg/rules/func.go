@@ -0,0 +1,81 @@
+package rules
+
+import "github.com/gokaycavdar/go-geoguard/pkg/models"
+
+// Func adapts an ordinary function to the Rule interface.
+//
+// This lowers the barrier for one-off custom checks: no struct with
+// Name/Description/Validate methods is needed.
+//
+// Example:
+//
+//	guard.AddRule(rules.Func("Blocked ASN", "Flags logins from ASN 64512.",
+//	    func(input, last *models.LoginRecord) (int, error) {
+//	        if input.ASN == 64512 {
+//	            return 50, nil
+//	        }
+//	        return 0, nil
+//	    }))
+//
+// The input pointer refers to a per-evaluation copy; last is nil on first login.
+func Func(name, description string, fn func(input, last *models.LoginRecord) (int, error)) Rule {
+	return &funcRule{name: name, description: description, fn: fn}
+}
+
+// GeoFunc adapts an ordinary function to the EphemeralGeoRule interface.
+// The engine detects the result as an EphemeralGeoRule and passes GeoContext.
+//
+// Example:
+//
+//	guard.AddRule(rules.GeoFunc("Southern Hemisphere", "Flags IPs south of the equator.",
+//	    func(ctx rules.GeoContext, input, last *models.LoginRecord) (int, error) {
+//	        if ctx.IPLatitude < 0 {
+//	            return 20, nil
+//	        }
+//	        return 0, nil
+//	    }))
+func GeoFunc(name, description string, fn func(ctx GeoContext, input, last *models.LoginRecord) (int, error)) EphemeralGeoRule {
+	return &geoFuncRule{funcRule: funcRule{name: name, description: description}, fn: fn}
+}
+
+// funcRule is the Rule returned by Func.
+type funcRule struct {
+	name        string
+	description string
+	fn          func(input, last *models.LoginRecord) (int, error)
+}
+
+func (f *funcRule) Name() string {
+	return f.name
+}
+
+func (f *funcRule) Description() string {
+	return f.description
+}
+
+func (f *funcRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
+	if f.fn == nil {
+		return 0, nil
+	}
+	return f.fn(&input, last)
+}
+
+// geoFuncRule is the EphemeralGeoRule returned by GeoFunc.
+type geoFuncRule struct {
+	funcRule
+	fn func(ctx GeoContext, input, last *models.LoginRecord) (int, error)
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because this rule requires ephemeral coordinates via ValidateWithGeo.
+func (g *geoFuncRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo calls the wrapped function with the ephemeral context.
+func (g *geoFuncRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, last *models.LoginRecord) (int, error) {
+	if g.fn == nil {
+		return 0, nil
+	}
+	return g.fn(ctx, &input, last)
+}
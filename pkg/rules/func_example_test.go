@@ -0,0 +1,50 @@
+package rules_test
+
+import (
+	"fmt"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+	"github.com/gokaycavdar/go-geoguard/pkg/rules"
+)
+
+func ExampleFunc() {
+	rule := rules.Func("Blocked ASN", "Flags logins from ASN 64512.",
+		func(input, last *models.LoginRecord) (int, error) {
+			if input.ASN == 64512 {
+				return 50, nil
+			}
+			return 0, nil
+		})
+
+	// Normally registered with guard.AddRule(rule); called directly here
+	score, _ := rule.Validate(models.LoginRecord{UserID: "alice", ASN: 64512}, nil)
+	fmt.Println(rule.Name(), score)
+
+	score, _ = rule.Validate(models.LoginRecord{UserID: "alice", ASN: 9121}, nil)
+	fmt.Println(rule.Name(), score)
+	// Output:
+	// Blocked ASN 50
+	// Blocked ASN 0
+}
+
+func ExampleGeoFunc() {
+	rule := rules.GeoFunc("Southern Hemisphere", "Flags IPs south of the equator.",
+		func(ctx rules.GeoContext, input, last *models.LoginRecord) (int, error) {
+			if ctx.IPLatitude < 0 {
+				return 20, nil
+			}
+			return 0, nil
+		})
+
+	// The engine passes the ephemeral GeoContext of each login
+	sydney := rules.GeoContext{IPLatitude: -33.87, IPLongitude: 151.21}
+	score, _ := rule.ValidateWithGeo(sydney, models.LoginRecord{UserID: "alice"}, nil)
+	fmt.Println(rule.Name(), score)
+
+	istanbul := rules.GeoContext{IPLatitude: 41.01, IPLongitude: 28.98}
+	score, _ = rule.ValidateWithGeo(istanbul, models.LoginRecord{UserID: "alice"}, nil)
+	fmt.Println(rule.Name(), score)
+	// Output:
+	// Southern Hemisphere 20
+	// Southern Hemisphere 0
+}
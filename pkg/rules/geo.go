@@ -0,0 +1,69 @@
+package rules
+
+import "math"
+
+// earthRadiusKm is the mean Earth radius used by the spherical formulas.
+const earthRadiusKm = 6371.0
+
+// DistanceKm returns the great-circle distance between two coordinates in
+// kilometers, using the Haversine formula on a spherical Earth (error below
+// 0.5% versus the WGS84 ellipsoid).
+//
+// Custom rules should use it instead of their own implementation so that
+// distances agree with VelocityRule, IPGPSRule, and the geofencing rules.
+//
+// Example:
+//
+//	// Istanbul -> Ankara, about 350 km
+//	km := rules.DistanceKm(41.01, 28.98, 39.93, 32.86)
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+
+	lat1 = toRadians(lat1)
+	lat2 = toRadians(lat2)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Sin(dLon/2)*math.Sin(dLon/2)*math.Cos(lat1)*math.Cos(lat2)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+
+	return earthRadiusKm * c
+}
+
+// Bearing returns the initial great-circle bearing from the first coordinate
+// to the second in degrees clockwise from true north, in [0, 360).
+//
+// Behavior:
+//   - 0 is north, 90 east, 180 south, 270 west
+//   - The bearing along a great circle changes en route; this is the
+//     heading at the starting point
+//   - Identical points return 0
+//
+// Direction-aware rules can compare consecutive bearings, e.g. hops that
+// reverse direction (~180° apart) repeatedly suggest oscillating VPN exits.
+//
+// Example:
+//
+//	// Istanbul -> Ankara, about 109° (east-southeast)
+//	deg := rules.Bearing(41.01, 28.98, 39.93, 32.86)
+func Bearing(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := toRadians(lat1), toRadians(lat2)
+	dLon := toRadians(lon2 - lon1)
+
+	y := math.Sin(dLon) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dLon)
+	if x == 0 && y == 0 {
+		return 0
+	}
+
+	degrees := math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+	if degrees >= 360 {
+		degrees = 0
+	}
+	return degrees
+}
+
+// toRadians converts degrees to radians.
+func toRadians(degrees float64) float64 {
+	return degrees * (math.Pi / 180.0)
+}
@@ -21,6 +21,9 @@ import (
 // Architecture:
 //   - Engine owns GeoIP lookup; rule receives only derived coordinates
 //   - Rule is testable with mock GeoContext values
+//
+// For the inverse (trigger inside a forbidden circle) use ExclusionZoneRule;
+// for non-circular areas use PolygonGeofenceRule or NewPolygonExclusionRule.
 type GeofencingRule struct {
 	CenterLat float64 // Latitude of the allowed area center
 	CenterLon float64 // Longitude of the allowed area center
@@ -51,6 +54,10 @@ func (g *GeofencingRule) Description() string {
 	return fmt.Sprintf("Verifies location is within %.1f km of allowed area.", g.RadiusKm)
 }
 
+func (g *GeofencingRule) Category() string {
+	return models.CategoryLocation
+}
+
 // Validate satisfies the Rule interface.
 // Returns 0 because this rule requires ephemeral coordinates via ValidateWithGeo.
 func (g *GeofencingRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
@@ -76,4 +83,16 @@ func (g *GeofencingRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecor
 	}
 
 	return 0, nil
-}
\ No newline at end of file
+}
+
+// Explain reports the distance from the allowed area center.
+// Implements ExplainableRule interface.
+func (g *GeofencingRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	if ctx.IPLatitude == 0 && ctx.IPLongitude == 0 {
+		return nil
+	}
+	return map[string]any{
+		"distance_km":     roundTenth(haversine(g.CenterLat, g.CenterLon, ctx.IPLatitude, ctx.IPLongitude)),
+		"max_distance_km": g.RadiusKm,
+	}
+}
@@ -0,0 +1,80 @@
+package rules
+
+import (
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// GPSFromDatacenterRule flags precise device GPS arriving from a data center IP.
+//
+// Real users on mobile or residential networks share GPS routinely, but real
+// users almost never browse from cloud/hosting infrastructure. A precise GPS
+// reading paired with a data center ASN is therefore implausible: it usually
+// means a spoofing tool or emulator is injecting coordinates while the traffic
+// is relayed through a server.
+//
+// Behavior:
+//   - Skips when no GPS data was provided
+//   - Skips when the ASN is unknown or not a known data center
+//   - Emits a single correlated score (use instead of stacking GPS and ASN signals)
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule interface
+//   - Only the presence of GPS is inspected; coordinates are never persisted
+type GPSFromDatacenterRule struct {
+	DataCenterASNs map[uint]string // ASN -> Provider name
+	RiskScore      int             // Points to add when GPS arrives from a data center
+}
+
+// NewGPSFromDatacenterRule creates a rule using the default data center ASN list.
+func NewGPSFromDatacenterRule(score int) *GPSFromDatacenterRule {
+	return &GPSFromDatacenterRule{
+		DataCenterASNs: defaultDataCenterASNs(),
+		RiskScore:      score,
+	}
+}
+
+func (g *GPSFromDatacenterRule) Name() string {
+	return "GPS From Data Center"
+}
+
+func (g *GPSFromDatacenterRule) Description() string {
+	return "Device reported precise GPS while connecting from a cloud/hosting provider network."
+}
+
+func (g *GPSFromDatacenterRule) Category() string {
+	return models.CategoryNetwork
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because this rule requires ephemeral coordinates via ValidateWithGeo.
+func (g *GPSFromDatacenterRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo correlates GPS presence with a data center ASN.
+// Implements EphemeralGeoRule interface.
+func (g *GPSFromDatacenterRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	// Skip if no GPS data provided
+	if ctx.DeviceLatitude == 0 && ctx.DeviceLongitude == 0 {
+		return 0, nil
+	}
+
+	// Skip residential or unknown networks
+	if input.ASN == 0 {
+		return 0, nil
+	}
+	if _, exists := g.DataCenterASNs[input.ASN]; !exists {
+		return 0, nil
+	}
+
+	return g.RiskScore, nil
+}
+
+// Explain reports the matched ASN and its organization.
+// Implements ExplainableRule interface.
+func (g *GPSFromDatacenterRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	return map[string]any{
+		"asn": input.ASN,
+		"org": input.OrgName,
+	}
+}
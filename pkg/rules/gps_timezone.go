@@ -0,0 +1,122 @@
+package rules
+
+import (
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// GPSTimezoneRule detects a client timezone that is impossible for the device GPS.
+//
+// If the device sends both GPS and a timezone, they should agree: GPS in Tokyo
+// with a "Europe/Istanbul" browser timezone is contradictory. Spoofing tools
+// often fake one signal but not the other.
+//
+// How it works:
+//   - The GPS position is resolved to its expected IANA timezone via Resolver
+//   - Identical timezone names pass immediately
+//   - Different names pass if both zones have the same UTC offset at login time
+//     (e.g., "Europe/Berlin" vs "Europe/Paris"), avoiding false positives
+//     for neighboring zones and aliases
+//   - Triggers when the UTC offsets differ
+//
+// Behavior:
+//   - Skips when GPS, client timezone, or Resolver is unavailable
+//   - Falls back to comparing names if a timezone cannot be loaded
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule interface
+//   - GPS coordinates are resolved ephemerally and never persisted
+type GPSTimezoneRule struct {
+	Resolver  TimezoneResolver // Maps GPS coordinates to an IANA timezone
+	RiskScore int              // Points to add when timezone contradicts GPS
+}
+
+// NewGPSTimezoneRule creates a new GPS vs client timezone consistency rule.
+// Set Resolver before use; without it the rule does not run.
+func NewGPSTimezoneRule(score int) *GPSTimezoneRule {
+	return &GPSTimezoneRule{RiskScore: score}
+}
+
+func (g *GPSTimezoneRule) Name() string {
+	return "GPS-Timezone Mismatch"
+}
+
+func (g *GPSTimezoneRule) Description() string {
+	return "Checks if the client-reported timezone is consistent with device GPS location."
+}
+
+func (g *GPSTimezoneRule) Category() string {
+	return models.CategoryLocation
+}
+
+// RequiresGeoIP reports that this rule only reads device GPS and the client timezone, so it keeps
+// running in no-geo mode. Implements GeoIPRule interface.
+func (g *GPSTimezoneRule) RequiresGeoIP() bool {
+	return false
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because this rule requires ephemeral coordinates via ValidateWithGeo.
+func (g *GPSTimezoneRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo compares the GPS-implied timezone with the client timezone.
+// Implements EphemeralGeoRule interface.
+func (g *GPSTimezoneRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	// Skip if no GPS data or client timezone
+	if ctx.DeviceLatitude == 0 && ctx.DeviceLongitude == 0 {
+		return 0, nil
+	}
+	if input.ClientTimezone == "" || g.Resolver == nil {
+		return 0, nil
+	}
+
+	gpsTimezone, err := g.Resolver.TimezoneAt(ctx.DeviceLatitude, ctx.DeviceLongitude)
+	if err != nil {
+		return 0, err
+	}
+	if gpsTimezone == "" || gpsTimezone == input.ClientTimezone {
+		return 0, nil
+	}
+
+	if sameUTCOffset(gpsTimezone, input.ClientTimezone, input.Timestamp) {
+		return 0, nil
+	}
+
+	return g.RiskScore, nil
+}
+
+// Explain reports the timezone at the GPS location and the client timezone.
+// Implements ExplainableRule interface.
+func (g *GPSTimezoneRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	if g.Resolver == nil {
+		return nil
+	}
+	gpsTimezone, err := g.Resolver.TimezoneAt(ctx.DeviceLatitude, ctx.DeviceLongitude)
+	if err != nil {
+		return nil
+	}
+	return map[string]any{
+		"gps_timezone":    gpsTimezone,
+		"client_timezone": input.ClientTimezone,
+	}
+}
+
+// sameUTCOffset reports whether two IANA timezones share a UTC offset at the given instant.
+// Returns false if either timezone cannot be loaded.
+func sameUTCOffset(tz1, tz2 string, at time.Time) bool {
+	loc1, err := time.LoadLocation(tz1)
+	if err != nil {
+		return false
+	}
+	loc2, err := time.LoadLocation(tz2)
+	if err != nil {
+		return false
+	}
+
+	_, offset1 := at.In(loc1).Zone()
+	_, offset2 := at.In(loc2).Zone()
+	return offset1 == offset2
+}
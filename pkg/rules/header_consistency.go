@@ -0,0 +1,75 @@
+package rules
+
+import (
+	"strings"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// HeaderConsistencyRule detects a browser User-Agent without an Accept-Language header.
+//
+// Real browsers always send Accept-Language. A request claiming to be a
+// mainstream browser but omitting it is contradictory and a common tell of
+// crude automation that spoofs the User-Agent but forgets supporting headers.
+//
+// Behavior:
+//   - Only browser-class User-Agents are evaluated (shared classification
+//     with BotUserAgentRule); empty, bot, and unrecognized UAs are skipped
+//   - Triggers when Accept-Language is missing or blank
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule to receive raw headers via GeoContext
+//   - Raw headers are never persisted; only the fingerprint hash is stored
+type HeaderConsistencyRule struct {
+	RiskScore int // Points to add when headers are inconsistent
+}
+
+// NewHeaderConsistencyRule creates a new browser header consistency rule.
+func NewHeaderConsistencyRule(score int) *HeaderConsistencyRule {
+	return &HeaderConsistencyRule{RiskScore: score}
+}
+
+func (h *HeaderConsistencyRule) Name() string {
+	return "Header Consistency"
+}
+
+func (h *HeaderConsistencyRule) Description() string {
+	return "Detects browser User-Agents sent without an Accept-Language header."
+}
+
+func (h *HeaderConsistencyRule) Category() string {
+	return models.CategoryDevice
+}
+
+// RequiresGeoIP reports that this rule only reads request headers, so it keeps
+// running in no-geo mode. Implements GeoIPRule interface.
+func (h *HeaderConsistencyRule) RequiresGeoIP() bool {
+	return false
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because raw headers are only available via ValidateWithGeo.
+func (h *HeaderConsistencyRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo checks raw request headers for consistency.
+// Implements EphemeralGeoRule interface.
+func (h *HeaderConsistencyRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	// Only browser-like clients are expected to send Accept-Language
+	if classifyUserAgent(ctx.UserAgent, defaultBotSignatures) != userAgentBrowser {
+		return 0, nil
+	}
+
+	if strings.TrimSpace(ctx.AcceptLanguage) == "" {
+		return h.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports the header missing from the browser request.
+// Implements ExplainableRule interface.
+func (h *HeaderConsistencyRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	return map[string]any{"missing_header": "Accept-Language"}
+}
@@ -0,0 +1,148 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// defaultHomeMinHistory is the number of located logins needed before
+// NewHomeDistanceRule establishes a home location.
+const defaultHomeMinHistory = 3
+
+// HomeDistanceRule detects logins far from a user's typical location.
+//
+// VelocityRule compares only against the previous login, so an attacker who
+// logs in twice from the same foreign city is "home" on the second attempt.
+// This rule instead compares the current IP location against the centroid
+// of the user's recent login locations.
+//
+// Behavior:
+//   - Computes the centroid of GeoContext.PreviousIPCoords (recent logins,
+//     up to the engine's history depth; see engine.WithHistoryDepth)
+//   - Triggers when the current IP location is farther than MaxDistanceKm
+//     from the centroid
+//   - Skips until at least MinHistory previous logins could be located
+//
+// Limitations:
+//   - Users who split time between two distant cities have a centroid in
+//     between; set MaxDistanceKm above half that distance
+//   - Uses city centroids from GeoIP, not exact locations
+//
+// Requirements:
+//   - A store implementing storage.RecentHistoryStore
+//   - GeoIP (the rule is skipped in no-geo mode)
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule and LocationHistoryRule
+//   - Previous coordinates are resolved ephemerally from masked prefixes
+//     by the engine; no coordinates are persisted
+type HomeDistanceRule struct {
+	MaxDistanceKm float64 // Maximum allowed distance from the home centroid
+	MinHistory    int     // Located previous logins required before triggering
+	RiskScore     int     // Points to add when rule triggers
+}
+
+// NewHomeDistanceRule creates a new distance-from-home detection rule.
+// A home location is established after 3 located logins.
+//
+// Parameters:
+//   - maxKm: Maximum distance from the home centroid in km (recommend 500)
+//   - score: Risk points to add when triggered
+func NewHomeDistanceRule(maxKm float64, score int) *HomeDistanceRule {
+	return &HomeDistanceRule{
+		MaxDistanceKm: maxKm,
+		MinHistory:    defaultHomeMinHistory,
+		RiskScore:     score,
+	}
+}
+
+func (h *HomeDistanceRule) Name() string {
+	return "Distance From Home"
+}
+
+func (h *HomeDistanceRule) Description() string {
+	return fmt.Sprintf("Checks if the login is more than %.0f km from the user's typical location.", h.MaxDistanceKm)
+}
+
+func (h *HomeDistanceRule) Category() string {
+	return models.CategoryLocation
+}
+
+// Stateful reports that this rule requires historical login data.
+func (h *HomeDistanceRule) Stateful() bool {
+	return true
+}
+
+// UsesLocationHistory reports that this rule reads GeoContext.PreviousIPCoords.
+// Implements LocationHistoryRule interface.
+func (h *HomeDistanceRule) UsesLocationHistory() bool {
+	return true
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because this rule requires ephemeral coordinates via ValidateWithGeo.
+func (h *HomeDistanceRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo compares the IP location with the home centroid.
+// Implements EphemeralGeoRule interface.
+func (h *HomeDistanceRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	distance, ok := h.measure(ctx)
+	if !ok {
+		return 0, nil
+	}
+
+	if distance > h.MaxDistanceKm {
+		return h.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports the distance from home and the number of logins it is based on.
+// Implements ExplainableRule interface.
+func (h *HomeDistanceRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	distance, ok := h.measure(ctx)
+	if !ok {
+		return nil
+	}
+	return map[string]any{
+		"distance_km":     roundTenth(distance),
+		"max_distance_km": h.MaxDistanceKm,
+		"home_samples":    len(ctx.PreviousIPCoords),
+	}
+}
+
+// measure returns the distance from the current IP location to the home
+// centroid, or false if either is unavailable.
+func (h *HomeDistanceRule) measure(ctx GeoContext) (float64, bool) {
+	if ctx.IPLatitude == 0 && ctx.IPLongitude == 0 {
+		return 0, false
+	}
+	if len(ctx.PreviousIPCoords) == 0 || len(ctx.PreviousIPCoords) < h.MinHistory {
+		return 0, false
+	}
+
+	homeLat, homeLon := centroid(ctx.PreviousIPCoords)
+	return haversine(ctx.IPLatitude, ctx.IPLongitude, homeLat, homeLon), true
+}
+
+// centroid returns the geographic mean of [lat, lon] points.
+// Points are averaged as unit vectors, so clusters spanning the antimeridian
+// (e.g., Fiji) average correctly.
+func centroid(points [][2]float64) (lat, lon float64) {
+	var x, y, z float64
+	for _, p := range points {
+		latRad, lonRad := p[0]*math.Pi/180, p[1]*math.Pi/180
+		x += math.Cos(latRad) * math.Cos(lonRad)
+		y += math.Cos(latRad) * math.Sin(lonRad)
+		z += math.Sin(latRad)
+	}
+
+	lat = math.Atan2(z, math.Hypot(x, y)) * 180 / math.Pi
+	lon = math.Atan2(y, x) * 180 / math.Pi
+	return lat, lon
+}
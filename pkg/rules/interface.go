@@ -1,6 +1,17 @@
 package rules
 
-import "github.com/gokaycavdar/go-geoguard/pkg/models"
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// ErrMissingData reports that a rule could not run because required input was
+// unavailable. Rules may return it (optionally wrapped with details) so the
+// engine reports them as skipped in RiskResult.RuleErrors instead of passed.
+var ErrMissingData = errors.New("required data unavailable")
 
 // Rule defines the interface that all security rules must implement.
 // Rules can be either stateless (only need current request data) or
@@ -32,7 +43,8 @@ type Rule interface {
 	//   - lastRecord: The user's previous login record (nil for first login)
 	//
 	// Returns:
-	//   - int: Risk score to add (0 if rule passes, positive if triggered)
+	//   - int: Risk score to add (0 if rule passes, positive if triggered,
+	//     negative to credit trusted signals; the engine floors the total at 0)
 	//   - error: Any error that occurred during validation
 	//
 	// Note: Stateless rules may ignore lastRecord.
@@ -40,6 +52,17 @@ type Rule interface {
 	Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error)
 }
 
+// AnonymousIPInfo contains anonymizer flags for the current IP.
+// The engine copies them from the GeoIP2 Anonymous-IP database.
+type AnonymousIPInfo struct {
+	IsAnonymous        bool // Any of the flags below is set
+	IsAnonymousVPN     bool // Known commercial VPN provider
+	IsHostingProvider  bool // Hosting or VPS provider
+	IsPublicProxy      bool // Public proxy
+	IsResidentialProxy bool // Residential proxy network
+	IsTorExitNode      bool // Tor exit node
+}
+
 // GeoContext provides ephemeral geographic data to rules that require it.
 // This data is computed by the engine and passed to rules implementing EphemeralGeoRule.
 //
@@ -66,8 +89,55 @@ type GeoContext struct {
 	// PreviousIPLatitude and PreviousIPLongitude are coordinates from the last login.
 	// Used by stateful rules like VelocityRule to detect impossible travel.
 	// Zero values indicate no previous login exists.
+	// They follow the baseline record (engine.WithBaselineSelector), so they
+	// need not match PreviousIPCoords[0].
 	PreviousIPLatitude  float64
 	PreviousIPLongitude float64
+
+	// PreviousIPCoords holds [latitude, longitude] of the user's recent
+	// logins, resolved ephemerally from their masked prefixes, most recent
+	// first. Logins whose prefix cannot be located are omitted.
+	// Nil unless an active rule implements LocationHistoryRule (see there).
+	PreviousIPCoords [][2]float64
+
+	// IPAccuracyRadiusKm is the GeoIP accuracy radius around the IP coordinates.
+	// Zero indicates the radius is unavailable.
+	IPAccuracyRadiusKm uint16
+
+	// IPCountryConfidence is the GeoIP confidence (0-100) in the IP country.
+	// Zero indicates confidence data is unavailable (e.g., GeoLite2 databases).
+	IPCountryConfidence uint8
+
+	// AnonymousIP holds anonymizer flags from a GeoIP2 Anonymous-IP database.
+	// Nil when the provider has no anonymizer data (see geoip.AnonymousProvider
+	// and geoip.Service.OpenAnonymousIPDB).
+	AnonymousIP *AnonymousIPInfo
+
+	// TrustLevel is the caller-supplied trust tier from engine.Input.
+	// Zero indicates an unknown or untrusted user.
+	TrustLevel int
+
+	// EvaluatedAt is the engine clock at evaluation time.
+	// May differ from the record Timestamp when callers supply their own timestamps.
+	EvaluatedAt time.Time
+
+	// UserAgent and AcceptLanguage are the raw request headers.
+	// Only the fingerprint hash (and the coarse Platform) is stored; these raw
+	// values exist only here.
+	//
+	// Visibility: only EphemeralGeoRule and ContextRule implementations
+	// receive GeoContext, so header-based rules (HeaderConsistencyRule,
+	// LocaleTimezoneRule, LanguageCountryRule, ...) implement one of them.
+	// Rules must not copy these values into any persisted or logged state.
+	UserAgent      string
+	AcceptLanguage string
+
+	// Extra carries custom ephemeral values computed once per evaluation by
+	// engine.ContextEnricher functions (e.g., a reverse-DNS name), so several
+	// rules can share an expensive value. Like the rest of GeoContext it is
+	// never persisted. Keys should be namespaced to avoid collisions
+	// (e.g., "myapp.rdns"). Rules must treat it as read-only.
+	Extra map[string]any
 }
 
 // EphemeralGeoRule is an optional interface for rules that require geographic coordinates.
@@ -103,4 +173,154 @@ type EphemeralGeoRule interface {
 	//   - int: Risk score to add (0 if rule passes, positive if triggered)
 	//   - error: Any error during validation
 	ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error)
-}
\ No newline at end of file
+}
+
+// HistoryRule is an optional interface for rules that analyze several recent logins.
+//
+// Why this interface exists:
+//   - Some patterns (frequency, churn, failed-then-success) need more than the
+//     single lastRecord passed to Rule.Validate()
+//   - History depends on store support, so rules must degrade gracefully
+//
+// Engine behavior:
+//   - If the store implements storage.RecentHistoryStore, the engine fetches the
+//     user's recent records once per evaluation and calls ValidateWithHistory
+//   - Otherwise the engine calls Rule.Validate() with the single lastRecord,
+//     which should apply a best-effort (or no-op) version of the check
+//   - EphemeralGeoRule takes precedence if a rule implements both interfaces
+type HistoryRule interface {
+	Rule
+
+	// ValidateWithHistory evaluates the rule against the user's recent logins.
+	//
+	// Parameters:
+	//   - input: Current login record (privacy-safe, no coordinates)
+	//   - history: Recent records, most recent first (empty for first login)
+	//
+	// Returns:
+	//   - int: Risk score to add (0 if rule passes, positive if triggered)
+	//   - error: Any error during validation
+	ValidateWithHistory(input models.LoginRecord, history []*models.LoginRecord) (int, error)
+}
+
+// ContextRule is an optional interface for rules that can honor cancellation.
+//
+// Rules that perform blocking work (remote lookups, network-backed reputation
+// feeds) implement this to receive the context passed to
+// GeoGuard.ValidateContext, so deadlines bound their latency.
+//
+// Engine behavior:
+//   - Takes precedence over EphemeralGeoRule and HistoryRule
+//   - Receives the same ephemeral GeoContext as EphemeralGeoRule
+//   - GeoGuard.Validate passes context.Background()
+type ContextRule interface {
+	Rule
+
+	// ValidateContext evaluates the rule, returning promptly once ctx is done.
+	//
+	// Parameters:
+	//   - ctx: Request context carrying deadline and cancellation
+	//   - geoCtx: Ephemeral geographic context (coordinates, never persisted)
+	//   - input: Current login record (privacy-safe, no coordinates)
+	//   - lastRecord: Previous login record (nil for first login)
+	ValidateContext(ctx context.Context, geoCtx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error)
+}
+
+// LocationHistoryRule is an optional interface for geo rules that read
+// GeoContext.PreviousIPCoords.
+//
+// Resolving previous coordinates costs a recent-history read and one GeoIP
+// lookup per distinct prefix, so the engine only does it when an active or
+// shadow rule implements this interface and UsesLocationHistory returns true.
+// It requires a store implementing storage.RecentHistoryStore; otherwise
+// PreviousIPCoords stays nil.
+type LocationHistoryRule interface {
+	EphemeralGeoRule
+
+	// UsesLocationHistory reports whether the rule reads PreviousIPCoords.
+	UsesLocationHistory() bool
+}
+
+// GeoIPRule is an optional interface for EphemeralGeoRules that declare
+// whether they need GeoIP data.
+//
+// In no-geo mode (engine.NewWithoutGeo) the engine skips EphemeralGeoRules
+// that need GeoIP data and reports them as skipped. Rules that only read
+// request-derived GeoContext fields (device GPS, headers, TrustLevel,
+// EvaluatedAt) return false and keep running. EphemeralGeoRules without this
+// interface are assumed to need GeoIP data.
+type GeoIPRule interface {
+	// RequiresGeoIP reports whether the rule reads GeoIP-derived data.
+	RequiresGeoIP() bool
+}
+
+// StatefulRule is an optional interface for rules that depend on login history.
+//
+// Rules returning true only trigger when a HistoryStore retains previous
+// records. The engine uses this to warn about configurations where such
+// rules can never fire (see GeoGuard.Check).
+type StatefulRule interface {
+	Rule
+
+	// Stateful reports whether the rule requires historical login data.
+	Stateful() bool
+}
+
+// ParameterizedRule is an optional interface for rules that describe their own
+// configuration for introspection (see GeoGuard.ConfigSnapshot).
+//
+// Rules without it are described from their exported fields. Implement it to
+// hide noisy fields or expose derived settings. Values must be
+// JSON-serializable and must never include secrets.
+type ParameterizedRule interface {
+	Rule
+
+	// Params returns the rule's tunable parameters keyed by name.
+	Params() map[string]any
+}
+
+// ExplainableRule is an optional interface for rules that can report the
+// values behind a triggered score: measurements (distance, speed, elapsed
+// time) and the observed values that differed (country pair, timezones, ASN).
+//
+// The engine calls Explain only when the rule returned a non-zero score,
+// with the same context and records it evaluated, and copies the result to
+// Violation.Details. Downstream alerting can then use machine-readable values
+// (e.g., from_country "TR", to_country "CN") instead of parsing Reason.
+//
+// Privacy Note:
+// Violations are returned to callers and typically logged. Explain must
+// return derived scalars only, never coordinates, raw IPs, or raw headers.
+type ExplainableRule interface {
+	Rule
+
+	// Explain returns the values behind the rule's score, keyed by snake_case
+	// name, with the unit as suffix for measurements (e.g., "distance_km").
+	// Returning nil omits details.
+	Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any
+}
+
+// HistoryExplainableRule is an optional interface for HistoryRules whose
+// details are measured over the same recent history they were scored on
+// (e.g., the number of distinct cities in the window).
+//
+// The engine calls ExplainWithHistory when the rule was evaluated through
+// ValidateWithHistory; when the store keeps no recent history it falls back
+// to ExplainableRule, if implemented. The Privacy Note of ExplainableRule
+// applies.
+type HistoryExplainableRule interface {
+	HistoryRule
+
+	// ExplainWithHistory returns the values behind the rule's score, as
+	// ExplainableRule.Explain does. Returning nil omits details.
+	ExplainWithHistory(input models.LoginRecord, history []*models.LoginRecord) map[string]any
+}
+
+// CategorizedRule is an optional interface for rules that declare a signal category.
+// The engine copies the category onto each Violation (see models.Category* constants).
+type CategorizedRule interface {
+	Rule
+
+	// Category returns the rule's signal category (e.g., models.CategoryLocation).
+	Category() string
+}
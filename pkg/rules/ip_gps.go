@@ -23,9 +23,15 @@ import (
 //   - Engine owns GeoIP lookup; rule receives only derived coordinates
 //   - GPS data is optional and provided by frontend (requires user permission)
 //   - Rule is testable with mock GeoContext values
+//
+// Accuracy Radius:
+// With UseAccuracyRadius set, the GeoIP accuracy radius is added to
+// MaxDistanceKm, so imprecise geolocations (rural areas, mobile carriers)
+// do not cause false positives.
 type IPGPSRule struct {
-	MaxDistanceKm float64 // Maximum allowed distance between IP and GPS locations
-	RiskScore     int     // Points to add when distance exceeds threshold
+	MaxDistanceKm     float64 // Maximum allowed distance between IP and GPS locations
+	UseAccuracyRadius bool    // Widen the tolerance by the GeoIP accuracy radius
+	RiskScore         int     // Points to add when distance exceeds threshold
 }
 
 // IPGPS creates a new IP-GPS cross-check rule.
@@ -40,6 +46,21 @@ func IPGPS(maxDist float64, score int) *IPGPSRule {
 	}
 }
 
+// NewIPGPSRuleWithAccuracy creates an IP-GPS cross-check rule that can widen
+// its tolerance by the GeoIP accuracy radius.
+//
+// Parameters:
+//   - maxDist: Maximum allowed distance in kilometers (recommend 50-100 km)
+//   - useAccuracy: Add the GeoIP accuracy radius to maxDist
+//   - score: Risk points to add when triggered
+func NewIPGPSRuleWithAccuracy(maxDist float64, useAccuracy bool, score int) *IPGPSRule {
+	return &IPGPSRule{
+		MaxDistanceKm:     maxDist,
+		UseAccuracyRadius: useAccuracy,
+		RiskScore:         score,
+	}
+}
+
 func (r *IPGPSRule) Name() string {
 	return "IP-GPS Crosscheck"
 }
@@ -48,6 +69,10 @@ func (r *IPGPSRule) Description() string {
 	return fmt.Sprintf("Checks if IP location and GPS location differ by more than %.0f km.", r.MaxDistanceKm)
 }
 
+func (r *IPGPSRule) Category() string {
+	return models.CategoryLocation
+}
+
 // Validate satisfies the Rule interface.
 // Returns 0 because this rule requires ephemeral coordinates via ValidateWithGeo.
 func (r *IPGPSRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
@@ -69,12 +94,32 @@ func (r *IPGPSRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, la
 		return 0, nil
 	}
 
-	// Calculate distance between IP location and device GPS
-	distance := haversine(ctx.IPLatitude, ctx.IPLongitude, ctx.DeviceLatitude, ctx.DeviceLongitude)
-
-	if distance > r.MaxDistanceKm {
+	distance, maxDistance := r.measure(ctx)
+	if distance > maxDistance {
 		return r.RiskScore, nil
 	}
 
 	return 0, nil
-}
\ No newline at end of file
+}
+
+// Explain reports the IP-GPS distance and the limit it exceeded.
+// Implements ExplainableRule interface.
+func (r *IPGPSRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	distance, maxDistance := r.measure(ctx)
+	return map[string]any{
+		"distance_km":     roundTenth(distance),
+		"max_distance_km": maxDistance,
+	}
+}
+
+// measure returns the distance between IP location and device GPS, and the
+// allowed maximum (widened by the accuracy radius when enabled).
+func (r *IPGPSRule) measure(ctx GeoContext) (distance, maxDistance float64) {
+	distance = haversine(ctx.IPLatitude, ctx.IPLongitude, ctx.DeviceLatitude, ctx.DeviceLongitude)
+
+	maxDistance = r.MaxDistanceKm
+	if r.UseAccuracyRadius {
+		maxDistance += float64(ctx.IPAccuracyRadiusKm)
+	}
+	return distance, maxDistance
+}
@@ -0,0 +1,166 @@
+package rules
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// LanguageCountryRule flags an Accept-Language header with no language
+// expected for the IP country.
+//
+// A browser accepting only Turkish while connecting from a US IP is a weak
+// anomaly: the client is likely tunneling through a foreign network. Unlike
+// LocaleTimezoneRule, which compares the language with the client timezone,
+// this rule compares it with the GeoIP country.
+//
+// Mapping:
+//   - Countries maps an ISO country code ("TR") to the primary language
+//     subtags expected there ("tr")
+//   - A tag whose region subtag equals the country (e.g., "en-TR") also
+//     counts as expected
+//   - GlobalLanguages (default: English) are expected everywhere
+//   - Override or extend via the Countries field (see DefaultCountryLanguages)
+//
+// Behavior:
+//   - Considers every language in the header, not only the first
+//   - Triggers only on a total mismatch: no language is expected
+//   - Skips when the header or the IP country is absent, and for countries
+//     not present in Countries
+//
+// Confidence:
+// Expatriates, travelers, and language learners legitimately trigger this
+// rule. Keep the score small and use it to corroborate stronger rules.
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule to receive the raw header via GeoContext
+//   - The Accept-Language header is never persisted
+type LanguageCountryRule struct {
+	Countries       map[string][]string // ISO country code -> expected primary language subtags
+	GlobalLanguages []string            // Language subtags expected in every country
+	RiskScore       int                 // Points to add on a total mismatch
+}
+
+// NewLanguageCountryRule creates a new language/country consistency rule.
+//
+// Parameters:
+//   - mapping: ISO country code -> expected languages; nil uses DefaultCountryLanguages
+//   - score: Risk points to add when no header language is expected
+func NewLanguageCountryRule(mapping map[string][]string, score int) *LanguageCountryRule {
+	if mapping == nil {
+		mapping = DefaultCountryLanguages()
+	}
+
+	countries := make(map[string][]string, len(mapping))
+	for country, languages := range mapping {
+		normalized := make([]string, 0, len(languages))
+		for _, language := range languages {
+			normalized = append(normalized, strings.ToLower(language))
+		}
+		countries[strings.ToUpper(country)] = normalized
+	}
+
+	return &LanguageCountryRule{
+		Countries:       countries,
+		GlobalLanguages: []string{"en"},
+		RiskScore:       score,
+	}
+}
+
+// DefaultCountryLanguages returns the default country -> language mapping.
+// Each call returns a fresh map that callers may modify.
+func DefaultCountryLanguages() map[string][]string {
+	return map[string][]string{
+		"TR": {"tr", "ku"},
+		"DE": {"de"},
+		"AT": {"de"},
+		"FR": {"fr"},
+		"IT": {"it"},
+		"ES": {"es", "ca", "gl", "eu"},
+		"PT": {"pt"},
+		"BR": {"pt"},
+		"NL": {"nl"},
+		"PL": {"pl"},
+		"RU": {"ru"},
+		"UA": {"uk", "ru"},
+		"JP": {"ja"},
+		"KR": {"ko"},
+		"CN": {"zh"},
+		"TW": {"zh"},
+		"US": {"es"},
+		"MX": {"es"},
+		"AR": {"es"},
+		"SA": {"ar"},
+		"EG": {"ar"},
+		"IR": {"fa"},
+		"IL": {"he", "ar", "ru"},
+		"GR": {"el"},
+		"SE": {"sv"},
+		"NO": {"nb", "nn", "no"},
+		"DK": {"da"},
+		"FI": {"fi", "sv"},
+		"CZ": {"cs"},
+		"HU": {"hu"},
+		"RO": {"ro"},
+		"TH": {"th"},
+		"VN": {"vi"},
+		"ID": {"id"},
+	}
+}
+
+func (l *LanguageCountryRule) Name() string {
+	return "Language Country Mismatch"
+}
+
+func (l *LanguageCountryRule) Description() string {
+	return "Flags browser languages not expected for the IP country."
+}
+
+func (l *LanguageCountryRule) Category() string {
+	return models.CategoryDevice
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because this rule requires the ephemeral header via ValidateWithGeo.
+func (l *LanguageCountryRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo checks the Accept-Language header against the IP country.
+// Implements EphemeralGeoRule interface.
+func (l *LanguageCountryRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	country := strings.ToUpper(input.CountryCode)
+	expected, ok := l.Countries[country]
+	if country == "" || !ok {
+		return 0, nil
+	}
+
+	tags := parseLanguageTags(ctx.AcceptLanguage)
+	if len(tags) == 0 {
+		return 0, nil
+	}
+
+	for _, tag := range tags {
+		if tag.region == country || slices.Contains(expected, tag.language) || slices.Contains(l.GlobalLanguages, tag.language) {
+			return 0, nil
+		}
+	}
+
+	return l.RiskScore, nil
+}
+
+// Explain reports the IP country and the browser's primary language subtags.
+// Implements ExplainableRule interface.
+func (l *LanguageCountryRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	var languages []string
+	for _, tag := range parseLanguageTags(ctx.AcceptLanguage) {
+		if !slices.Contains(languages, tag.language) {
+			languages = append(languages, tag.language)
+		}
+	}
+	return map[string]any{
+		"country":   strings.ToUpper(input.CountryCode),
+		"languages": languages,
+	}
+}
@@ -0,0 +1,198 @@
+package rules
+
+import (
+	"strings"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// LocaleTimezoneRule flags a browser language whose typical region disagrees
+// with the client timezone.
+//
+// A browser reporting "ja-JP" but "Europe/London" is internally inconsistent:
+// most Japanese-locale users are in JST. This catches partially spoofed
+// clients that fake one signal (language or timezone) but leave the other real.
+//
+// Mapping:
+//   - Languages maps a primary language subtag ("ja") to the IANA timezones
+//     (or timezone prefixes ending in "/") where it is typical
+//   - Only languages concentrated in few timezones are listed by default;
+//     global languages (English, Spanish, French, Arabic, ...) never trigger
+//   - Override or extend via the Languages field (see DefaultLocaleTimezones)
+//
+// Behavior:
+//   - Uses the first (highest-priority) language of the Accept-Language header
+//   - Skips when either the header or the client timezone is absent
+//   - Skips languages not present in Languages
+//
+// Confidence:
+// This is a low-confidence signal: expatriates, travelers, and language
+// learners legitimately combine any language with any timezone. Keep the
+// score small and use it to corroborate stronger rules.
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule to receive the raw header via GeoContext
+//   - The Accept-Language header is never persisted
+type LocaleTimezoneRule struct {
+	Languages map[string][]string // Primary language subtag -> typical timezones or prefixes
+	RiskScore int                 // Points to add when language and timezone disagree
+}
+
+// NewLocaleTimezoneRule creates a new language/timezone consistency rule
+// using DefaultLocaleTimezones.
+func NewLocaleTimezoneRule(score int) *LocaleTimezoneRule {
+	return &LocaleTimezoneRule{
+		Languages: DefaultLocaleTimezones(),
+		RiskScore: score,
+	}
+}
+
+// DefaultLocaleTimezones returns the default language -> timezone mapping.
+// Each call returns a fresh map that callers may modify.
+func DefaultLocaleTimezones() map[string][]string {
+	return map[string][]string{
+		"ja":    {"Asia/Tokyo"},
+		"ko":    {"Asia/Seoul"},
+		"zh":    {"Asia/Shanghai", "Asia/Hong_Kong", "Asia/Taipei", "Asia/Macau", "Asia/Singapore", "Asia/Urumqi"},
+		"tr":    {"Europe/Istanbul", "Asia/Istanbul", "Asia/Nicosia", "Asia/Famagusta"},
+		"th":    {"Asia/Bangkok"},
+		"vi":    {"Asia/Ho_Chi_Minh", "Asia/Saigon"},
+		"id":    {"Asia/Jakarta", "Asia/Makassar", "Asia/Jayapura", "Asia/Pontianak"},
+		"he":    {"Asia/Jerusalem", "Asia/Tel_Aviv"},
+		"fa":    {"Asia/Tehran", "Asia/Kabul"},
+		"pl":    {"Europe/Warsaw"},
+		"cs":    {"Europe/Prague"},
+		"hu":    {"Europe/Budapest"},
+		"el":    {"Europe/Athens", "Asia/Nicosia"},
+		"fi":    {"Europe/Helsinki"},
+		"sv":    {"Europe/Stockholm", "Europe/Helsinki", "Europe/Mariehamn"},
+		"da":    {"Europe/Copenhagen"},
+		"nb":    {"Europe/Oslo"},
+		"no":    {"Europe/Oslo"},
+		"uk":    {"Europe/Kyiv", "Europe/Kiev"},
+		"ro":    {"Europe/Bucharest", "Europe/Chisinau"},
+		"bg":    {"Europe/Sofia"},
+		"pt-BR": {"America/"},
+	}
+}
+
+func (l *LocaleTimezoneRule) Name() string {
+	return "Locale/Timezone Mismatch"
+}
+
+func (l *LocaleTimezoneRule) Description() string {
+	return "Checks that the browser language is typical for the client timezone."
+}
+
+func (l *LocaleTimezoneRule) Category() string {
+	return models.CategoryDevice
+}
+
+// RequiresGeoIP reports that this rule only reads Accept-Language and the
+// client timezone, so it keeps running in no-geo mode. Implements GeoIPRule
+// interface.
+func (l *LocaleTimezoneRule) RequiresGeoIP() bool {
+	return false
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because the raw Accept-Language header is only available via ValidateWithGeo.
+func (l *LocaleTimezoneRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo compares the primary language's typical timezones with the client timezone.
+// Implements EphemeralGeoRule interface.
+func (l *LocaleTimezoneRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	if input.ClientTimezone == "" {
+		return 0, nil
+	}
+
+	_, timezones := l.expectedTimezones(ctx.AcceptLanguage)
+	if len(timezones) == 0 {
+		return 0, nil
+	}
+
+	for _, tz := range timezones {
+		if tz == input.ClientTimezone || (strings.HasSuffix(tz, "/") && strings.HasPrefix(input.ClientTimezone, tz)) {
+			return 0, nil
+		}
+	}
+
+	return l.RiskScore, nil
+}
+
+// Explain reports the browser language and the client timezone.
+// Implements ExplainableRule interface.
+func (l *LocaleTimezoneRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	language, _ := l.expectedTimezones(ctx.AcceptLanguage)
+	if language == "" {
+		return nil
+	}
+	return map[string]any{
+		"language":        language,
+		"client_timezone": input.ClientTimezone,
+	}
+}
+
+// expectedTimezones returns the Languages key matching the primary browser
+// language and its typical timezones, or "" and nil if none matches.
+// A language-region entry (e.g., "pt-BR") takes precedence over the bare language.
+func (l *LocaleTimezoneRule) expectedTimezones(acceptLanguage string) (string, []string) {
+	language, region := primaryLanguage(acceptLanguage)
+	if language == "" {
+		return "", nil
+	}
+	for _, key := range []string{language + "-" + region, language} {
+		if timezones, ok := l.Languages[key]; ok {
+			return key, timezones
+		}
+	}
+	return "", nil
+}
+
+// primaryLanguage extracts the first language of an Accept-Language header,
+// returning the lowercase language subtag and uppercase region subtag
+// (e.g., "ja-jp;q=0.9, en" -> "ja", "JP").
+func primaryLanguage(acceptLanguage string) (string, string) {
+	tags := parseLanguageTags(acceptLanguage)
+	if len(tags) == 0 {
+		return "", ""
+	}
+	return tags[0].language, tags[0].region
+}
+
+// languageTag is one parsed Accept-Language entry.
+type languageTag struct {
+	language string // Lowercase primary language subtag (e.g., "pt")
+	region   string // Uppercase region subtag (e.g., "BR"), empty if absent
+}
+
+// parseLanguageTags parses an Accept-Language header in header order.
+// Wildcards and entries with q=0 (explicitly not acceptable) are skipped.
+func parseLanguageTags(acceptLanguage string) []languageTag {
+	var tags []languageTag
+	for _, entry := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(entry, ";")
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok && strings.Trim(q, "0.") == "" {
+			continue
+		}
+
+		tag = strings.TrimSpace(strings.ReplaceAll(tag, "_", "-"))
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		language, rest, _ := strings.Cut(tag, "-")
+		region := ""
+		for _, subtag := range strings.Split(rest, "-") {
+			// Region subtags are two letters (skip script subtags like "Hant")
+			if len(subtag) == 2 {
+				region = strings.ToUpper(subtag)
+				break
+			}
+		}
+		tags = append(tags, languageTag{language: strings.ToLower(language), region: region})
+	}
+	return tags
+}
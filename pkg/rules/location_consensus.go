@@ -0,0 +1,122 @@
+package rules
+
+import (
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// LocationConsensusRule cross-checks the country implied by three independent
+// location sources and flags the one that disagrees with the other two.
+//
+// Sources:
+//   - IP country: CountryCode from GeoIP (backend-derived)
+//   - GPS country: Device coordinates reverse-geocoded via Resolver (frontend-derived)
+//   - Timezone country: Country implied by the client-reported IANA timezone
+//
+// A single dissenter (e.g., IP says NL while GPS and timezone both say TR)
+// suggests one signal is being masked or spoofed: here, a VPN.
+//
+// Behavior:
+//   - Triggers only on a 2-vs-1 vote: two sources agree and the third differs
+//   - All three sources must be available; with fewer there is no majority,
+//     so the rule does not trigger (IPGPSRule and TimezoneRule cover pairwise
+//     mismatches)
+//   - Three different countries do not trigger either: no source is singled
+//     out, which is more likely bad reference data than a masked signal
+//
+// Requirements:
+//   - Resolver must be set; without it the GPS source is never available
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule interface
+//   - GPS coordinates are resolved ephemerally and never persisted
+type LocationConsensusRule struct {
+	Resolver  CountryResolver // Reverse geocoder for GPS coordinates
+	RiskScore int             // Points to add when one source disagrees with the other two
+}
+
+// NewLocationConsensusRule creates a new cross-source location consistency rule.
+func NewLocationConsensusRule(resolver CountryResolver, score int) *LocationConsensusRule {
+	return &LocationConsensusRule{Resolver: resolver, RiskScore: score}
+}
+
+func (l *LocationConsensusRule) Name() string {
+	return "Location Consensus"
+}
+
+func (l *LocationConsensusRule) Description() string {
+	return "Flags a location source (IP, GPS, or timezone) that disagrees with the other two."
+}
+
+func (l *LocationConsensusRule) Category() string {
+	return models.CategoryLocation
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because this rule requires ephemeral coordinates via ValidateWithGeo.
+func (l *LocationConsensusRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo votes on the countries implied by the three location sources.
+// Implements EphemeralGeoRule interface.
+func (l *LocationConsensusRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	ipCountry, gpsCountry, tzCountry, err := l.sources(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+	if dissenter(ipCountry, gpsCountry, tzCountry) == "" {
+		return 0, nil
+	}
+	return l.RiskScore, nil
+}
+
+// Explain reports the country from each source and which one disagreed.
+// Implements ExplainableRule interface.
+func (l *LocationConsensusRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	ipCountry, gpsCountry, tzCountry, err := l.sources(ctx, input)
+	if err != nil {
+		return nil
+	}
+	return map[string]any{
+		"ip_country":       ipCountry,
+		"gps_country":      gpsCountry,
+		"timezone_country": tzCountry,
+		"dissenter":        dissenter(ipCountry, gpsCountry, tzCountry),
+	}
+}
+
+// sources returns the IP, GPS, and timezone countries ("" when unavailable).
+func (l *LocationConsensusRule) sources(ctx GeoContext, input models.LoginRecord) (ipCountry, gpsCountry, tzCountry string, err error) {
+	ipCountry = input.CountryCode
+	tzCountry = timezoneCountry(input.ClientTimezone)
+
+	if l.Resolver != nil && (ctx.DeviceLatitude != 0 || ctx.DeviceLongitude != 0) {
+		gpsCountry, err = l.Resolver.CountryAt(ctx.DeviceLatitude, ctx.DeviceLongitude)
+		if err != nil {
+			return "", "", "", err
+		}
+	}
+	return ipCountry, gpsCountry, tzCountry, nil
+}
+
+// dissenter returns which source ("ip", "gps", or "timezone") disagrees with
+// the other two, or "" without a 2-vs-1 vote (a source is missing, all
+// agree, or all differ).
+func dissenter(ipCountry, gpsCountry, tzCountry string) string {
+	if ipCountry == "" || gpsCountry == "" || tzCountry == "" {
+		return ""
+	}
+
+	switch {
+	case ipCountry == gpsCountry && gpsCountry == tzCountry:
+		return ""
+	case gpsCountry == tzCountry:
+		return "ip"
+	case ipCountry == tzCountry:
+		return "gps"
+	case ipCountry == gpsCountry:
+		return "timezone"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,60 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+func TestLocationConsensusVote(t *testing.T) {
+	// The device coordinates are ignored; the resolver returns the test's GPS country
+	tests := []struct {
+		name     string
+		ip       string
+		gps      string
+		timezone string
+		want     int
+	}{
+		{"all agree", "TR", "TR", "Europe/Istanbul", 0},
+		{"IP dissents", "NL", "TR", "Europe/Istanbul", 40},
+		{"GPS dissents", "TR", "NL", "Europe/Istanbul", 40},
+		{"timezone dissents", "TR", "TR", "Europe/Amsterdam", 40},
+		{"all differ", "TR", "NL", "Europe/Berlin", 0},
+		{"no GPS, sources differ", "NL", "", "Europe/Istanbul", 0},
+		{"no timezone, sources differ", "NL", "TR", "", 0},
+		{"no IP country, sources differ", "", "TR", "Europe/Amsterdam", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := CountryResolverFunc(func(lat, lon float64) (string, error) {
+				return tt.gps, nil
+			})
+			rule := NewLocationConsensusRule(resolver, 40)
+
+			ctx := GeoContext{DeviceLatitude: 41.0, DeviceLongitude: 29.0}
+			record := models.LoginRecord{CountryCode: tt.ip, ClientTimezone: tt.timezone}
+			got, err := rule.ValidateWithGeo(ctx, record, nil)
+			if err != nil {
+				t.Fatalf("ValidateWithGeo: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("score = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocationConsensusWithoutResolver(t *testing.T) {
+	rule := NewLocationConsensusRule(nil, 40)
+
+	ctx := GeoContext{DeviceLatitude: 41.0, DeviceLongitude: 29.0}
+	record := models.LoginRecord{CountryCode: "NL", ClientTimezone: "Europe/Istanbul"}
+	got, err := rule.ValidateWithGeo(ctx, record, nil)
+	if err != nil {
+		t.Fatalf("ValidateWithGeo: %v", err)
+	}
+	if got != 0 {
+		t.Errorf("score = %d without a GPS source, want 0", got)
+	}
+}
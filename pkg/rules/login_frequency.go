@@ -0,0 +1,117 @@
+package rules
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// LoginFrequencyRule detects an unusually high login rate for one account.
+//
+// Bursts of logins against a single account within minutes are typical of
+// credential stuffing and automated account checking. Unlike VelocityRule,
+// this rule ignores geography and looks only at how often logins occur.
+//
+// Behavior:
+//   - Counts recorded logins within Window of the current attempt, plus the
+//     current attempt itself
+//   - Triggers when the count exceeds MaxLogins
+//   - Failed attempts count too when the application records them
+//     (Input.Outcome), which is what makes stuffing bursts visible
+//
+// Degradation:
+//   - With recent-history support (storage.RecentHistoryStore) the count
+//     covers up to the engine's history depth (engine.WithHistoryDepth,
+//     default 10) plus the current login, so MaxLogins must be below that
+//     depth for the rule to ever trigger
+//   - With only the single last record (Validate), the count is at most 2:
+//     the rule can only trigger when MaxLogins is 1, i.e. it degrades to a
+//     "two logins within Window" check, and is silent for larger thresholds
+//
+// Implements HistoryRule interface.
+type LoginFrequencyRule struct {
+	MaxLogins int           // Maximum logins allowed within Window, including the current one
+	Window    time.Duration // Lookback window
+	RiskScore int           // Points to add when rule triggers
+}
+
+// NewLoginFrequencyRule creates a new login frequency detection rule.
+//
+// Parameters:
+//   - maxLogins: Maximum logins within the window (recommend 5)
+//   - window: Lookback window (recommend 1 minute)
+//   - score: Risk points to add when triggered
+func NewLoginFrequencyRule(maxLogins int, window time.Duration, score int) *LoginFrequencyRule {
+	return &LoginFrequencyRule{
+		MaxLogins: maxLogins,
+		Window:    window,
+		RiskScore: score,
+	}
+}
+
+func (l *LoginFrequencyRule) Name() string {
+	return "Login Frequency"
+}
+
+func (l *LoginFrequencyRule) Description() string {
+	return fmt.Sprintf("Detects more than %d logins within %s.", l.MaxLogins, l.Window)
+}
+
+func (l *LoginFrequencyRule) Category() string {
+	return models.CategoryBehavior
+}
+
+// Stateful reports that this rule requires historical login data.
+func (l *LoginFrequencyRule) Stateful() bool {
+	return true
+}
+
+// Validate applies the single-record fallback: the current login plus the
+// last one, if it falls within the window.
+func (l *LoginFrequencyRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
+	if last == nil {
+		return l.ValidateWithHistory(input, nil)
+	}
+	return l.ValidateWithHistory(input, []*models.LoginRecord{last})
+}
+
+// ValidateWithHistory counts logins within the window.
+// Implements HistoryRule interface.
+func (l *LoginFrequencyRule) ValidateWithHistory(input models.LoginRecord, history []*models.LoginRecord) (int, error) {
+	if l.count(input, history) > l.MaxLogins {
+		return l.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports the login count from the last record alone.
+// Implements ExplainableRule interface.
+func (l *LoginFrequencyRule) Explain(ctx GeoContext, input models.LoginRecord, last *models.LoginRecord) map[string]any {
+	if last == nil {
+		return l.ExplainWithHistory(input, nil)
+	}
+	return l.ExplainWithHistory(input, []*models.LoginRecord{last})
+}
+
+// ExplainWithHistory reports the number of logins in the window.
+// Implements HistoryExplainableRule interface.
+func (l *LoginFrequencyRule) ExplainWithHistory(input models.LoginRecord, history []*models.LoginRecord) map[string]any {
+	return map[string]any{
+		"logins":         l.count(input, history),
+		"max_logins":     l.MaxLogins,
+		"window_minutes": l.Window.Minutes(),
+	}
+}
+
+// count returns the number of logins within Window, including input.
+func (l *LoginFrequencyRule) count(input models.LoginRecord, history []*models.LoginRecord) int {
+	count := 1 // The current attempt
+	for _, record := range history {
+		if input.Timestamp.Sub(record.Timestamp) <= l.Window {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,127 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// LongitudeTimezoneRule compares the GPS longitude with the IP timezone's UTC offset.
+//
+// The sun-based (nominal) UTC offset at a longitude is longitude / 15 hours.
+// For a non-VPN user, the IP timezone's actual offset should be close to the
+// nominal offset at the device's GPS position. A large gap means the device
+// and the network are in very different parts of the world.
+//
+// Coarseness of the approximation:
+//   - Political timezones deviate from nominal offsets by 1-2 hours in many
+//     places (e.g., Spain uses UTC+1 at roughly 0° longitude, China uses a
+//     single zone across 60° of longitude)
+//   - Daylight saving adds another hour
+//   - Only longitude is used; latitude is irrelevant to UTC offsets
+//
+// Keep MaxOffsetHours generous (3 or more recommended). This is a lightweight
+// cross-check that needs no timezone-boundary dataset; for precise checks,
+// use GPSTimezoneRule with a TimezoneResolver.
+//
+// Behavior:
+//   - Skips when GPS is missing or the IP timezone is unknown/unloadable
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule interface
+//   - Coordinates are passed via GeoContext (never persisted)
+type LongitudeTimezoneRule struct {
+	MaxOffsetHours float64 // Maximum allowed gap between nominal and IP offsets
+	RiskScore      int     // Points to add when the gap exceeds MaxOffsetHours
+}
+
+// NewLongitudeTimezoneRule creates a new GPS longitude vs IP timezone rule.
+//
+// Parameters:
+//   - maxOffsetHours: Maximum allowed offset gap in hours (recommend 3)
+//   - score: Risk points to add when triggered
+func NewLongitudeTimezoneRule(maxOffsetHours float64, score int) *LongitudeTimezoneRule {
+	return &LongitudeTimezoneRule{
+		MaxOffsetHours: maxOffsetHours,
+		RiskScore:      score,
+	}
+}
+
+func (l *LongitudeTimezoneRule) Name() string {
+	return "GPS Longitude vs IP Timezone"
+}
+
+func (l *LongitudeTimezoneRule) Description() string {
+	return fmt.Sprintf("Checks if GPS longitude implies a UTC offset within %.1f hours of the IP timezone.", l.MaxOffsetHours)
+}
+
+func (l *LongitudeTimezoneRule) Category() string {
+	return models.CategoryLocation
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because this rule requires ephemeral coordinates via ValidateWithGeo.
+func (l *LongitudeTimezoneRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo compares the nominal GPS offset with the IP timezone offset.
+// Implements EphemeralGeoRule interface.
+func (l *LongitudeTimezoneRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	gap, ok := l.offsetGap(ctx, input)
+	if ok && gap > l.MaxOffsetHours {
+		return l.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports the gap between the GPS-implied and IP timezone offsets.
+// Implements ExplainableRule interface.
+func (l *LongitudeTimezoneRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	gap, ok := l.offsetGap(ctx, input)
+	if !ok {
+		return nil
+	}
+	return map[string]any{
+		"ip_timezone":      input.IPTimezone,
+		"offset_gap_hours": roundTenth(gap),
+		"max_offset_hours": l.MaxOffsetHours,
+	}
+}
+
+// offsetGap returns the hours between the UTC offset implied by GPS
+// longitude and the IP timezone's offset at login time, or false if GPS or
+// the IP timezone is unavailable.
+func (l *LongitudeTimezoneRule) offsetGap(ctx GeoContext, input models.LoginRecord) (float64, bool) {
+	// Skip if no GPS data provided
+	if ctx.DeviceLatitude == 0 && ctx.DeviceLongitude == 0 {
+		return 0, false
+	}
+	if input.IPTimezone == "" {
+		return 0, false
+	}
+
+	loc, err := time.LoadLocation(input.IPTimezone)
+	if err != nil {
+		return 0, false
+	}
+
+	at := input.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+	_, offsetSeconds := at.In(loc).Zone()
+
+	nominalHours := ctx.DeviceLongitude / 15
+	actualHours := float64(offsetSeconds) / 3600
+
+	// Offsets wrap around the antimeridian (UTC+12 and UTC-12 are adjacent)
+	gap := math.Abs(nominalHours - actualHours)
+	if gap > 12 {
+		gap = 24 - gap
+	}
+	return gap, true
+}
@@ -0,0 +1,109 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// MobileStationaryGPSRule detects cellular connections whose GPS never moves.
+//
+// Mobile carriers route traffic through regional gateways and real phones
+// report a slightly different GPS fix every time. A cellular connection paired
+// with GPS pinned to the exact same spot across many logins contradicts
+// genuine mobile usage: it points to a mock-location app or an emulator
+// tethered to a mobile network.
+//
+// Data Requirements:
+//   - Connection type: a GeoIP2 Enterprise city database, or a Connection-Type
+//     database loaded via geoip.Service.OpenConnectionTypeDB
+//   - Coordinate history: opt-in storage via engine.WithCoordinateStorage
+//     (5 decimals recommended) and a storage.RecentHistoryStore
+//   - The rule is a no-op when either is unavailable
+//
+// Behavior:
+//   - Only evaluates logins whose ConnectionType is models.ConnectionTypeCellular
+//   - Counts previous logins with byte-identical stored coordinates
+//   - Triggers when the count reaches MinRepeats
+//
+// Implements HistoryRule interface.
+type MobileStationaryGPSRule struct {
+	MinRepeats int // Previous logins with identical coordinates required to trigger
+	RiskScore  int // Points to add when rule triggers
+}
+
+// NewMobileStationaryGPSRule creates a new cellular/stationary GPS contradiction rule.
+//
+// Parameters:
+//   - minRepeats: Previous logins with identical GPS required (recommend 3)
+//   - score: Risk points to add when triggered
+func NewMobileStationaryGPSRule(minRepeats int, score int) *MobileStationaryGPSRule {
+	return &MobileStationaryGPSRule{
+		MinRepeats: minRepeats,
+		RiskScore:  score,
+	}
+}
+
+func (m *MobileStationaryGPSRule) Name() string {
+	return "Stationary GPS on Cellular"
+}
+
+func (m *MobileStationaryGPSRule) Description() string {
+	return fmt.Sprintf("Detects a cellular connection with GPS identical to %d+ previous logins.", m.MinRepeats)
+}
+
+func (m *MobileStationaryGPSRule) Category() string {
+	return models.CategoryDevice
+}
+
+// Stateful reports that this rule requires historical login data.
+func (m *MobileStationaryGPSRule) Stateful() bool {
+	return true
+}
+
+// Validate falls back to checking only the last record when history is unavailable.
+func (m *MobileStationaryGPSRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
+	if last == nil {
+		return 0, nil
+	}
+	return m.ValidateWithHistory(input, []*models.LoginRecord{last})
+}
+
+// ValidateWithHistory counts identical coordinates for cellular logins.
+// Implements HistoryRule interface.
+func (m *MobileStationaryGPSRule) ValidateWithHistory(input models.LoginRecord, history []*models.LoginRecord) (int, error) {
+	// Connection type unknown or not a mobile carrier
+	if input.ConnectionType != models.ConnectionTypeCellular {
+		return 0, nil
+	}
+
+	// No stored GPS for this login (not provided or storage disabled)
+	if input.DeviceLatitude == 0 && input.DeviceLongitude == 0 {
+		return 0, nil
+	}
+
+	if gpsRepeats(input, history) >= m.MinRepeats {
+		return m.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports whether the last record alone repeats the GPS.
+// Implements ExplainableRule interface.
+func (m *MobileStationaryGPSRule) Explain(ctx GeoContext, input models.LoginRecord, last *models.LoginRecord) map[string]any {
+	if last == nil {
+		return nil
+	}
+	return m.ExplainWithHistory(input, []*models.LoginRecord{last})
+}
+
+// ExplainWithHistory reports how many previous logins had identical GPS.
+// Implements HistoryExplainableRule interface.
+func (m *MobileStationaryGPSRule) ExplainWithHistory(input models.LoginRecord, history []*models.LoginRecord) map[string]any {
+	return map[string]any{
+		"connection_type": input.ConnectionType,
+		"repeats":         gpsRepeats(input, history),
+		"min_repeats":     m.MinRepeats,
+	}
+}
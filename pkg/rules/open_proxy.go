@@ -2,9 +2,10 @@ package rules
 
 import (
 	"bufio"
-	"net"
+	"io"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/gokaycavdar/go-geoguard/pkg/models"
 )
@@ -20,7 +21,7 @@ import (
 //   - Block known proxy infrastructure
 //
 // Privacy-by-Design:
-//   - IP blacklist is stored as /24 prefixes (not individual IPs)
+//   - IP blacklist is stored as /24 (IPv4) or /64 (IPv6) prefixes (not individual IPs)
 //   - Matching is done against masked IP prefixes
 //   - No raw IP addresses are stored or compared
 //
@@ -28,40 +29,25 @@ import (
 //   - IPsum: https://github.com/stamparm/ipsum (Level 3+ recommended)
 //   - FireHOL: https://iplists.firehol.org/
 //   - Tor Exit Nodes: https://check.torproject.org/torbulkexitlist
+//
+// Concurrency:
+//   - Validate, AddIP, RemoveIP, and Count are safe to call concurrently
+//     (e.g., updating the blacklist from a feed while serving logins)
+//   - Set ProxyPrefixes only before the rule is in use; afterwards, mutate
+//     it through AddIP and RemoveIP
 type OpenProxyRule struct {
 	ProxyPrefixes map[string]bool // Set of masked IP prefixes (/24 or /64)
 	RiskScore     int             // Points to add when prefix matches
-}
 
-// maskIPToPrefix masks an IP address to its /24 (IPv4) or /64 (IPv6) prefix.
-// This ensures privacy compliance - no raw IPs are stored.
-func maskIPToPrefix(ipStr string) string {
-	ip := net.ParseIP(ipStr)
-	if ip == nil {
-		return ""
-	}
-
-	// IPv4: Mask to /24
-	if ipv4 := ip.To4(); ipv4 != nil {
-		masked := ipv4.Mask(net.CIDRMask(24, 32))
-		return masked.String() + "/24"
-	}
-
-	// IPv6: Mask to /64
-	if ipv6 := ip.To16(); ipv6 != nil {
-		masked := ipv6.Mask(net.CIDRMask(64, 128))
-		return masked.String() + "/64"
-	}
-
-	return ""
+	mu sync.RWMutex // Protects ProxyPrefixes
 }
 
 // OpenProxy creates a rule from a list of IP addresses.
-// IPs are automatically masked to /24 prefixes for privacy compliance.
+// IPs are automatically masked to /24 (IPv4) or /64 (IPv6) prefixes for privacy compliance.
 func OpenProxy(proxyIPs []string, score int) *OpenProxyRule {
 	prefixSet := make(map[string]bool, len(proxyIPs))
 	for _, ip := range proxyIPs {
-		prefix := maskIPToPrefix(ip)
+		prefix := canonicalPrefix(ip)
 		if prefix != "" {
 			prefixSet[prefix] = true
 		}
@@ -73,13 +59,14 @@ func OpenProxy(proxyIPs []string, score int) *OpenProxyRule {
 }
 
 // LoadOpenProxyRule loads an IP blacklist from a file.
-// IPs are automatically masked to /24 prefixes.
+// IPs are automatically masked to /24 (IPv4) or /64 (IPv6) prefixes.
 //
 // Supported formats:
 //   - One IP per line
 //   - Lines starting with # are ignored (comments)
 //   - IPsum format: "1.2.3.4\t5" (IP + TAB + count)
-//   - CIDR notation (e.g., "1.2.3.0/24")
+//   - CIDR notation (e.g., "1.2.3.0/24"); entries are canonicalized to the
+//     engine's /24 or /64 format, and wider CIDRs are skipped
 //
 // Example:
 //
@@ -91,8 +78,22 @@ func LoadOpenProxyRule(filePath string, score int) (*OpenProxyRule, error) {
 	}
 	defer file.Close()
 
+	prefixSet, err := parsePrefixList(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OpenProxyRule{
+		ProxyPrefixes: prefixSet,
+		RiskScore:     score,
+	}, nil
+}
+
+// parsePrefixList reads a blocklist in the LoadOpenProxyRule format into a
+// set of canonical masked prefixes.
+func parsePrefixList(r io.Reader) (map[string]bool, error) {
 	prefixSet := make(map[string]bool)
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -105,16 +106,10 @@ func LoadOpenProxyRule(filePath string, score int) (*OpenProxyRule, error) {
 		// Parse IPsum format: "1.2.3.4\t5" or plain IP/CIDR
 		parts := strings.Fields(line)
 		if len(parts) > 0 {
-			ip := parts[0]
-			if strings.Contains(ip, "/") {
-				// Already in CIDR format
-				prefixSet[ip] = true
-			} else {
-				// Single IP - mask to /24 prefix
-				prefix := maskIPToPrefix(ip)
-				if prefix != "" {
-					prefixSet[prefix] = true
-				}
+			// Single IPs and CIDRs share MaskIP's key format
+			prefix := canonicalPrefix(parts[0])
+			if prefix != "" {
+				prefixSet[prefix] = true
 			}
 		}
 	}
@@ -122,11 +117,7 @@ func LoadOpenProxyRule(filePath string, score int) (*OpenProxyRule, error) {
 	if err := scanner.Err(); err != nil {
 		return nil, err
 	}
-
-	return &OpenProxyRule{
-		ProxyPrefixes: prefixSet,
-		RiskScore:     score,
-	}, nil
+	return prefixSet, nil
 }
 
 // DefaultOpenProxyRule creates a rule with example proxy IPs.
@@ -146,11 +137,18 @@ func (o *OpenProxyRule) Description() string {
 	return "Checks if IP belongs to a known proxy, VPN, or Tor exit node."
 }
 
+func (o *OpenProxyRule) Category() string {
+	return models.CategoryNetwork
+}
+
 func (o *OpenProxyRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
 	if input.MaskedIPPrefix == "" {
 		return 0, nil
 	}
 
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
 	// Check if masked prefix is in the blacklist
 	if o.ProxyPrefixes[input.MaskedIPPrefix] {
 		return o.RiskScore, nil
@@ -159,24 +157,54 @@ func (o *OpenProxyRule) Validate(input models.LoginRecord, lastRecord *models.Lo
 	return 0, nil
 }
 
+// Explain reports the listed prefix.
+// Implements ExplainableRule interface.
+func (o *OpenProxyRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	return map[string]any{"masked_ip_prefix": input.MaskedIPPrefix}
+}
+
 // AddIP adds an IP to the blacklist at runtime.
-// The IP is automatically masked to /24 prefix.
+// The IP is masked to its /24 (IPv4) or /64 (IPv6) prefix, the same form as
+// MaskedIPPrefix; a CIDR is canonicalized the same way, and wider CIDRs are ignored.
 func (o *OpenProxyRule) AddIP(ip string) {
-	prefix := maskIPToPrefix(ip)
-	if prefix != "" {
-		o.ProxyPrefixes[prefix] = true
+	prefix := canonicalPrefix(ip)
+	if prefix == "" {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.ProxyPrefixes == nil {
+		o.ProxyPrefixes = make(map[string]bool)
 	}
+	o.ProxyPrefixes[prefix] = true
 }
 
 // RemoveIP removes an IP's prefix from the blacklist.
 func (o *OpenProxyRule) RemoveIP(ip string) {
-	prefix := maskIPToPrefix(ip)
-	if prefix != "" {
-		delete(o.ProxyPrefixes, prefix)
+	prefix := canonicalPrefix(ip)
+	if prefix == "" {
+		return
 	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.ProxyPrefixes, prefix)
 }
 
 // Count returns the number of prefixes in the blacklist.
 func (o *OpenProxyRule) Count() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
 	return len(o.ProxyPrefixes)
 }
+
+// Params reports the list size rather than the full prefix set.
+// Implements ParameterizedRule interface.
+func (o *OpenProxyRule) Params() map[string]any {
+	return map[string]any{
+		"RiskScore":   o.RiskScore,
+		"PrefixCount": o.Count(),
+	}
+}
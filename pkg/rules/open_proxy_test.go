@@ -0,0 +1,54 @@
+package rules
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// TestOpenProxyConcurrentUpdates mutates the blocklist while validating.
+// Run with -race; it fails only through the race detector or a panic.
+func TestOpenProxyConcurrentUpdates(t *testing.T) {
+	rule := OpenProxy([]string{"198.51.100.7"}, 50)
+	const iterations = 500
+
+	var wg sync.WaitGroup
+	for v := 0; v < 4; v++ {
+		wg.Add(1)
+		go func(v int) {
+			defer wg.Done()
+			record := models.LoginRecord{MaskedIPPrefix: fmt.Sprintf("203.0.%d.0/24", v)}
+			for i := 0; i < iterations; i++ {
+				if _, err := rule.Validate(record, nil); err != nil {
+					t.Errorf("Validate: %v", err)
+					return
+				}
+			}
+		}(v)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			ip := fmt.Sprintf("203.0.%d.%d", i%4, i%250)
+			rule.AddIP(ip)
+			_ = rule.Count()
+			_ = rule.Params()
+			rule.RemoveIP(ip)
+		}
+	}()
+
+	wg.Wait()
+
+	// Updates leave the original entry intact
+	score, err := rule.Validate(models.LoginRecord{MaskedIPPrefix: "198.51.100.0/24"}, nil)
+	if err != nil || score != 50 {
+		t.Errorf("Validate = %d, %v; want 50, nil", score, err)
+	}
+	if got := rule.Count(); got != 1 {
+		t.Errorf("Count = %d after balanced adds and removes, want 1", got)
+	}
+}
@@ -0,0 +1,92 @@
+package rules
+
+import (
+	"strings"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// OrgChangeRule detects when a user's ISP or organization changes between
+// logins while the country stays the same.
+//
+// An attacker in the victim's country avoids CountryMismatchRule but still
+// connects through a different provider. This rule compares the stored
+// OrgName, which is more readable in investigations than ASNChangeRule's
+// numbers and also catches providers that share an ASN across brands.
+//
+// Behavior:
+//   - Triggers when both organization names are known, differ after
+//     normalization, and both logins come from the same (known) country
+//   - Normalization trims, collapses internal whitespace, and case-folds,
+//     so "Turk Telekom" and " TURK  TELEKOM" compare equal
+//   - Ignores the first login; country changes are left to CountryMismatchRule
+//
+// Note: Legitimate users change networks routinely (home Wi-Fi, mobile
+// data, office). Keep the score low and combine it with other signals;
+// registering both OrgChangeRule and ASNChangeRule counts most changes twice.
+type OrgChangeRule struct {
+	RiskScore int // Points to add when the organization differs within the same country
+}
+
+// NewOrgChangeRule creates a new ISP/organization change detection rule.
+func NewOrgChangeRule(score int) *OrgChangeRule {
+	return &OrgChangeRule{RiskScore: score}
+}
+
+func (o *OrgChangeRule) Name() string {
+	return "Organization Change"
+}
+
+func (o *OrgChangeRule) Description() string {
+	return "Detects when the ISP/organization differs from the previous login within the same country."
+}
+
+func (o *OrgChangeRule) Category() string {
+	return models.CategoryNetwork
+}
+
+// Stateful reports that this rule requires historical login data.
+func (o *OrgChangeRule) Stateful() bool {
+	return true
+}
+
+func (o *OrgChangeRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
+	// First login or no historical data
+	if last == nil {
+		return 0, nil
+	}
+
+	// Only same-country changes; unknown countries cannot be compared
+	if input.CountryCode == "" || !strings.EqualFold(input.CountryCode, last.CountryCode) {
+		return 0, nil
+	}
+
+	current, previous := normalizeOrgName(input.OrgName), normalizeOrgName(last.OrgName)
+	if current == "" || previous == "" {
+		return 0, nil
+	}
+
+	if current != previous {
+		return o.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports the previous and current organizations.
+// Implements ExplainableRule interface.
+func (o *OrgChangeRule) Explain(ctx GeoContext, input models.LoginRecord, last *models.LoginRecord) map[string]any {
+	if last == nil {
+		return nil
+	}
+	return map[string]any{
+		"from_org": last.OrgName,
+		"to_org":   input.OrgName,
+		"country":  input.CountryCode,
+	}
+}
+
+// normalizeOrgName trims, collapses whitespace, and case-folds an organization name.
+func normalizeOrgName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
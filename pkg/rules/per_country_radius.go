@@ -0,0 +1,125 @@
+package rules
+
+import (
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// DefaultZoneKey is the zone key used for countries without their own zone.
+const DefaultZoneKey = "*"
+
+// Zone describes an allowed circular area.
+type Zone struct {
+	Lat      float64 // Latitude of the zone center
+	Lon      float64 // Longitude of the zone center
+	RadiusKm float64 // Allowed radius in kilometers
+	Score    int     // Points to add when outside the zone (0 uses the rule default)
+}
+
+// PerCountryRadiusRule applies a different geofence to each country.
+//
+// A single global radius does not fit services operating in countries of
+// wildly different sizes. This rule selects a zone by the login's CountryCode
+// and checks the IP coordinates against it, enabling policies like "within
+// 50 km of the registered office in each country".
+//
+// Fallback behavior:
+//   - Countries without a zone use the zone under DefaultZoneKey ("*"), if any
+//   - Otherwise the login is not evaluated (no policy for that country)
+//   - Skips when coordinates or country are unavailable
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule interface
+//   - Coordinates are passed via GeoContext (never persisted)
+type PerCountryRadiusRule struct {
+	Zones        map[string]Zone // Country code -> allowed zone
+	DefaultScore int             // Points to add when a zone does not set its own Score
+}
+
+// NewPerCountryRadiusRule creates a new per-country geofencing rule.
+//
+// Example:
+//
+//	rule := rules.NewPerCountryRadiusRule(map[string]rules.Zone{
+//	    "TR": {Lat: 41.01, Lon: 28.97, RadiusKm: 50},
+//	    "DE": {Lat: 52.52, Lon: 13.40, RadiusKm: 30, Score: 60},
+//	}, 40)
+func NewPerCountryRadiusRule(centers map[string]Zone, defaultScore int) *PerCountryRadiusRule {
+	return &PerCountryRadiusRule{
+		Zones:        centers,
+		DefaultScore: defaultScore,
+	}
+}
+
+func (p *PerCountryRadiusRule) Name() string {
+	return "Per-Country Geofencing"
+}
+
+func (p *PerCountryRadiusRule) Description() string {
+	return "Verifies location is within the allowed radius configured for its country."
+}
+
+func (p *PerCountryRadiusRule) Category() string {
+	return models.CategoryLocation
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because this rule requires ephemeral coordinates via ValidateWithGeo.
+func (p *PerCountryRadiusRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo checks the IP coordinates against the zone for the login's country.
+// Implements EphemeralGeoRule interface.
+func (p *PerCountryRadiusRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	// Cannot validate without coordinates or country
+	if ctx.IPLatitude == 0 && ctx.IPLongitude == 0 {
+		return 0, nil
+	}
+	if input.CountryCode == "" {
+		return 0, nil
+	}
+
+	_, zone, exists := p.zone(input.CountryCode)
+	if !exists {
+		return 0, nil
+	}
+
+	distance := haversine(zone.Lat, zone.Lon, ctx.IPLatitude, ctx.IPLongitude)
+	if distance <= zone.RadiusKm {
+		return 0, nil
+	}
+
+	if zone.Score != 0 {
+		return zone.Score, nil
+	}
+	return p.DefaultScore, nil
+}
+
+// Explain reports the zone applied and the distance from its center.
+// Implements ExplainableRule interface.
+func (p *PerCountryRadiusRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	key, zone, exists := p.zone(input.CountryCode)
+	if !exists || (ctx.IPLatitude == 0 && ctx.IPLongitude == 0) {
+		return nil
+	}
+	return map[string]any{
+		"country":         input.CountryCode,
+		"zone":            key,
+		"distance_km":     roundTenth(haversine(zone.Lat, zone.Lon, ctx.IPLatitude, ctx.IPLongitude)),
+		"max_distance_km": zone.RadiusKm,
+	}
+}
+
+// zone returns the zone for country and its key in Zones, falling back to
+// DefaultZoneKey. Returns false if neither is configured.
+func (p *PerCountryRadiusRule) zone(country string) (string, Zone, bool) {
+	if country == "" {
+		return "", Zone{}, false
+	}
+	for _, key := range []string{country, DefaultZoneKey} {
+		if zone, exists := p.Zones[key]; exists {
+			return key, zone, true
+		}
+	}
+	return "", Zone{}, false
+}
@@ -0,0 +1,148 @@
+package rules
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// PingPongRule detects logins bouncing between two distant locations (A→B→A→B).
+//
+// Alternating returns to two far-apart places within a short window are a
+// hallmark of an attacker and the legitimate user being active at the same
+// time, or of a client switching between proxies. Each individual hop may be
+// slow enough for VelocityRule to rationalize; the alternating pattern is not.
+//
+// Requirements:
+//   - Coordinates are not stored by default; this rule requires opt-in
+//     storage via engine.WithCoordinateStorage (2 decimals are sufficient)
+//   - The store must implement storage.RecentHistoryStore
+//   - The rule is a no-op without history support or stored coordinates
+//
+// Behavior:
+//   - Takes the current login and the three most recent previous logins with
+//     coordinates inside the window
+//   - Triggers when they alternate between two poles: logins 1 and 3 (and 2
+//     and 4) are within PoleRadiusKm of each other, while consecutive logins
+//     are at least MinDistanceKm apart
+//
+// Implements HistoryRule interface.
+type PingPongRule struct {
+	WindowHours   int     // Lookback window in hours
+	MinDistanceKm float64 // Minimum distance between the two poles
+	PoleRadiusKm  float64 // Maximum distance for a return to count as the same pole
+	RiskScore     int     // Points to add when rule triggers
+}
+
+// NewPingPongRule creates a new alternating-location detection rule.
+// MinDistanceKm defaults to 300 and PoleRadiusKm to 50.
+//
+// Parameters:
+//   - windowHours: Lookback window in hours (recommend 24)
+//   - score: Risk points to add when triggered
+func NewPingPongRule(windowHours int, score int) *PingPongRule {
+	return &PingPongRule{
+		WindowHours:   windowHours,
+		MinDistanceKm: 300,
+		PoleRadiusKm:  50,
+		RiskScore:     score,
+	}
+}
+
+func (p *PingPongRule) Name() string {
+	return "Ping-Pong Travel"
+}
+
+func (p *PingPongRule) Description() string {
+	return fmt.Sprintf("Detects logins alternating between locations %.0f+ km apart within %d hours.", p.MinDistanceKm, p.WindowHours)
+}
+
+func (p *PingPongRule) Category() string {
+	return models.CategoryLocation
+}
+
+// Stateful reports that this rule requires historical login data.
+func (p *PingPongRule) Stateful() bool {
+	return true
+}
+
+// Validate is a no-op: alternation cannot be observed from a single previous record.
+func (p *PingPongRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithHistory checks the latest logins for an A→B→A→B pattern.
+// Implements HistoryRule interface.
+func (p *PingPongRule) ValidateWithHistory(input models.LoginRecord, history []*models.LoginRecord) (int, error) {
+	if _, ok := p.hops(input, history); ok {
+		return p.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// ExplainWithHistory reports the shortest hop between the two locations
+// and the window it happened in.
+// Implements HistoryExplainableRule interface.
+func (p *PingPongRule) ExplainWithHistory(input models.LoginRecord, history []*models.LoginRecord) map[string]any {
+	hops, ok := p.hops(input, history)
+	if !ok {
+		return nil
+	}
+	return map[string]any{
+		"min_hop_distance_km": roundTenth(min(hops[0], hops[1], hops[2])),
+		"window_hours":        p.WindowHours,
+	}
+}
+
+// hops returns the distances of the three hops between the current and the
+// last three GPS logins in the window, or false if they do not alternate
+// between two poles.
+func (p *PingPongRule) hops(input models.LoginRecord, history []*models.LoginRecord) ([3]float64, bool) {
+	var hops [3]float64
+
+	// No stored GPS for this login (not provided or storage disabled)
+	if input.DeviceLatitude == 0 && input.DeviceLongitude == 0 {
+		return hops, false
+	}
+
+	window := time.Duration(p.WindowHours) * time.Hour
+	points := []*models.LoginRecord{&input}
+	for _, record := range history {
+		if len(points) == 4 {
+			break
+		}
+		if input.Timestamp.Sub(record.Timestamp) > window {
+			break // History is ordered most recent first
+		}
+		if record.DeviceLatitude == 0 && record.DeviceLongitude == 0 {
+			continue
+		}
+		points = append(points, record)
+	}
+
+	if len(points) < 4 {
+		return hops, false
+	}
+
+	// Returns: A and B are each revisited
+	if recordDistance(points[0], points[2]) > p.PoleRadiusKm || recordDistance(points[1], points[3]) > p.PoleRadiusKm {
+		return hops, false
+	}
+
+	// Each hop jumps between the two distant poles
+	for i := range hops {
+		hops[i] = recordDistance(points[i], points[i+1])
+		if hops[i] < p.MinDistanceKm {
+			return hops, false
+		}
+	}
+
+	return hops, true
+}
+
+// recordDistance returns the distance between two records' stored device coordinates.
+func recordDistance(a, b *models.LoginRecord) float64 {
+	return haversine(a.DeviceLatitude, a.DeviceLongitude, b.DeviceLatitude, b.DeviceLongitude)
+}
@@ -0,0 +1,112 @@
+package rules
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// PlatformSwitchRule detects an account hopping between OS platforms in quick succession.
+//
+// A user alternating between Windows, iOS, and Android within minutes is
+// switching devices faster than a person plausibly could, which suggests
+// shared or leaked credentials used from several machines at once. This is a
+// behavioral signal the binary FingerprintRule cannot express: it only sees
+// that the fingerprint changed, not how many platforms are in play.
+//
+// Platform Extraction:
+//   - The engine stores a coarse OS family in LoginRecord.Platform via ParsePlatform
+//   - Families: windows, macos, ios, android, chromeos, linux
+//   - Unrecognized User-Agents (empty Platform) are ignored
+//
+// Behavior:
+//   - Counts distinct platforms within Window, including the current login
+//   - Triggers when the count exceeds MaxPlatforms
+//   - No-op without recent-history support (storage.RecentHistoryStore)
+//   - Records written before schema version 4 have no Platform and are ignored
+//
+// Implements HistoryRule interface.
+type PlatformSwitchRule struct {
+	MaxPlatforms int           // Maximum distinct platforms allowed within Window
+	Window       time.Duration // Lookback window
+	RiskScore    int           // Points to add when rule triggers
+}
+
+// NewPlatformSwitchRule creates a new platform switching detection rule.
+//
+// Parameters:
+//   - maxPlatforms: Maximum distinct platforms within the window (recommend 2)
+//   - window: Lookback window (recommend 10 minutes)
+//   - score: Risk points to add when triggered
+func NewPlatformSwitchRule(maxPlatforms int, window time.Duration, score int) *PlatformSwitchRule {
+	return &PlatformSwitchRule{
+		MaxPlatforms: maxPlatforms,
+		Window:       window,
+		RiskScore:    score,
+	}
+}
+
+func (p *PlatformSwitchRule) Name() string {
+	return "Platform Switching"
+}
+
+func (p *PlatformSwitchRule) Description() string {
+	return fmt.Sprintf("Detects more than %d distinct device platforms within %s.", p.MaxPlatforms, p.Window)
+}
+
+func (p *PlatformSwitchRule) Category() string {
+	return models.CategoryBehavior
+}
+
+// Stateful reports that this rule requires historical login data.
+func (p *PlatformSwitchRule) Stateful() bool {
+	return true
+}
+
+// Validate is a no-op: platform switching cannot be measured from a single previous record.
+func (p *PlatformSwitchRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithHistory counts distinct platforms within the window.
+// Implements HistoryRule interface.
+func (p *PlatformSwitchRule) ValidateWithHistory(input models.LoginRecord, history []*models.LoginRecord) (int, error) {
+	if len(p.platforms(input, history)) > p.MaxPlatforms {
+		return p.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// ExplainWithHistory reports the distinct platforms seen in the window.
+// Implements HistoryExplainableRule interface.
+func (p *PlatformSwitchRule) ExplainWithHistory(input models.LoginRecord, history []*models.LoginRecord) map[string]any {
+	return map[string]any{
+		"platforms":      p.platforms(input, history),
+		"max_platforms":  p.MaxPlatforms,
+		"window_minutes": p.Window.Minutes(),
+	}
+}
+
+// platforms returns the distinct platforms of input and of history within
+// Window, sorted.
+func (p *PlatformSwitchRule) platforms(input models.LoginRecord, history []*models.LoginRecord) []string {
+	platforms := make(map[string]struct{})
+	if input.Platform != "" {
+		platforms[input.Platform] = struct{}{}
+	}
+
+	for _, record := range history {
+		if record.Platform == "" {
+			continue
+		}
+		if input.Timestamp.Sub(record.Timestamp) > p.Window {
+			continue
+		}
+		platforms[record.Platform] = struct{}{}
+	}
+	return slices.Sorted(maps.Keys(platforms))
+}
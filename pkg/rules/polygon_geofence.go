@@ -0,0 +1,186 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// PolygonGeofenceRule checks if a user's location is within allowed polygonal areas.
+//
+// GeofencingRule only supports a circle, which fits real service areas
+// (countries, states, metropolitan regions) poorly. This rule accepts one or
+// more polygons and uses a point-in-polygon (ray casting) test on the IP
+// coordinates.
+//
+// Behavior:
+//   - Inside any polygon = allowed; outside all polygons = trigger
+//     (same semantics as GeofencingRule)
+//   - With Exclude set, the polygons are forbidden zones instead: inside any
+//     polygon = trigger (same semantics as ExclusionZoneRule), e.g. for
+//     sanctioned regions or fraud hotspots
+//   - Polygons may be disjoint (e.g., mainland plus islands)
+//   - Vertices are [latitude, longitude] pairs; the ring is closed implicitly
+//   - Polygons with fewer than 3 vertices are ignored
+//
+// Antimeridian:
+// Edges always take the shorter way around the globe, so a polygon crossing
+// 180° longitude (e.g., Fiji, or Chukotka to Alaska) works as expected as
+// long as no single edge spans more than 180° of longitude.
+//
+// Limitations:
+//   - Edges are straight lines in latitude/longitude, not great circles;
+//     use more vertices for long edges at high latitudes
+//   - Polygons enclosing a pole are not supported
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule interface
+//   - Coordinates are passed via GeoContext (never persisted)
+type PolygonGeofenceRule struct {
+	Polygons  [][][2]float64 // Areas, each a ring of [lat, lon] vertices
+	Exclude   bool           // Treat polygons as forbidden zones instead of allowed areas
+	RiskScore int            // Points to add when outside all allowed areas (inside any zone if Exclude)
+}
+
+// NewPolygonGeofenceRule creates a polygon geofencing rule with one allowed area.
+// Add further disjoint areas with AddPolygon.
+//
+// Parameters:
+//   - polygon: Vertices as [latitude, longitude] pairs
+//   - score: Risk points to add when the IP location is outside the area
+//
+// Example:
+//
+//	rule := rules.NewPolygonGeofenceRule([][2]float64{
+//	    {42.1, 26.0}, {42.1, 44.8}, {35.8, 44.8}, {35.8, 26.0},
+//	}, 50)
+func NewPolygonGeofenceRule(polygon [][2]float64, score int) *PolygonGeofenceRule {
+	rule := &PolygonGeofenceRule{RiskScore: score}
+	rule.AddPolygon(polygon)
+	return rule
+}
+
+// NewPolygonExclusionRule creates a rule that triggers inside a forbidden polygon.
+// Add further disjoint zones with AddPolygon.
+//
+// Parameters:
+//   - polygon: Vertices as [latitude, longitude] pairs
+//   - score: Risk points to add when the IP location is inside the zone
+func NewPolygonExclusionRule(polygon [][2]float64, score int) *PolygonGeofenceRule {
+	rule := NewPolygonGeofenceRule(polygon, score)
+	rule.Exclude = true
+	return rule
+}
+
+// AddPolygon adds another area. Call it before the rule is in use.
+func (p *PolygonGeofenceRule) AddPolygon(polygon [][2]float64) {
+	p.Polygons = append(p.Polygons, polygon)
+}
+
+// Contains reports whether the coordinates fall inside any of the polygons.
+func (p *PolygonGeofenceRule) Contains(lat, lon float64) bool {
+	for _, polygon := range p.Polygons {
+		if pointInPolygon(lat, lon, polygon) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *PolygonGeofenceRule) Name() string {
+	if p.Exclude {
+		return "Polygon Exclusion Zone"
+	}
+	return "Polygon Geofencing"
+}
+
+func (p *PolygonGeofenceRule) Description() string {
+	if p.Exclude {
+		return fmt.Sprintf("Flags locations within %d restricted polygon area(s).", len(p.Polygons))
+	}
+	return fmt.Sprintf("Verifies location is within %d allowed polygon area(s).", len(p.Polygons))
+}
+
+func (p *PolygonGeofenceRule) Category() string {
+	return models.CategoryLocation
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because this rule requires ephemeral coordinates via ValidateWithGeo.
+func (p *PolygonGeofenceRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo checks the IP location against the polygons.
+// Implements EphemeralGeoRule interface.
+func (p *PolygonGeofenceRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	// Cannot validate without coordinates
+	if ctx.IPLatitude == 0 && ctx.IPLongitude == 0 {
+		return 0, nil
+	}
+
+	// Trigger if outside every allowed area, or inside a forbidden one
+	if p.Contains(ctx.IPLatitude, ctx.IPLongitude) == p.Exclude {
+		return p.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports whether the IP location fell inside a polygon.
+// Implements ExplainableRule interface.
+func (p *PolygonGeofenceRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	if ctx.IPLatitude == 0 && ctx.IPLongitude == 0 {
+		return nil
+	}
+	return map[string]any{
+		"inside_polygon": p.Contains(ctx.IPLatitude, ctx.IPLongitude),
+		"exclude":        p.Exclude,
+		"polygon_count":  len(p.Polygons),
+	}
+}
+
+// pointInPolygon runs an even-odd ray casting test.
+//
+// Vertex longitudes are unwrapped so each edge takes the shorter way around
+// the globe; the point is then tested at lon and lon ± 360 so it matches
+// polygons whose unwrapped longitudes extend past ±180.
+func pointInPolygon(lat, lon float64, polygon [][2]float64) bool {
+	n := len(polygon)
+	if n < 3 {
+		return false
+	}
+
+	lons := make([]float64, n)
+	lons[0] = polygon[0][1]
+	for i := 1; i < n; i++ {
+		lons[i] = lons[i-1] + shortestLonDelta(polygon[i][1]-polygon[i-1][1])
+	}
+
+	for _, shift := range [...]float64{0, 360, -360} {
+		x := lon + shift
+		inside := false
+		for i, j := 0, n-1; i < n; j, i = i, i+1 {
+			yi, yj := polygon[i][0], polygon[j][0]
+			if (yi > lat) != (yj > lat) &&
+				x < (lons[j]-lons[i])*(lat-yi)/(yj-yi)+lons[i] {
+				inside = !inside
+			}
+		}
+		if inside {
+			return true
+		}
+	}
+	return false
+}
+
+// shortestLonDelta normalizes a longitude difference to [-180, 180].
+func shortestLonDelta(delta float64) float64 {
+	for delta > 180 {
+		delta -= 360
+	}
+	for delta < -180 {
+		delta += 360
+	}
+	return delta
+}
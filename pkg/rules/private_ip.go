@@ -0,0 +1,67 @@
+package rules
+
+import (
+	"net"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// sharedAddressSpace is RFC 6598 carrier-grade NAT space (100.64.0.0/10).
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// PrivateIPRule flags logins whose IP is private or reserved.
+//
+// A public login service should never see RFC 1918, loopback, or link-local
+// client addresses. When it does, a reverse proxy is usually not forwarding
+// the real client IP (e.g., X-Forwarded-For is ignored), and every location
+// rule silently sees empty GeoIP data. An internal caller spoofing requests
+// produces the same pattern.
+//
+// Behavior:
+//   - Checks the masked prefix against the ranges of geoip.IsPrivateIP:
+//     private, loopback, link-local, carrier-grade NAT, and unspecified
+//   - Masking keeps these ranges intact (all are /7 to /16 or ::/64, which
+//     covers ::1), so no raw IP is needed
+//
+// Tip: RiskResult.PrivateIP reports the same condition without scoring it.
+type PrivateIPRule struct {
+	RiskScore int // Points to add when the IP is private or reserved
+}
+
+// NewPrivateIPRule creates a new private/reserved IP detection rule.
+func NewPrivateIPRule(score int) *PrivateIPRule {
+	return &PrivateIPRule{RiskScore: score}
+}
+
+func (p *PrivateIPRule) Name() string {
+	return "Private IP"
+}
+
+func (p *PrivateIPRule) Description() string {
+	return "Flags private, loopback, or reserved client IPs (often a proxy misconfiguration)."
+}
+
+func (p *PrivateIPRule) Category() string {
+	return models.CategoryNetwork
+}
+
+func (p *PrivateIPRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	ip, _, err := net.ParseCIDR(input.MaskedIPPrefix)
+	if err != nil {
+		return 0, nil
+	}
+
+	// ::/64 holds the IPv6 loopback (::1) and unspecified (::) addresses
+	if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() ||
+		ip.IsUnspecified() || sharedAddressSpace.Contains(ip) {
+		return p.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports the private or reserved prefix.
+// Implements ExplainableRule interface.
+func (p *PrivateIPRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	return map[string]any{"masked_ip_prefix": input.MaskedIPPrefix}
+}
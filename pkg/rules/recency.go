@@ -0,0 +1,29 @@
+package rules
+
+import (
+	"math"
+	"time"
+)
+
+// RecencyWeight returns an exponential-decay weight for a signal of the given age.
+//
+// Stateful rules multiply their score by this weight so that comparisons
+// against recent records count more than comparisons against old ones.
+//
+// Half-life semantics:
+//   - elapsed == 0 returns 1.0 (full weight)
+//   - elapsed == halfLife returns 0.5, 2*halfLife returns 0.25, and so on
+//   - Negative elapsed (clock skew, future timestamps) is treated as 0
+//   - halfLife <= 0 disables decay and always returns 1.0
+func RecencyWeight(elapsed time.Duration, halfLife time.Duration) float64 {
+	if halfLife <= 0 || elapsed <= 0 {
+		return 1.0
+	}
+
+	return math.Exp2(-float64(elapsed) / float64(halfLife))
+}
+
+// decayScore scales score by RecencyWeight, rounding to the nearest point.
+func decayScore(score int, elapsed time.Duration, halfLife time.Duration) int {
+	return int(math.Round(float64(score) * RecencyWeight(elapsed, halfLife)))
+}
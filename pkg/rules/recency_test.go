@@ -0,0 +1,34 @@
+package rules
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDecayScore(t *testing.T) {
+	const halfLife = 24 * time.Hour
+	tests := []struct {
+		elapsed  time.Duration
+		halfLife time.Duration
+		want     int
+	}{
+		{0, halfLife, 40},
+		{halfLife / 2, halfLife, 28}, // 40 * 2^-0.5 = 28.28
+		{halfLife, halfLife, 20},
+		{2 * halfLife, halfLife, 10},
+		{3 * halfLife, halfLife, 5},
+		{4 * halfLife, halfLife, 3}, // 2.5 rounds away from zero
+		{10 * halfLife, halfLife, 0},
+		{-time.Hour, halfLife, 40}, // Clock skew counts as no time elapsed
+		{48 * time.Hour, 0, 40},    // Decay disabled
+		{48 * time.Hour, -time.Hour, 40},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v/%v", tt.elapsed, tt.halfLife), func(t *testing.T) {
+			if got := decayScore(40, tt.elapsed, tt.halfLife); got != tt.want {
+				t.Errorf("decayScore(40, %v, %v) = %d, want %d", tt.elapsed, tt.halfLife, got, tt.want)
+			}
+		})
+	}
+}
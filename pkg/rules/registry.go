@@ -0,0 +1,505 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnknownRule reports a RuleConfig whose Name has no registered factory.
+var ErrUnknownRule = errors.New("unknown rule")
+
+// RuleFactory builds a rule from declarative parameters.
+//
+// Params maps parameter names to JSON-compatible values (as produced by
+// encoding/json or a YAML decoder). Omitted parameters keep the rule's
+// defaults. Factories must reject unknown or ill-typed parameters so that
+// typos in operator-maintained config fail loudly instead of silently
+// running with defaults.
+type RuleFactory func(params map[string]any) (Rule, error)
+
+// RuleConfig declares one rule for BuildFromConfig and
+// engine.AddRulesFromConfig.
+//
+// Example (JSON):
+//
+//	[
+//	  {"name": "velocity", "params": {"MaxSpeedKmh": 900, "RiskScore": 80}},
+//	  {"name": "city_churn", "params": {"window": "12h"}, "weight": 0.5},
+//	  {"name": "bot_user_agent", "shadow": true}
+//	]
+type RuleConfig struct {
+	Name   string         `json:"name"`             // Registry name (e.g., "velocity")
+	Params map[string]any `json:"params,omitempty"` // Parameter overrides
+	Weight float64        `json:"weight,omitempty"` // Score multiplier for the engine (0 = 1.0)
+	Shadow bool           `json:"shadow,omitempty"` // Register in shadow mode (engine only)
+}
+
+// Registry maps rule names to factories for config-driven rule setup.
+//
+// Built-in registry (see DefaultRegistry):
+//   - Names follow the rule's source file: "velocity", "ip_gps",
+//     "country_mismatch", "tor_exit", ...; Names lists them
+//   - Parameters are the rule's exported field names, matched
+//     case-insensitively with underscores ignored, so "RiskScore" and
+//     "risk_score" are equivalent, and GeoGuard.ConfigSnapshot param names
+//     can be reused as config keys. Rules with custom factories
+//     (country_policy, corridor, open_proxy, subnet_reputation, tor_exit)
+//     document their own parameters
+//   - Durations are strings in time.ParseDuration format ("90m", "24h")
+//   - Omitted parameters use the score listed in the README rule tables
+//     and the constructor's defaults
+//
+// Not registered: rules that need Go values rather than data, i.e. the
+// resolver-based CoordCountryConsistencyRule, CountryConfidenceRule,
+// CrossBorderRule, GPSTimezoneRule, and LocationConsensusRule; SharedIPRule
+// (needs a Counter); and Func/GeoFunc rules. Register a factory that closes
+// over the dependency to make them configurable:
+//
+//	registry := rules.DefaultRegistry()
+//	registry.Register("shared_ip", func(params map[string]any) (rules.Rule, error) {
+//	    rule := rules.NewSharedIPRule(10, 10*time.Minute, 40)
+//	    rule.Counter = store
+//	    return rule, rules.DecodeParams(params, rule)
+//	})
+//
+// A Registry is safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]RuleFactory
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]RuleFactory)}
+}
+
+// DefaultRegistry creates a registry with every built-in rule registered.
+// Each call returns a new registry, so Register never affects other callers.
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+	registerBuiltins(r)
+	return r
+}
+
+// Register adds a factory under name, replacing any existing one.
+func (r *Registry) Register(name string, factory RuleFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[name] = factory
+}
+
+// Names returns the registered rule names in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build creates the rule described by config.
+// Returns an error wrapping ErrUnknownRule if config.Name is not registered.
+func (r *Registry) Build(config RuleConfig) (Rule, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[config.Name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownRule, config.Name)
+	}
+
+	rule, err := factory(config.Params)
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %w", config.Name, err)
+	}
+	return rule, nil
+}
+
+// BuildFromConfig creates one rule per config, in order.
+//
+// Building is all-or-nothing: on the first error, rules already built are
+// stopped (if they have a Stop method, e.g. TorExitRule) and nil is
+// returned with an error naming the failing entry.
+func (r *Registry) BuildFromConfig(configs []RuleConfig) ([]Rule, error) {
+	built := make([]Rule, 0, len(configs))
+	for i, config := range configs {
+		rule, err := r.Build(config)
+		if err != nil {
+			stopRules(built)
+			return nil, fmt.Errorf("rule config %d: %w", i, err)
+		}
+		built = append(built, rule)
+	}
+	return built, nil
+}
+
+// stopRules stops background work of rules discarded after a failed build.
+func stopRules(built []Rule) {
+	for _, rule := range built {
+		if stopper, ok := rule.(interface{ Stop() }); ok {
+			stopper.Stop()
+		}
+	}
+}
+
+// DecodeParams sets the exported fields of the struct pointed to by target
+// from params, following the Registry parameter conventions.
+//
+// Behavior:
+//   - Keys match field names case-insensitively, ignoring underscores
+//   - time.Duration fields take duration strings ("10m")
+//   - Other fields are decoded from the value's JSON encoding, so numbers,
+//     strings, lists, and objects convert as encoding/json would
+//     (map[uint]string keys may be given as "16509")
+//   - Unknown keys, ill-typed values, and interface or func fields
+//     (resolvers, counters) are errors
+//   - Out-of-range values are errors: latitudes (*Lat) outside -90..90,
+//     longitudes (*Lon) outside -180..180, StartHour outside 0..23,
+//     EndHour outside 0..24, and negative durations, distances (*Km, *Kmh),
+//     hour counts (*Hours), or Max*/Min* limits
+func DecodeParams(params map[string]any, target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decode params: target must be a non-nil struct pointer, got %T", target)
+	}
+	v = v.Elem()
+
+	for key, value := range params {
+		field, name, ok := paramField(v, key)
+		if !ok {
+			return fmt.Errorf("unknown parameter %q", key)
+		}
+		if err := setParam(field, value); err != nil {
+			return fmt.Errorf("parameter %q: %w", key, err)
+		}
+		if err := checkParamRange(name, field); err != nil {
+			return fmt.Errorf("parameter %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// paramField finds the exported field matching a parameter key and
+// returns it with its name.
+func paramField(v reflect.Value, key string) (reflect.Value, string, bool) {
+	want := paramKey(key)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.IsExported() && paramKey(field.Name) == want {
+			return v.Field(i), field.Name, true
+		}
+	}
+	return reflect.Value{}, "", false
+}
+
+// paramKey folds a parameter or field name for matching.
+func paramKey(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// durationType is the reflect.Type of time.Duration.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setParam converts value to the field's type and assigns it.
+func setParam(field reflect.Value, value any) error {
+	switch {
+	case field.Type() == durationType:
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("duration must be a string like \"10m\", got %T", value)
+		}
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	case field.Kind() == reflect.Interface || field.Kind() == reflect.Func:
+		return fmt.Errorf("%s cannot be set from config", field.Type())
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	decoded := reflect.New(field.Type())
+	if err := json.Unmarshal(encoded, decoded.Interface()); err != nil {
+		return err
+	}
+	field.Set(decoded.Elem())
+	return nil
+}
+
+// checkParamRange rejects implausible numeric values by field name
+// (see DecodeParams). Non-numeric fields always pass.
+func checkParamRange(name string, field reflect.Value) error {
+	var value float64
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = float64(field.Int())
+	case reflect.Float32, reflect.Float64:
+		value = field.Float()
+	default:
+		return nil
+	}
+
+	minValue, maxValue := math.Inf(-1), math.Inf(1)
+	switch {
+	case strings.HasSuffix(name, "Lat"):
+		minValue, maxValue = -90, 90
+	case strings.HasSuffix(name, "Lon"):
+		minValue, maxValue = -180, 180
+	case name == "StartHour":
+		minValue, maxValue = 0, 23
+	case name == "EndHour":
+		minValue, maxValue = 0, 24
+	case field.Type() == durationType,
+		strings.HasSuffix(name, "Km"), strings.HasSuffix(name, "Kmh"), strings.HasSuffix(name, "Hours"),
+		strings.HasPrefix(name, "Max"), strings.HasPrefix(name, "Min"):
+		minValue = 0
+	}
+
+	if value < minValue || value > maxValue {
+		if field.Type() == durationType {
+			return fmt.Errorf("%s must not be negative", time.Duration(field.Int()))
+		}
+		if math.IsInf(maxValue, 1) {
+			return fmt.Errorf("%g is out of range (minimum %g)", value, minValue)
+		}
+		return fmt.Errorf("%g is out of range [%g, %g]", value, minValue, maxValue)
+	}
+	return nil
+}
+
+// structFactory returns a factory that decodes params onto a fresh default
+// rule and rejects configs missing any of the required parameters.
+func structFactory[T Rule](newRule func() T, required ...string) RuleFactory {
+	return func(params map[string]any) (Rule, error) {
+		if err := requireParams(params, required...); err != nil {
+			return nil, err
+		}
+		rule := newRule()
+		if err := DecodeParams(params, rule); err != nil {
+			return nil, err
+		}
+		return rule, nil
+	}
+}
+
+// requireParams reports the first required parameter missing from params.
+func requireParams(params map[string]any, required ...string) error {
+	present := make(map[string]bool, len(params))
+	for key := range params {
+		present[paramKey(key)] = true
+	}
+	for _, name := range required {
+		if !present[paramKey(name)] {
+			return fmt.Errorf("missing required parameter %q", name)
+		}
+	}
+	return nil
+}
+
+// registerBuiltins registers every built-in rule that can be configured
+// from data alone. Defaults follow the README rule tables.
+func registerBuiltins(r *Registry) {
+	// Stateless rules
+	r.Register("anonymous_ip", structFactory(func() *AnonymousIPRule { return NewAnonymousIPRule(40) }))
+	r.Register("bot_user_agent", structFactory(func() *BotUserAgentRule { return NewBotUserAgentRule(50) }))
+	r.Register("business_hours", structFactory(func() *BusinessHoursRule { return NewBusinessHoursRule(0, 24, 20) }, "StartHour", "EndHour"))
+	r.Register("country_policy", countryPolicyFactory)
+	r.Register("data_center", structFactory(func() *DataCenterRule { return DefaultDataCenterRule(30) }))
+	r.Register("exclusion_zone", structFactory(func() *ExclusionZoneRule { return NewExclusionZoneRule(0, 0, 0, 60) }, "CenterLat", "CenterLon", "RadiusKm"))
+	r.Register("geofencing", structFactory(func() *GeofencingRule { return Geofencing(0, 0, 0, 50) }, "CenterLat", "CenterLon", "RadiusKm"))
+	r.Register("gps_datacenter", structFactory(func() *GPSFromDatacenterRule { return NewGPSFromDatacenterRule(40) }))
+	r.Register("header_consistency", structFactory(func() *HeaderConsistencyRule { return NewHeaderConsistencyRule(30) }))
+	r.Register("ip_gps", structFactory(func() *IPGPSRule { return IPGPS(100, 40) }))
+	r.Register("language_country", structFactory(func() *LanguageCountryRule { return NewLanguageCountryRule(nil, 10) }))
+	r.Register("locale_timezone", structFactory(func() *LocaleTimezoneRule { return NewLocaleTimezoneRule(15) }))
+	r.Register("longitude_timezone", structFactory(func() *LongitudeTimezoneRule { return NewLongitudeTimezoneRule(3, 30) }))
+	r.Register("open_proxy", openProxyFactory)
+	r.Register("per_country_radius", structFactory(func() *PerCountryRadiusRule { return NewPerCountryRadiusRule(nil, 40) }, "Zones"))
+	r.Register("polygon_exclusion", structFactory(func() *PolygonGeofenceRule { return &PolygonGeofenceRule{Exclude: true, RiskScore: 50} }, "Polygons"))
+	r.Register("polygon_geofence", structFactory(func() *PolygonGeofenceRule { return &PolygonGeofenceRule{RiskScore: 50} }, "Polygons"))
+	r.Register("private_ip", structFactory(func() *PrivateIPRule { return NewPrivateIPRule(30) }))
+	r.Register("subnet_reputation", subnetReputationFactory)
+	r.Register("timestamp_sanity", structFactory(func() *TimestampSanityRule { return NewTimestampSanityRule(5*time.Minute, 50) }))
+	r.Register("timezone", structFactory(func() *TimezoneRule { return Timezone(45) }))
+	r.Register("tor_exit", torExitFactory)
+	r.Register("trust_adjustment", structFactory(func() *TrustAdjustmentRule { return NewTrustAdjustmentRule(10) }))
+	r.Register("uninhabitable", structFactory(func() *UninhabitableRule { return NewUninhabitableRule(40) }))
+	r.Register("unlocatable_ip", structFactory(func() *UnlocatableIPRule { return NewUnlocatableIPRule(10) }))
+
+	// Stateful rules
+	r.Register("asn_change", structFactory(func() *ASNChangeRule { return NewASNChangeRule(15) }))
+	r.Register("city_change", structFactory(func() *CityChangeRule { return NewCityChangeRule(10) }))
+	r.Register("city_churn", structFactory(func() *CityChurnRule { return NewCityChurnRule(3, 24*time.Hour, 30) }))
+	r.Register("corridor", corridorFactory)
+	r.Register("country_mismatch", structFactory(func() *CountryMismatchRule { return CountryMismatch(25) }))
+	r.Register("failed_attempt_shift", structFactory(func() *FailedAttemptShiftRule { return NewFailedAttemptShiftRule(3, time.Hour, 60) }))
+	r.Register("fingerprint", structFactory(func() *FingerprintRule { return Fingerprint(35) }))
+	r.Register("home_distance", structFactory(func() *HomeDistanceRule { return NewHomeDistanceRule(500, 40) }))
+	r.Register("login_frequency", structFactory(func() *LoginFrequencyRule { return NewLoginFrequencyRule(10, time.Hour, 40) }))
+	r.Register("mobile_stationary", structFactory(func() *MobileStationaryGPSRule { return NewMobileStationaryGPSRule(3, 40) }))
+	r.Register("org_change", structFactory(func() *OrgChangeRule { return NewOrgChangeRule(15) }))
+	r.Register("ping_pong", structFactory(func() *PingPongRule { return NewPingPongRule(24, 50) }))
+	r.Register("platform_switch", structFactory(func() *PlatformSwitchRule { return NewPlatformSwitchRule(3, time.Hour, 40) }))
+	r.Register("repeated_gps", structFactory(func() *RepeatedGPSRule { return NewRepeatedGPSRule(3, 15) }))
+	r.Register("replay", structFactory(func() *ReplayRule { return NewReplayRule(time.Minute, 30) }))
+	r.Register("round_gps", structFactory(func() *RoundGPSHistoryRule { return NewRoundGPSHistoryRule(2, 40) }))
+	r.Register("velocity", structFactory(func() *VelocityRule { return Velocity(900, 80) }))
+}
+
+// countryPolicyFactory builds a CountryPolicyRule from a country list,
+// normalized like NewCountryPolicyRule.
+// Parameters: Countries (required), Mode ("allow" or "block", required),
+// FailOnUnknown, RiskScore.
+func countryPolicyFactory(params map[string]any) (Rule, error) {
+	if err := requireParams(params, "Countries", "Mode"); err != nil {
+		return nil, err
+	}
+	config := struct {
+		Countries     []string
+		Mode          PolicyMode
+		FailOnUnknown bool
+		RiskScore     int
+	}{RiskScore: 100}
+	if err := DecodeParams(params, &config); err != nil {
+		return nil, err
+	}
+	if config.Mode != PolicyAllow && config.Mode != PolicyBlock {
+		return nil, fmt.Errorf("parameter \"Mode\": must be %q or %q, got %q", PolicyAllow, PolicyBlock, config.Mode)
+	}
+
+	rule := NewCountryPolicyRule(config.Countries, config.Mode, config.RiskScore)
+	rule.FailOnUnknown = config.FailOnUnknown
+	return rule, nil
+}
+
+// openProxyFactory builds an OpenProxyRule from a list file or inline IPs.
+// Parameters: File (see LoadOpenProxyRule), IPs, RiskScore.
+// With neither File nor IPs the DefaultOpenProxyRule sample list is used.
+func openProxyFactory(params map[string]any) (Rule, error) {
+	config := struct {
+		File      string
+		IPs       []string
+		RiskScore int
+	}{RiskScore: 40}
+	if err := DecodeParams(params, &config); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case config.File != "":
+		rule, err := LoadOpenProxyRule(config.File, config.RiskScore)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range config.IPs {
+			rule.AddIP(ip)
+		}
+		return rule, nil
+	case config.IPs != nil:
+		return OpenProxy(config.IPs, config.RiskScore), nil
+	default:
+		return DefaultOpenProxyRule(config.RiskScore), nil
+	}
+}
+
+// subnetReputationFactory builds a SubnetReputationRule from a CSV file or
+// an inline feed. Inline prefixes are canonicalized like the CSV loader.
+// Parameters: File (see LoadSubnetReputationRule), Feed, Threshold (required),
+// RiskScore.
+func subnetReputationFactory(params map[string]any) (Rule, error) {
+	if err := requireParams(params, "Threshold"); err != nil {
+		return nil, err
+	}
+	config := struct {
+		File      string
+		Feed      map[string]int
+		Threshold int
+		RiskScore int
+	}{RiskScore: 35}
+	if err := DecodeParams(params, &config); err != nil {
+		return nil, err
+	}
+
+	if config.File != "" {
+		if config.Feed != nil {
+			return nil, errors.New("parameters \"File\" and \"Feed\" are mutually exclusive")
+		}
+		return LoadSubnetReputationRule(config.File, config.Threshold, config.RiskScore)
+	}
+
+	feed := make(map[string]int, len(config.Feed))
+	for entry, score := range config.Feed {
+		prefix := canonicalPrefix(entry)
+		if prefix == "" {
+			return nil, fmt.Errorf("parameter \"Feed\": invalid prefix %q", entry)
+		}
+		feed[prefix] = score
+	}
+	return NewSubnetReputationRule(feed, config.Threshold, config.RiskScore), nil
+}
+
+// corridorFactory builds a CorridorRule from a CSV file or inline corridors
+// keyed "FROM,TO" (the LoadCorridorRule line format without the score).
+// Parameters: File, Corridors.
+func corridorFactory(params map[string]any) (Rule, error) {
+	config := struct {
+		File      string
+		Corridors map[string]int
+	}{}
+	if err := DecodeParams(params, &config); err != nil {
+		return nil, err
+	}
+
+	rule := DefaultCorridorRule()
+	if config.File != "" {
+		loaded, err := LoadCorridorRule(config.File)
+		if err != nil {
+			return nil, err
+		}
+		rule = loaded
+	}
+	for pair, score := range config.Corridors {
+		from, to, ok := strings.Cut(pair, ",")
+		from = strings.ToUpper(strings.TrimSpace(from))
+		to = strings.ToUpper(strings.TrimSpace(to))
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("parameter \"Corridors\": key %q must be \"FROM,TO\"", pair)
+		}
+		rule.Corridors[[2]string{from, to}] = score
+	}
+	return rule, nil
+}
+
+// torExitFactory builds a TorExitRule and calls Start, so the rule refreshes
+// in the background until Stop is called. Rules registered on an engine
+// from config are never stopped: configure tor_exit only for engines that
+// live as long as the process.
+// Parameters: URL (empty = TorBulkExitListURL), Refresh (default "1h"),
+// RiskScore.
+func torExitFactory(params map[string]any) (Rule, error) {
+	config := struct {
+		URL       string
+		Refresh   time.Duration
+		RiskScore int
+	}{Refresh: time.Hour, RiskScore: 40}
+	if err := DecodeParams(params, &config); err != nil {
+		return nil, err
+	}
+	rule := NewTorExitRule(config.URL, config.Refresh, config.RiskScore)
+	rule.Start()
+	return rule, nil
+}
@@ -0,0 +1,112 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// RepeatedGPSRule detects device GPS that never jitters between logins.
+//
+// Real GPS readings vary slightly on every fix, even for a stationary device.
+// A user reporting exactly the same coordinates across many logins likely has
+// a hard-coded spoofed location (emulator, mock-location app, scripted client).
+//
+// Requirements:
+//   - Coordinates are not stored by default; this rule requires opt-in
+//     storage via engine.WithCoordinateStorage (5 decimals recommended)
+//   - The store must implement storage.RecentHistoryStore for full history
+//   - Without stored coordinates the rule never triggers (no-op)
+//
+// Limitations:
+//   - Low-confidence signal: coarse storage precision makes genuine readings
+//     from the same desk look identical, so keep the score small
+//   - Rounding happens at storage time, so comparisons use the stored precision
+//
+// Implements HistoryRule interface.
+type RepeatedGPSRule struct {
+	MinRepeats int // Previous logins with identical coordinates required to trigger
+	RiskScore  int // Points to add when rule triggers
+}
+
+// NewRepeatedGPSRule creates a new identical-coordinates detection rule.
+//
+// Parameters:
+//   - minRepeats: Previous logins with identical GPS required (recommend 3)
+//   - score: Risk points to add when triggered (keep low, e.g. 15)
+func NewRepeatedGPSRule(minRepeats int, score int) *RepeatedGPSRule {
+	return &RepeatedGPSRule{
+		MinRepeats: minRepeats,
+		RiskScore:  score,
+	}
+}
+
+func (r *RepeatedGPSRule) Name() string {
+	return "Repeated Identical GPS"
+}
+
+func (r *RepeatedGPSRule) Description() string {
+	return fmt.Sprintf("Detects device GPS identical to %d+ previous logins (no natural jitter).", r.MinRepeats)
+}
+
+func (r *RepeatedGPSRule) Category() string {
+	return models.CategoryDevice
+}
+
+// Stateful reports that this rule requires historical login data.
+func (r *RepeatedGPSRule) Stateful() bool {
+	return true
+}
+
+// Validate falls back to checking only the last record when history is unavailable.
+func (r *RepeatedGPSRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
+	if last == nil {
+		return 0, nil
+	}
+	return r.ValidateWithHistory(input, []*models.LoginRecord{last})
+}
+
+// ValidateWithHistory counts previous logins with byte-identical coordinates.
+// Implements HistoryRule interface.
+func (r *RepeatedGPSRule) ValidateWithHistory(input models.LoginRecord, history []*models.LoginRecord) (int, error) {
+	// No stored GPS for this login (not provided or storage disabled)
+	if input.DeviceLatitude == 0 && input.DeviceLongitude == 0 {
+		return 0, nil
+	}
+
+	if gpsRepeats(input, history) >= r.MinRepeats {
+		return r.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports whether the last record alone repeats the GPS.
+// Implements ExplainableRule interface.
+func (r *RepeatedGPSRule) Explain(ctx GeoContext, input models.LoginRecord, last *models.LoginRecord) map[string]any {
+	if last == nil {
+		return nil
+	}
+	return r.ExplainWithHistory(input, []*models.LoginRecord{last})
+}
+
+// ExplainWithHistory reports how many previous logins had identical GPS.
+// Implements HistoryExplainableRule interface.
+func (r *RepeatedGPSRule) ExplainWithHistory(input models.LoginRecord, history []*models.LoginRecord) map[string]any {
+	return map[string]any{
+		"repeats":     gpsRepeats(input, history),
+		"min_repeats": r.MinRepeats,
+	}
+}
+
+// gpsRepeats counts the records in history whose stored device GPS is
+// identical to input's.
+func gpsRepeats(input models.LoginRecord, history []*models.LoginRecord) int {
+	repeats := 0
+	for _, record := range history {
+		if record.DeviceLatitude == input.DeviceLatitude && record.DeviceLongitude == input.DeviceLongitude {
+			repeats++
+		}
+	}
+	return repeats
+}
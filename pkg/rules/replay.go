@@ -0,0 +1,97 @@
+package rules
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// ReplayRule detects near-identical logins arriving faster than a person retries.
+//
+// Automated retry loops and credential-stuffing scripts produce bursts of
+// logins seconds apart from the exact same context. Unlike volume-based
+// checks, this rule looks at a single pair: the current login and the last
+// one. If nothing changed (same network, device, and country) and almost no
+// time passed, the login is likely machine-driven.
+//
+// This is a stateful rule that requires historical login data.
+//
+// Behavior:
+//   - Skips the first login (no previous record)
+//   - Context matches when MaskedIPPrefix, FingerprintHash, and CountryCode
+//     are all equal to the last record
+//   - Triggers when the context matches and the login arrives less than
+//     MinInterval after the last one
+//
+// Legitimate Rapid Re-authentication:
+// Double-submitted forms, SSO redirects, and apps that re-authenticate on
+// resume can produce identical logins within a second or two. Keep
+// MinInterval short (a few seconds) and the score moderate, and only save
+// records for completed login attempts so redirects are not counted twice.
+type ReplayRule struct {
+	MinInterval time.Duration // Identical logins closer than this are flagged
+	RiskScore   int           // Points to add when rule triggers
+}
+
+// NewReplayRule creates a new rapid identical-login detection rule.
+//
+// Parameters:
+//   - minInterval: Minimum plausible time between identical logins (recommend 5 seconds)
+//   - score: Risk points to add when triggered
+func NewReplayRule(minInterval time.Duration, score int) *ReplayRule {
+	return &ReplayRule{
+		MinInterval: minInterval,
+		RiskScore:   score,
+	}
+}
+
+func (r *ReplayRule) Name() string {
+	return "Rapid Replay"
+}
+
+func (r *ReplayRule) Description() string {
+	return fmt.Sprintf("Detects an identical login repeated within %s.", r.MinInterval)
+}
+
+func (r *ReplayRule) Category() string {
+	return models.CategoryBehavior
+}
+
+// Stateful reports that this rule requires historical login data.
+func (r *ReplayRule) Stateful() bool {
+	return true
+}
+
+func (r *ReplayRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
+	// First login or no historical data
+	if last == nil {
+		return 0, nil
+	}
+
+	// Any change in network, device, or country means a different context
+	if input.MaskedIPPrefix != last.MaskedIPPrefix ||
+		input.FingerprintHash != last.FingerprintHash ||
+		input.CountryCode != last.CountryCode {
+		return 0, nil
+	}
+
+	elapsed := input.Timestamp.Sub(last.Timestamp)
+	if elapsed < 0 || elapsed >= r.MinInterval {
+		return 0, nil
+	}
+
+	return r.RiskScore, nil
+}
+
+// Explain reports the time since the identical login.
+// Implements ExplainableRule interface.
+func (r *ReplayRule) Explain(ctx GeoContext, input models.LoginRecord, last *models.LoginRecord) map[string]any {
+	if last == nil {
+		return nil
+	}
+	return map[string]any{
+		"elapsed_seconds":      roundTenth(input.Timestamp.Sub(last.Timestamp).Seconds()),
+		"min_interval_seconds": r.MinInterval.Seconds(),
+	}
+}
@@ -0,0 +1,62 @@
+package rules
+
+// CountryResolver reverse-geocodes coordinates to an ISO 3166-1 alpha-2 country code.
+//
+// GeoGuard does not bundle a country-boundary dataset; integrators inject
+// a resolver backed by their preferred source (offline shapefile, geocoding
+// service, etc.). Rules that accept a resolver skip the GPS signal when it is nil.
+//
+// Privacy Note:
+// Resolvers receive ephemeral coordinates and must not persist them.
+type CountryResolver interface {
+	// CountryAt returns the country code at the given coordinates,
+	// or an empty string if the location is not within any country.
+	CountryAt(lat, lon float64) (string, error)
+}
+
+// CountryResolverFunc adapts an ordinary function to the CountryResolver interface.
+type CountryResolverFunc func(lat, lon float64) (string, error)
+
+// CountryAt calls f(lat, lon).
+func (f CountryResolverFunc) CountryAt(lat, lon float64) (string, error) {
+	return f(lat, lon)
+}
+
+// TimezoneResolver maps coordinates to the IANA timezone in effect at that location.
+//
+// GeoGuard does not bundle timezone-boundary data; integrators inject a
+// resolver backed by their preferred source (e.g., a timezone-boundary
+// shapefile or offline lookup library). Rules that accept a resolver skip
+// the check when it is nil.
+//
+// Privacy Note:
+// Resolvers receive ephemeral coordinates and must not persist them.
+type TimezoneResolver interface {
+	// TimezoneAt returns the IANA timezone at the given coordinates
+	// (e.g., "Asia/Tokyo"), or an empty string if unknown.
+	TimezoneAt(lat, lon float64) (string, error)
+}
+
+// TimezoneResolverFunc adapts an ordinary function to the TimezoneResolver interface.
+type TimezoneResolverFunc func(lat, lon float64) (string, error)
+
+// TimezoneAt calls f(lat, lon).
+func (f TimezoneResolverFunc) TimezoneAt(lat, lon float64) (string, error) {
+	return f(lat, lon)
+}
+
+// BorderResolver is a CountryResolver that can also estimate how far a point
+// lies from the nearest national border.
+//
+// Like CountryResolver, it is integrator-supplied; implementations typically
+// compute the distance to the nearest edge of the containing country polygon.
+//
+// Privacy Note:
+// Resolvers receive ephemeral coordinates and must not persist them.
+type BorderResolver interface {
+	CountryResolver
+
+	// BorderDistanceKm returns the approximate distance in kilometers from the
+	// given coordinates to the nearest border of the country containing them.
+	BorderDistanceKm(lat, lon float64) (float64, error)
+}
@@ -0,0 +1,133 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// RoundGPSHistoryRule detects a user who keeps reporting the same round-number GPS.
+//
+// A device reporting exactly "39.0, 35.0" on every login is almost certainly
+// not reading a real GPS fix: it is a spoofed location, an emulator default,
+// or a placeholder value (often a geofence center copied from documentation).
+// This is a stronger signal than RepeatedGPSRule because round numbers are
+// implausible even once; repeated round numbers are implausible many times over.
+//
+// Requirements:
+//   - Coordinates are not stored by default; this rule requires opt-in
+//     storage via engine.WithCoordinateStorage
+//   - The store must implement storage.RecentHistoryStore for full history
+//   - Without stored coordinates the rule never triggers (no-op)
+//
+// Behavior:
+//   - A coordinate is "round" when both values have at most MaxDecimals decimals
+//   - Triggers when the current login and the MinRecords most recent previous
+//     logins all report the identical round coordinate
+//   - A storage precision at or below MaxDecimals makes every coordinate round;
+//     store at least MaxDecimals+2 decimals to keep the signal meaningful
+//
+// Implements HistoryRule interface.
+type RoundGPSHistoryRule struct {
+	MinRecords  int // Consecutive previous logins with the same round coordinate required
+	MaxDecimals int // Maximum decimal places for a value to count as round
+	RiskScore   int // Points to add when rule triggers
+}
+
+// NewRoundGPSHistoryRule creates a new repeated round-coordinate detection rule.
+// MaxDecimals defaults to 1 (e.g., 39.0 and 39.5 are round, 39.52 is not).
+//
+// Parameters:
+//   - minRecords: Consecutive previous logins required (recommend 3)
+//   - score: Risk points to add when triggered
+func NewRoundGPSHistoryRule(minRecords int, score int) *RoundGPSHistoryRule {
+	return &RoundGPSHistoryRule{
+		MinRecords:  minRecords,
+		MaxDecimals: 1,
+		RiskScore:   score,
+	}
+}
+
+func (r *RoundGPSHistoryRule) Name() string {
+	return "Repeated Round GPS"
+}
+
+func (r *RoundGPSHistoryRule) Description() string {
+	return fmt.Sprintf("Detects the same round-number GPS across %d+ consecutive logins.", r.MinRecords)
+}
+
+func (r *RoundGPSHistoryRule) Category() string {
+	return models.CategoryDevice
+}
+
+// Stateful reports that this rule requires historical login data.
+func (r *RoundGPSHistoryRule) Stateful() bool {
+	return true
+}
+
+// Validate falls back to checking only the last record when history is unavailable.
+func (r *RoundGPSHistoryRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
+	if last == nil {
+		return 0, nil
+	}
+	return r.ValidateWithHistory(input, []*models.LoginRecord{last})
+}
+
+// ValidateWithHistory checks the most recent records for the same round coordinate.
+// Implements HistoryRule interface.
+func (r *RoundGPSHistoryRule) ValidateWithHistory(input models.LoginRecord, history []*models.LoginRecord) (int, error) {
+	// No stored GPS for this login (not provided or storage disabled)
+	if input.DeviceLatitude == 0 && input.DeviceLongitude == 0 {
+		return 0, nil
+	}
+
+	if !isRoundCoordinate(input.DeviceLatitude, r.MaxDecimals) || !isRoundCoordinate(input.DeviceLongitude, r.MaxDecimals) {
+		return 0, nil
+	}
+
+	// History is ordered most recent first
+	if r.MinRecords < 1 || len(history) < r.MinRecords {
+		return 0, nil
+	}
+
+	for _, record := range history[:r.MinRecords] {
+		if record.DeviceLatitude != input.DeviceLatitude || record.DeviceLongitude != input.DeviceLongitude {
+			return 0, nil
+		}
+	}
+
+	return r.RiskScore, nil
+}
+
+// Explain reports whether the last record alone repeats the round GPS.
+// Implements ExplainableRule interface.
+func (r *RoundGPSHistoryRule) Explain(ctx GeoContext, input models.LoginRecord, last *models.LoginRecord) map[string]any {
+	if last == nil {
+		return nil
+	}
+	return r.ExplainWithHistory(input, []*models.LoginRecord{last})
+}
+
+// ExplainWithHistory reports how many consecutive previous logins had the
+// same round GPS, and the decimal places counted as round.
+// Implements HistoryExplainableRule interface.
+func (r *RoundGPSHistoryRule) ExplainWithHistory(input models.LoginRecord, history []*models.LoginRecord) map[string]any {
+	consecutive := 0
+	for _, record := range history {
+		if record.DeviceLatitude != input.DeviceLatitude || record.DeviceLongitude != input.DeviceLongitude {
+			break
+		}
+		consecutive++
+	}
+	return map[string]any{
+		"consecutive_logins": consecutive,
+		"max_decimals":       r.MaxDecimals,
+	}
+}
+
+// isRoundCoordinate reports whether value has at most decimals decimal places.
+func isRoundCoordinate(value float64, decimals int) bool {
+	scaled := value * math.Pow(10, float64(decimals))
+	return math.Abs(scaled-math.Round(scaled)) < 1e-6
+}
@@ -0,0 +1,151 @@
+package rules
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// PrefixUserCounter counts distinct users seen from a masked IP prefix.
+//
+// storage.PrefixIndexStore satisfies it (e.g., *storage.MemoryStore and
+// *storage.PostgresStore), so the history store itself is usually injected.
+type PrefixUserCounter interface {
+	// CountUsersByPrefix returns the number of distinct users with at least
+	// one record from prefix within [now-window, now].
+	CountUsersByPrefix(prefix string, now time.Time, window time.Duration) (int, error)
+}
+
+// SharedIPRule detects many distinct accounts logging in from one network.
+//
+// Credential stuffing replays leaked username/password pairs from a small
+// pool of machines, so a single /24 suddenly serves logins for dozens of
+// accounts. Every per-user rule sees an ordinary login; only a count across
+// users reveals the attack.
+//
+// Behavior:
+//   - Counts distinct users with a recorded login from the current
+//     MaskedIPPrefix within Window before the login's Timestamp, via Counter
+//   - The current user is added to the count unless their last login came
+//     from the same prefix within Window (and is therefore already counted)
+//   - Triggers when the count exceeds MaxUsers
+//   - Failed attempts count too when the application records them
+//     (Input.Outcome), which is what makes stuffing bursts visible
+//
+// Requirements:
+//   - Counter must be set, typically to the engine's history store; without
+//     it the rule returns ErrMissingData and is reported as skipped
+//   - The count is read before the current login is saved
+//
+// Limitations:
+//   - Carrier-grade NAT, campus, and corporate networks legitimately share a
+//     prefix among many users; choose MaxUsers with that in mind, or pair the
+//     rule with an allowlist
+//   - The current user may be counted twice when an older (not the last)
+//     login of theirs came from the same prefix, so the count errs high by one
+//
+// Privacy-by-Design:
+//   - Only masked prefixes are counted; no user IDs leave the store
+type SharedIPRule struct {
+	Counter   PrefixUserCounter // Prefix index, usually the history store
+	MaxUsers  int               // Maximum distinct users per prefix within Window, including the current one
+	Window    time.Duration     // Lookback window
+	RiskScore int               // Points to add when rule triggers
+}
+
+// NewSharedIPRule creates a new shared-prefix detection rule.
+// Set Counter before use.
+//
+// Parameters:
+//   - maxUsers: Maximum distinct users per prefix within the window (recommend 10)
+//   - window: Lookback window (recommend 10 minutes)
+//   - score: Risk points to add when triggered
+//
+// Example:
+//
+//	store := storage.NewMemoryStore()
+//	sharedIP := rules.NewSharedIPRule(10, 10*time.Minute, 40)
+//	sharedIP.Counter = store
+//	guard := engine.New(geoService, store)
+//	guard.AddRule(sharedIP)
+func NewSharedIPRule(maxUsers int, window time.Duration, score int) *SharedIPRule {
+	return &SharedIPRule{
+		MaxUsers:  maxUsers,
+		Window:    window,
+		RiskScore: score,
+	}
+}
+
+func (s *SharedIPRule) Name() string {
+	return "Shared IP Prefix"
+}
+
+func (s *SharedIPRule) Description() string {
+	return fmt.Sprintf("Detects more than %d users from one network within %s.", s.MaxUsers, s.Window)
+}
+
+func (s *SharedIPRule) Category() string {
+	return models.CategoryNetwork
+}
+
+// Stateful reports that this rule requires historical login data.
+func (s *SharedIPRule) Stateful() bool {
+	return true
+}
+
+func (s *SharedIPRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	if s.Counter == nil {
+		return 0, fmt.Errorf("%w: no prefix index configured", ErrMissingData)
+	}
+	if input.MaskedIPPrefix == "" {
+		return 0, nil
+	}
+
+	count, err := s.users(input, lastRecord)
+	if err != nil {
+		return 0, err
+	}
+
+	if count > s.MaxUsers {
+		return s.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports the number of distinct users on the prefix in the window.
+// Implements ExplainableRule interface.
+func (s *SharedIPRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	if s.Counter == nil {
+		return nil
+	}
+	count, err := s.users(input, lastRecord)
+	if err != nil {
+		return nil
+	}
+	return map[string]any{
+		"masked_ip_prefix": input.MaskedIPPrefix,
+		"distinct_users":   count,
+		"max_users":        s.MaxUsers,
+		"window_minutes":   s.Window.Minutes(),
+	}
+}
+
+// users returns the distinct users on input's prefix within Window,
+// including the current user.
+func (s *SharedIPRule) users(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	// Measured from the login's own timestamp (engine clock or replayed time)
+	count, err := s.Counter.CountUsersByPrefix(input.MaskedIPPrefix, input.Timestamp, s.Window)
+	if err != nil {
+		return 0, err
+	}
+
+	// Add the current user unless their last login is already in the count
+	if lastRecord == nil ||
+		lastRecord.MaskedIPPrefix != input.MaskedIPPrefix ||
+		input.Timestamp.Sub(lastRecord.Timestamp) > s.Window {
+		count++
+	}
+	return count, nil
+}
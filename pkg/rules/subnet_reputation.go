@@ -0,0 +1,178 @@
+package rules
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// SubnetReputationRule scores logins from subnets with a poor abuse reputation.
+//
+// Unlike OpenProxyRule, which matches a set of known-bad addresses, this rule
+// consumes a reputation feed that assigns each subnet an aggregate abuse
+// score (e.g., the number of abuse reports across its addresses). Only
+// subnets whose reputation exceeds Threshold trigger the rule, so a single
+// noisy report does not flag a whole network.
+//
+// Privacy-by-Design:
+//   - The feed is keyed by /24 (IPv4) or /64 (IPv6) prefixes
+//   - Matching uses the already-masked MaskedIPPrefix; raw IPs are never compared
+//
+// Refreshing:
+//   - Refresh atomically replaces the feed and is safe to call while the
+//     engine is validating (e.g., from a periodic ticker)
+//   - Reload re-reads the feed from a CSV file
+type SubnetReputationRule struct {
+	Threshold int // Reputation above which the subnet is flagged
+	RiskScore int // Points to add when rule triggers
+
+	mu   sync.RWMutex
+	feed map[string]int // Masked prefix -> aggregate abuse score
+}
+
+// NewSubnetReputationRule creates a rule from a prefix -> reputation map.
+//
+// Parameters:
+//   - feed: Masked prefixes (e.g., "203.0.113.0/24") mapped to abuse scores
+//   - threshold: Reputation above which a subnet is flagged
+//   - score: Risk points to add when triggered
+func NewSubnetReputationRule(feed map[string]int, threshold, score int) *SubnetReputationRule {
+	return &SubnetReputationRule{
+		Threshold: threshold,
+		RiskScore: score,
+		feed:      feed,
+	}
+}
+
+// LoadSubnetReputationRule loads a reputation feed from a CSV file.
+//
+// Supported format:
+//   - One subnet per line: PREFIX,SCORE (e.g., "203.0.113.0/24,87")
+//   - Plain IPs and CIDRs are canonicalized to the /24 or /64 format of
+//     MaskedIPPrefix; CIDRs wider than /24 or /64 are skipped
+//   - Lines starting with # are ignored (comments)
+//
+// Example:
+//
+//	rule, err := rules.LoadSubnetReputationRule("data/subnet_reputation.csv", 50, 35)
+func LoadSubnetReputationRule(filePath string, threshold, score int) (*SubnetReputationRule, error) {
+	feed, err := loadSubnetFeed(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return NewSubnetReputationRule(feed, threshold, score), nil
+}
+
+// loadSubnetFeed parses a PREFIX,SCORE CSV file into a reputation feed.
+func loadSubnetFeed(filePath string) (map[string]int, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	feed := make(map[string]int)
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		reputation, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid subnet score %q: %v", fields[1], err)
+		}
+
+		// Single IPs and CIDRs share MaskIP's key format
+		prefix := canonicalPrefix(strings.TrimSpace(fields[0]))
+		if prefix != "" {
+			feed[prefix] = reputation
+		}
+	}
+
+	return feed, nil
+}
+
+func (s *SubnetReputationRule) Name() string {
+	return "Subnet Reputation"
+}
+
+func (s *SubnetReputationRule) Description() string {
+	return fmt.Sprintf("Checks if the IP subnet has an abuse reputation above %d.", s.Threshold)
+}
+
+func (s *SubnetReputationRule) Category() string {
+	return models.CategoryNetwork
+}
+
+func (s *SubnetReputationRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	if input.MaskedIPPrefix == "" {
+		return 0, nil
+	}
+
+	s.mu.RLock()
+	reputation, ok := s.feed[input.MaskedIPPrefix]
+	s.mu.RUnlock()
+
+	// Subnets absent from the feed have no known reputation
+	if !ok || reputation <= s.Threshold {
+		return 0, nil
+	}
+
+	return s.RiskScore, nil
+}
+
+// Explain reports the subnet and its reputation.
+// Implements ExplainableRule interface.
+func (s *SubnetReputationRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	s.mu.RLock()
+	reputation, ok := s.feed[input.MaskedIPPrefix]
+	s.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return map[string]any{
+		"masked_ip_prefix": input.MaskedIPPrefix,
+		"reputation":       reputation,
+		"threshold":        s.Threshold,
+	}
+}
+
+// Refresh atomically replaces the reputation feed.
+func (s *SubnetReputationRule) Refresh(feed map[string]int) {
+	s.mu.Lock()
+	s.feed = feed
+	s.mu.Unlock()
+}
+
+// Reload re-reads the reputation feed from a CSV file (see LoadSubnetReputationRule).
+// On error the current feed is kept.
+func (s *SubnetReputationRule) Reload(filePath string) error {
+	feed, err := loadSubnetFeed(filePath)
+	if err != nil {
+		return err
+	}
+	s.Refresh(feed)
+	return nil
+}
+
+// Count returns the number of subnets in the feed.
+func (s *SubnetReputationRule) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.feed)
+}
@@ -0,0 +1,95 @@
+package rules
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// TimestampSanityRule flags login records whose timestamp is far from the engine clock.
+//
+// Caller-supplied timestamps (engine.Input.Timestamp) may come from clients,
+// queues, or skewed clocks. A timestamp far in the future or past corrupts
+// stateful temporal rules: velocity computes absurd speeds, frequency windows
+// miscount, and the bad record poisons history for later logins.
+//
+// Role as a Data-Quality Gate:
+//   - A trigger means the record's timing cannot be trusted
+//   - Consider rejecting such records rather than saving them to history
+//   - With the default engine clock and no caller timestamps, never triggers
+//
+// Implements EphemeralGeoRule to receive the engine clock via GeoContext.
+type TimestampSanityRule struct {
+	MaxSkew   time.Duration // Maximum allowed deviation from the engine clock
+	RiskScore int           // Points to add when the deviation exceeds MaxSkew
+}
+
+// NewTimestampSanityRule creates a new timestamp sanity rule.
+//
+// Parameters:
+//   - maxSkew: Maximum allowed deviation in either direction (recommend 5 minutes)
+//   - score: Risk points to add when triggered
+func NewTimestampSanityRule(maxSkew time.Duration, score int) *TimestampSanityRule {
+	return &TimestampSanityRule{
+		MaxSkew:   maxSkew,
+		RiskScore: score,
+	}
+}
+
+func (t *TimestampSanityRule) Name() string {
+	return "Timestamp Sanity"
+}
+
+func (t *TimestampSanityRule) Description() string {
+	return fmt.Sprintf("Flags login timestamps more than %s from the engine clock.", t.MaxSkew)
+}
+
+func (t *TimestampSanityRule) Category() string {
+	return models.CategoryBehavior
+}
+
+// RequiresGeoIP reports that this rule only reads record timestamps and the engine clock, so it keeps
+// running in no-geo mode. Implements GeoIPRule interface.
+func (t *TimestampSanityRule) RequiresGeoIP() bool {
+	return false
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because the engine clock is only available via ValidateWithGeo.
+func (t *TimestampSanityRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo compares the record timestamp with the engine clock.
+// Implements EphemeralGeoRule interface.
+func (t *TimestampSanityRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	// Cannot compare without both times
+	if ctx.EvaluatedAt.IsZero() || input.Timestamp.IsZero() {
+		return 0, nil
+	}
+
+	skew := input.Timestamp.Sub(ctx.EvaluatedAt)
+	if skew < 0 {
+		skew = -skew
+	}
+
+	if skew > t.MaxSkew {
+		return t.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports the skew from the engine clock; positive means the
+// timestamp is in the future.
+// Implements ExplainableRule interface.
+func (t *TimestampSanityRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	if ctx.EvaluatedAt.IsZero() || input.Timestamp.IsZero() {
+		return nil
+	}
+	return map[string]any{
+		"skew_seconds":     roundTenth(input.Timestamp.Sub(ctx.EvaluatedAt).Seconds()),
+		"max_skew_seconds": t.MaxSkew.Seconds(),
+	}
+}
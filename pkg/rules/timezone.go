@@ -39,6 +39,10 @@ func (t *TimezoneRule) Description() string {
 	return "Checks if IP-derived timezone differs from client-reported timezone."
 }
 
+func (t *TimezoneRule) Category() string {
+	return models.CategoryNetwork
+}
+
 func (t *TimezoneRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
 	// Both timezones required for comparison
 	if input.IPTimezone == "" || input.ClientTimezone == "" {
@@ -52,3 +56,12 @@ func (t *TimezoneRule) Validate(input models.LoginRecord, lastRecord *models.Log
 
 	return 0, nil
 }
+
+// Explain reports the mismatched timezones.
+// Implements ExplainableRule interface.
+func (t *TimezoneRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	return map[string]any{
+		"ip_timezone":     input.IPTimezone,
+		"client_timezone": input.ClientTimezone,
+	}
+}
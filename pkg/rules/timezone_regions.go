@@ -0,0 +1,128 @@
+package rules
+
+// timezoneCountries maps common IANA timezones to the country they imply.
+//
+// Derived from the IANA zone.tab primary country assignments. Zones shared by
+// several countries (e.g., "Europe/Zurich" also covers Liechtenstein) map to
+// their primary country only. Unlisted zones imply no country.
+var timezoneCountries = map[string]string{
+	// Europe
+	"Europe/Amsterdam":   "NL",
+	"Europe/Andorra":     "AD",
+	"Europe/Athens":      "GR",
+	"Europe/Belgrade":    "RS",
+	"Europe/Berlin":      "DE",
+	"Europe/Brussels":    "BE",
+	"Europe/Bucharest":   "RO",
+	"Europe/Budapest":    "HU",
+	"Europe/Chisinau":    "MD",
+	"Europe/Copenhagen":  "DK",
+	"Europe/Dublin":      "IE",
+	"Europe/Helsinki":    "FI",
+	"Europe/Istanbul":    "TR",
+	"Europe/Kaliningrad": "RU",
+	"Europe/Kiev":        "UA",
+	"Europe/Kyiv":        "UA",
+	"Europe/Lisbon":      "PT",
+	"Europe/London":      "GB",
+	"Europe/Luxembourg":  "LU",
+	"Europe/Madrid":      "ES",
+	"Europe/Malta":       "MT",
+	"Europe/Minsk":       "BY",
+	"Europe/Monaco":      "MC",
+	"Europe/Moscow":      "RU",
+	"Europe/Oslo":        "NO",
+	"Europe/Paris":       "FR",
+	"Europe/Prague":      "CZ",
+	"Europe/Riga":        "LV",
+	"Europe/Rome":        "IT",
+	"Europe/Samara":      "RU",
+	"Europe/Sofia":       "BG",
+	"Europe/Stockholm":   "SE",
+	"Europe/Tallinn":     "EE",
+	"Europe/Tirane":      "AL",
+	"Europe/Vienna":      "AT",
+	"Europe/Vilnius":     "LT",
+	"Europe/Warsaw":      "PL",
+	"Europe/Zagreb":      "HR",
+	"Europe/Zurich":      "CH",
+
+	// Asia
+	"Asia/Almaty":       "KZ",
+	"Asia/Amman":        "JO",
+	"Asia/Baghdad":      "IQ",
+	"Asia/Baku":         "AZ",
+	"Asia/Bangkok":      "TH",
+	"Asia/Beirut":       "LB",
+	"Asia/Calcutta":     "IN",
+	"Asia/Colombo":      "LK",
+	"Asia/Damascus":     "SY",
+	"Asia/Dhaka":        "BD",
+	"Asia/Dubai":        "AE",
+	"Asia/Ho_Chi_Minh":  "VN",
+	"Asia/Hong_Kong":    "HK",
+	"Asia/Jakarta":      "ID",
+	"Asia/Jerusalem":    "IL",
+	"Asia/Kabul":        "AF",
+	"Asia/Karachi":      "PK",
+	"Asia/Kathmandu":    "NP",
+	"Asia/Kolkata":      "IN",
+	"Asia/Kuala_Lumpur": "MY",
+	"Asia/Kuwait":       "KW",
+	"Asia/Manila":       "PH",
+	"Asia/Nicosia":      "CY",
+	"Asia/Qatar":        "QA",
+	"Asia/Riyadh":       "SA",
+	"Asia/Seoul":        "KR",
+	"Asia/Shanghai":     "CN",
+	"Asia/Singapore":    "SG",
+	"Asia/Taipei":       "TW",
+	"Asia/Tashkent":     "UZ",
+	"Asia/Tbilisi":      "GE",
+	"Asia/Tehran":       "IR",
+	"Asia/Tokyo":        "JP",
+	"Asia/Yerevan":      "AM",
+
+	// Americas
+	"America/Argentina/Buenos_Aires": "AR",
+	"America/Bogota":                 "CO",
+	"America/Caracas":                "VE",
+	"America/Chicago":                "US",
+	"America/Denver":                 "US",
+	"America/Halifax":                "CA",
+	"America/Havana":                 "CU",
+	"America/Lima":                   "PE",
+	"America/Los_Angeles":            "US",
+	"America/Mexico_City":            "MX",
+	"America/New_York":               "US",
+	"America/Phoenix":                "US",
+	"America/Santiago":               "CL",
+	"America/Sao_Paulo":              "BR",
+	"America/Toronto":                "CA",
+	"America/Vancouver":              "CA",
+	"America/Anchorage":              "US",
+	"Pacific/Honolulu":               "US",
+
+	// Africa
+	"Africa/Algiers":      "DZ",
+	"Africa/Cairo":        "EG",
+	"Africa/Casablanca":   "MA",
+	"Africa/Johannesburg": "ZA",
+	"Africa/Lagos":        "NG",
+	"Africa/Nairobi":      "KE",
+	"Africa/Tunis":        "TN",
+
+	// Oceania
+	"Australia/Adelaide":  "AU",
+	"Australia/Brisbane":  "AU",
+	"Australia/Melbourne": "AU",
+	"Australia/Perth":     "AU",
+	"Australia/Sydney":    "AU",
+	"Pacific/Auckland":    "NZ",
+}
+
+// timezoneCountry returns the country implied by an IANA timezone,
+// or an empty string if the timezone is unknown.
+func timezoneCountry(tz string) string {
+	return timezoneCountries[tz]
+}
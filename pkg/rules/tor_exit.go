@@ -0,0 +1,225 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// TorBulkExitListURL is the Tor Project's plain-text list of current exit node IPs.
+const TorBulkExitListURL = "https://check.torproject.org/torbulkexitlist"
+
+// maxTorListBytes bounds the size of a fetched exit list (the real list is ~20 KB).
+const maxTorListBytes = 8 << 20
+
+// TorExitRule detects logins from current Tor exit nodes using a live list.
+//
+// Unlike OpenProxyRule, which loads a static file once, this rule fetches
+// the exit list over HTTP and refreshes it in the background, because Tor
+// exit nodes change constantly.
+//
+// Behavior:
+//   - Nothing is fetched until Start (or Refresh) is called
+//   - Start fetches the list immediately and then every refresh interval
+//   - Each refresh atomically swaps in the new prefix set
+//   - A failed fetch, or an empty list, keeps the previous prefix set
+//     (a transient outage never disables detection)
+//   - Call Stop to end background refreshing
+//
+// Privacy-by-Design:
+//   - Exit IPs are masked to /24 (IPv4) or /64 (IPv6) prefixes on load
+//   - Matching uses the already-masked MaskedIPPrefix
+//
+// Limitations:
+//   - Masking flags every address in an exit node's /24 or /64, not only the
+//     exit node itself
+//   - Until the first fetch completes the list is empty; call Refresh
+//     directly to load it synchronously at startup
+type TorExitRule struct {
+	URL       string // Exit list URL (one IP per line)
+	RiskScore int    // Points to add when prefix matches
+
+	client   *http.Client
+	mu       sync.RWMutex
+	prefixes map[string]bool // Masked exit node prefixes
+	lastErr  error           // Error from the most recent refresh, if any
+
+	refresh   time.Duration
+	startOnce sync.Once
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewTorExitRule creates a rule that loads the exit list from url and, once
+// Start is called, refreshes it every refresh interval until Stop is called.
+// The constructor does no network I/O and starts no goroutine.
+//
+// Parameters:
+//   - url: Exit list URL; empty uses TorBulkExitListURL
+//   - refresh: Interval between refreshes; <= 0 fetches only once
+//   - score: Risk points to add when triggered
+//
+// Example:
+//
+//	torRule := rules.NewTorExitRule("", time.Hour, 40)
+//	torRule.Start()
+//	defer torRule.Stop()
+//	guard.AddRule(torRule)
+func NewTorExitRule(url string, refresh time.Duration, score int) *TorExitRule {
+	if url == "" {
+		url = TorBulkExitListURL
+	}
+
+	t := &TorExitRule{
+		URL:       url,
+		RiskScore: score,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		prefixes:  make(map[string]bool),
+		refresh:   refresh,
+		stop:      make(chan struct{}),
+	}
+	return t
+}
+
+// Start begins fetching the exit list in the background: once immediately,
+// then every refresh interval until Stop is called. Calls after the first
+// have no effect.
+func (t *TorExitRule) Start() {
+	t.startOnce.Do(func() { go t.run(t.refresh) })
+}
+
+// run performs the initial fetch and, if refresh > 0, periodic refreshes.
+func (t *TorExitRule) run(refresh time.Duration) {
+	t.refreshUntilStopped()
+	if refresh <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(refresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.refreshUntilStopped()
+		case <-t.stop:
+			return
+		}
+	}
+}
+
+// refreshUntilStopped runs one refresh that is canceled when Stop is called.
+func (t *TorExitRule) refreshUntilStopped() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-t.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	t.Refresh(ctx)
+}
+
+// Refresh fetches the exit list now and swaps it in on success.
+// On error the current list is kept and the error is returned.
+func (t *TorExitRule) Refresh(ctx context.Context) error {
+	prefixes, err := t.fetch(ctx)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastErr = err
+	if err == nil {
+		t.prefixes = prefixes
+	}
+	return err
+}
+
+// fetch downloads and parses the exit list.
+func (t *TorExitRule) fetch(ctx context.Context) (map[string]bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch tor exit list: unexpected status %s", resp.Status)
+	}
+
+	prefixes, err := parsePrefixList(io.LimitReader(resp.Body, maxTorListBytes))
+	if err != nil {
+		return nil, err
+	}
+	if len(prefixes) == 0 {
+		return nil, errors.New("fetch tor exit list: list is empty")
+	}
+	return prefixes, nil
+}
+
+// Stop ends background refreshing. It is safe to call more than once.
+// The last loaded list remains in use.
+func (t *TorExitRule) Stop() {
+	t.stopOnce.Do(func() { close(t.stop) })
+}
+
+// Count returns the number of masked prefixes currently loaded.
+func (t *TorExitRule) Count() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.prefixes)
+}
+
+// LastError returns the error from the most recent refresh, or nil if it succeeded.
+func (t *TorExitRule) LastError() error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.lastErr
+}
+
+func (t *TorExitRule) Name() string {
+	return "Tor Exit Node"
+}
+
+func (t *TorExitRule) Description() string {
+	return "Checks if IP belongs to a current Tor exit node (live list)."
+}
+
+func (t *TorExitRule) Category() string {
+	return models.CategoryNetwork
+}
+
+func (t *TorExitRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	if input.MaskedIPPrefix == "" {
+		return 0, nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.prefixes[input.MaskedIPPrefix] {
+		return t.RiskScore, nil
+	}
+	return 0, nil
+}
+
+// Explain reports the listed prefix.
+// Implements ExplainableRule interface.
+func (t *TorExitRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	return map[string]any{"masked_ip_prefix": input.MaskedIPPrefix}
+}
@@ -0,0 +1,65 @@
+package rules
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTorExitRuleFetchesOnlyAfterStart(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte("185.220.101.1\n"))
+	}))
+	defer server.Close()
+
+	rule := NewTorExitRule(server.URL, 0, 40)
+	defer rule.Stop()
+
+	time.Sleep(50 * time.Millisecond)
+	if n := requests.Load(); n != 0 {
+		t.Fatalf("constructor made %d requests, want 0", n)
+	}
+
+	rule.Start()
+	rule.Start() // Second call is a no-op
+
+	deadline := time.Now().Add(5 * time.Second)
+	for rule.Count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if rule.Count() != 1 {
+		t.Fatalf("Count() = %d after Start, want 1", rule.Count())
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("%d requests after Start, want 1", n)
+	}
+}
+
+func TestTorExitFactoryStartsRule(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("185.220.101.1\n"))
+	}))
+	defer server.Close()
+
+	built, err := DefaultRegistry().Build(RuleConfig{
+		Name:   "tor_exit",
+		Params: map[string]any{"URL": server.URL, "Refresh": "0s"},
+	})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	rule := built.(*TorExitRule)
+	defer rule.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for rule.Count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if rule.Count() == 0 {
+		t.Fatal("Count() = 0, want the exit list loaded after Build")
+	}
+}
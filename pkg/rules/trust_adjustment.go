@@ -0,0 +1,76 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// TrustAdjustmentRule lowers the risk score for users the application already trusts.
+//
+// The integrating application usually knows a user's trust tier (verified email,
+// MFA enrolled, account age). This rule turns that knowledge into a negative
+// score so well-established users clear the risk bar more easily, while new or
+// unverified users (TrustLevel 0) face full scrutiny.
+//
+// Score Floor:
+//   - The credit is proportional: -ReductionPerLevel * TrustLevel
+//   - The engine floors TotalRiskScore at 0, so credits can offset other
+//     violations but never push the total below zero
+//   - A trusted user with no violations still scores 0, not a negative value
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule to receive the trust level via GeoContext
+//   - TrustLevel is caller-supplied per request and never persisted
+type TrustAdjustmentRule struct {
+	ReductionPerLevel int // Points to subtract per trust level
+}
+
+// NewTrustAdjustmentRule creates a new trust-based score reduction rule.
+//
+// Parameters:
+//   - reductionPerLevel: Points subtracted for each level of caller-supplied trust
+func NewTrustAdjustmentRule(reductionPerLevel int) *TrustAdjustmentRule {
+	return &TrustAdjustmentRule{ReductionPerLevel: reductionPerLevel}
+}
+
+func (t *TrustAdjustmentRule) Name() string {
+	return "Trust Adjustment"
+}
+
+func (t *TrustAdjustmentRule) Description() string {
+	return fmt.Sprintf("Reduces risk by %d points per caller-supplied trust level.", t.ReductionPerLevel)
+}
+
+func (t *TrustAdjustmentRule) Category() string {
+	return models.CategoryTrust
+}
+
+// RequiresGeoIP reports that this rule only reads the caller-supplied trust level, so it keeps
+// running in no-geo mode. Implements GeoIPRule interface.
+func (t *TrustAdjustmentRule) RequiresGeoIP() bool {
+	return false
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because the trust level is only available via ValidateWithGeo.
+func (t *TrustAdjustmentRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo returns a negative score proportional to the trust level.
+// Implements EphemeralGeoRule interface.
+func (t *TrustAdjustmentRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	// Unknown or untrusted users receive no credit
+	if ctx.TrustLevel <= 0 || t.ReductionPerLevel <= 0 {
+		return 0, nil
+	}
+
+	return -ctx.TrustLevel * t.ReductionPerLevel, nil
+}
+
+// Explain reports the trust level behind the reduction.
+// Implements ExplainableRule interface.
+func (t *TrustAdjustmentRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	return map[string]any{"trust_level": ctx.TrustLevel}
+}
@@ -0,0 +1,136 @@
+package rules
+
+import (
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// GeoBox is a latitude/longitude bounding box. Boxes do not cross the antimeridian.
+type GeoBox struct {
+	Name   string  // Human-readable label (e.g., "North Atlantic")
+	MinLat float64 // Southern edge
+	MaxLat float64 // Northern edge
+	MinLon float64 // Western edge
+	MaxLon float64 // Eastern edge
+}
+
+// Contains reports whether the coordinates fall inside the box (edges inclusive).
+func (b GeoBox) Contains(lat, lon float64) bool {
+	return lat >= b.MinLat && lat <= b.MaxLat && lon >= b.MinLon && lon <= b.MaxLon
+}
+
+// UninhabitableRule flags device GPS located in open ocean or polar regions.
+//
+// Spoofed or defaulted GPS often lands somewhere nobody logs in from: the
+// middle of an ocean, the North Pole, or Antarctica. Distance-based checks
+// miss this when the IP location is itself imprecise.
+//
+// Mask Resolution:
+//   - Areas is a coarse mask of bounding boxes, not a coastline dataset
+//   - Default ocean boxes cover open water only and deliberately stop short
+//     of coasts and known inhabited islands (Hawaii, Azores, Bermuda, ...)
+//   - Default polar boxes cover north of 84°N and south of 60°S
+//
+// False Positives:
+//   - Ships, offshore platforms, and in-flight Wi-Fi are genuinely at sea
+//   - Antarctic research stations are inside the southern polar box
+//   - Remote islands inside a box are possible; keep the score moderate and
+//     override Areas for audiences with maritime users
+//
+// Behavior:
+//   - Skips when device GPS is absent
+//   - Triggers when the GPS coordinates fall inside any area
+//
+// Privacy-by-Design:
+//   - Implements EphemeralGeoRule interface
+//   - Coordinates are passed via GeoContext (never persisted)
+type UninhabitableRule struct {
+	Areas     []GeoBox // Implausible login areas
+	RiskScore int      // Points to add when GPS falls inside an area
+}
+
+// NewUninhabitableRule creates a new implausible-GPS-location rule
+// using DefaultUninhabitableAreas.
+func NewUninhabitableRule(score int) *UninhabitableRule {
+	return &UninhabitableRule{
+		Areas:     DefaultUninhabitableAreas(),
+		RiskScore: score,
+	}
+}
+
+// DefaultUninhabitableAreas returns the default coarse ocean and polar mask.
+// Each call returns a fresh slice that callers may modify.
+func DefaultUninhabitableAreas() []GeoBox {
+	return []GeoBox{
+		// Polar regions
+		{Name: "Arctic Ocean", MinLat: 84, MaxLat: 90, MinLon: -180, MaxLon: 180},
+		{Name: "Antarctica", MinLat: -90, MaxLat: -60, MinLon: -180, MaxLon: 180},
+
+		// Open ocean (clear of coasts and inhabited islands)
+		{Name: "North Pacific (East)", MinLat: 25, MaxLat: 45, MinLon: -170, MaxLon: -130},
+		{Name: "North Pacific (West)", MinLat: 30, MaxLat: 45, MinLon: 150, MaxLon: 175},
+		{Name: "South Pacific", MinLat: -50, MaxLat: -30, MinLon: -170, MaxLon: -85},
+		{Name: "North Atlantic", MinLat: 35, MaxLat: 50, MinLon: -50, MaxLon: -33},
+		{Name: "Central Atlantic", MinLat: 20, MaxLat: 30, MinLon: -60, MaxLon: -30},
+		{Name: "South Atlantic", MinLat: -34, MaxLat: -18, MinLon: -28, MaxLon: -8},
+		{Name: "Indian Ocean", MinLat: -35, MaxLat: -15, MinLon: 65, MaxLon: 100},
+	}
+}
+
+func (u *UninhabitableRule) Name() string {
+	return "Uninhabitable GPS Location"
+}
+
+func (u *UninhabitableRule) Description() string {
+	return "Detects device GPS in open ocean or polar regions."
+}
+
+func (u *UninhabitableRule) Category() string {
+	return models.CategoryDevice
+}
+
+// RequiresGeoIP reports that this rule only reads device GPS, so it keeps
+// running in no-geo mode. Implements GeoIPRule interface.
+func (u *UninhabitableRule) RequiresGeoIP() bool {
+	return false
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because this rule requires ephemeral coordinates via ValidateWithGeo.
+func (u *UninhabitableRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo checks device GPS against the uninhabitable areas.
+// Implements EphemeralGeoRule interface.
+func (u *UninhabitableRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	// Skip if GPS is not available
+	if ctx.DeviceLatitude == 0 && ctx.DeviceLongitude == 0 {
+		return 0, nil
+	}
+
+	if _, ok := u.area(ctx.DeviceLatitude, ctx.DeviceLongitude); ok {
+		return u.RiskScore, nil
+	}
+
+	return 0, nil
+}
+
+// Explain reports the name of the area containing device GPS.
+// Implements ExplainableRule interface.
+func (u *UninhabitableRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	area, ok := u.area(ctx.DeviceLatitude, ctx.DeviceLongitude)
+	if !ok {
+		return nil
+	}
+	return map[string]any{"area": area.Name}
+}
+
+// area returns the first of Areas containing the coordinates.
+func (u *UninhabitableRule) area(lat, lon float64) (GeoBox, bool) {
+	for _, area := range u.Areas {
+		if area.Contains(lat, lon) {
+			return area, true
+		}
+	}
+	return GeoBox{}, false
+}
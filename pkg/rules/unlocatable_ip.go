@@ -0,0 +1,74 @@
+package rules
+
+import (
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// UnlocatableIPRule flags logins whose IP GeoIP could not place anywhere.
+//
+// When an IP has no country, no city, and no coordinates, every location
+// rule passes for lack of evidence, so a garbage or unknown IP looks exactly
+// like a clean domestic login. Being unlocatable is itself a weak signal:
+// unallocated or reserved space, spoofed header values, or networks too new
+// for the database.
+//
+// Behavior:
+//   - Triggers when the record has no CountryCode and no CityGeonameID, and
+//     GeoContext has no IP coordinates
+//   - IPs located only to the country level do not trigger
+//   - Private IPs trigger as well; PrivateIPRule scores them specifically,
+//     so keep this score low when both are registered
+//
+// Requirements:
+//   - GeoIP: implements EphemeralGeoRule so it is skipped in no-geo mode,
+//     where every login would otherwise look unlocatable
+//
+// Tip: RiskResult.GeoLookupFailed additionally reports lookup errors.
+type UnlocatableIPRule struct {
+	RiskScore int // Points to add when the IP cannot be located
+}
+
+// NewUnlocatableIPRule creates a new unlocatable IP detection rule.
+func NewUnlocatableIPRule(score int) *UnlocatableIPRule {
+	return &UnlocatableIPRule{RiskScore: score}
+}
+
+func (u *UnlocatableIPRule) Name() string {
+	return "Unlocatable IP"
+}
+
+func (u *UnlocatableIPRule) Description() string {
+	return "Flags IPs that GeoIP cannot place in any country or city."
+}
+
+func (u *UnlocatableIPRule) Category() string {
+	return models.CategoryNetwork
+}
+
+// Validate satisfies the Rule interface.
+// Returns 0 because this rule must not run without GeoIP; see ValidateWithGeo.
+func (u *UnlocatableIPRule) Validate(input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	return 0, nil
+}
+
+// ValidateWithGeo checks whether GeoIP returned any location for the IP.
+// Implements EphemeralGeoRule interface.
+func (u *UnlocatableIPRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (int, error) {
+	if input.CountryCode != "" || input.CityGeonameID != 0 {
+		return 0, nil
+	}
+	if ctx.IPLatitude != 0 || ctx.IPLongitude != 0 {
+		return 0, nil
+	}
+
+	return u.RiskScore, nil
+}
+
+// Explain reports the network of the unlocatable IP.
+// Implements ExplainableRule interface.
+func (u *UnlocatableIPRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	return map[string]any{
+		"asn": input.ASN,
+		"org": input.OrgName,
+	}
+}
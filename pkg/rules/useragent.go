@@ -0,0 +1,116 @@
+package rules
+
+import "strings"
+
+// userAgentClass is a coarse classification of a User-Agent string.
+type userAgentClass int
+
+const (
+	userAgentEmpty   userAgentClass = iota // No User-Agent header
+	userAgentOther                         // Unrecognized client
+	userAgentBrowser                       // Looks like a mainstream browser
+	userAgentBot                           // Known automation tool or library
+)
+
+// defaultBotSignatures lists lowercase substrings identifying automation tools.
+var defaultBotSignatures = []string{
+	"curl/",
+	"wget/",
+	"python-requests",
+	"python-urllib",
+	"aiohttp",
+	"go-http-client",
+	"okhttp",
+	"java/",
+	"libwww-perl",
+	"httpclient",
+	"headlesschrome",
+	"phantomjs",
+	"selenium",
+	"puppeteer",
+	"playwright",
+}
+
+// browserTokens lists lowercase substrings present in mainstream browser User-Agents.
+var browserTokens = []string{
+	"chrome/",
+	"firefox/",
+	"safari/",
+	"edg/",
+	"opr/",
+	"iphone",
+	"android",
+	"macintosh",
+	"windows nt",
+}
+
+// classifyUserAgent classifies a User-Agent using the given bot signatures.
+// Bot signatures take precedence: "HeadlessChrome" also contains "Chrome/".
+func classifyUserAgent(userAgent string, botSignatures []string) userAgentClass {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return userAgentEmpty
+	}
+
+	for _, signature := range botSignatures {
+		if strings.Contains(ua, strings.ToLower(signature)) {
+			return userAgentBot
+		}
+	}
+
+	if strings.HasPrefix(ua, "mozilla/") {
+		for _, token := range browserTokens {
+			if strings.Contains(ua, token) {
+				return userAgentBrowser
+			}
+		}
+	}
+
+	return userAgentOther
+}
+
+// Coarse OS platform families returned by ParsePlatform.
+const (
+	PlatformWindows  = "windows"
+	PlatformMacOS    = "macos"
+	PlatformIOS      = "ios"
+	PlatformAndroid  = "android"
+	PlatformChromeOS = "chromeos"
+	PlatformLinux    = "linux"
+)
+
+// platformTokens maps lowercase User-Agent substrings to platform families.
+// Order matters: Android UAs contain "Linux", iOS UAs contain "like Mac OS X".
+var platformTokens = []struct {
+	token    string
+	platform string
+}{
+	{"windows", PlatformWindows},
+	{"iphone", PlatformIOS},
+	{"ipad", PlatformIOS},
+	{"ipod", PlatformIOS},
+	{"android", PlatformAndroid},
+	{"cros", PlatformChromeOS},
+	{"macintosh", PlatformMacOS},
+	{"mac os x", PlatformMacOS},
+	{"linux", PlatformLinux},
+}
+
+// ParsePlatform extracts the coarse OS family from a User-Agent string.
+//
+// Only the platform family is returned (no versions, no device models), so
+// the result is low-entropy and safe to persist in LoginRecord.Platform.
+// Returns an empty string when the platform cannot be determined.
+//
+// Limitations:
+//   - iPadOS in desktop mode reports "Macintosh" and is classified as macOS
+//   - The User-Agent is client-controlled and trivially spoofed
+func ParsePlatform(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	for _, entry := range platformTokens {
+		if strings.Contains(ua, entry.token) {
+			return entry.platform
+		}
+	}
+	return ""
+}
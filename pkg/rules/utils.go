@@ -3,24 +3,20 @@ package rules
 import (
 	"math"
 	"net"
+	"strings"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/internal/ipaddr"
 )
 
+// roundTenth rounds v to one decimal place for human-readable details.
+func roundTenth(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
 // haversine calculates the great-circle distance between two coordinates in kilometers.
-// Uses the Haversine formula for accurate distance calculation on a sphere.
+// It is kept for the built-in rules and delegates to DistanceKm.
 func haversine(lat1, lon1, lat2, lon2 float64) float64 {
-	const earthRadiusKm = 6371.0
-
-	dLat := (lat2 - lat1) * (math.Pi / 180.0)
-	dLon := (lon2 - lon1) * (math.Pi / 180.0)
-
-	lat1 = lat1 * (math.Pi / 180.0)
-	lat2 = lat2 * (math.Pi / 180.0)
-
-	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
-		math.Sin(dLon/2)*math.Sin(dLon/2)*math.Cos(lat1)*math.Cos(lat2)
-	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
-
-	return earthRadiusKm * c
+	return DistanceKm(lat1, lon1, lat2, lon2)
 }
 
 // MaskIP anonymizes an IP address for GDPR/KVKK compliance.
@@ -33,14 +29,20 @@ func haversine(lat1, lon1, lat2, lon2 float64) float64 {
 // provides enough granularity for security analysis (network-level)
 // while protecting individual user privacy.
 //
+// The input is normalized first (as by geoip.NormalizeIP), so IPv4-mapped,
+// bracketed, and zoned forms of an address produce the same prefix.
+// Returns "" for unparseable input.
+//
 // Examples:
 //   - "192.168.1.55" -> "192.168.1.0/24"
+//   - "::ffff:192.168.1.55" -> "192.168.1.0/24"
 //   - "2001:db8::1" -> "2001:db8::/64"
 func MaskIP(ipStr string) string {
-	ip := net.ParseIP(ipStr)
-	if ip == nil {
+	normalized, err := ipaddr.Normalize(ipStr)
+	if err != nil {
 		return ""
 	}
+	ip := net.ParseIP(normalized)
 
 	// IPv4: Mask to /24 subnet (last 8 bits hidden)
 	if ipv4 := ip.To4(); ipv4 != nil {
@@ -55,4 +57,31 @@ func MaskIP(ipStr string) string {
 	}
 
 	return ""
-}
\ No newline at end of file
+}
+
+// canonicalPrefix converts a blocklist entry (single IP or CIDR) into the
+// exact key format MaskIP produces, so feed entries match stored
+// MaskedIPPrefix values byte for byte.
+//
+//   - Single IPs are masked with MaskIP
+//   - CIDRs at or narrower than the masking granularity (/24 IPv4, /64 IPv6)
+//     map to their containing masked prefix, e.g. "2001:DB8:0:0::/64" and
+//     "2001:db8::1/128" both become "2001:db8::/64"
+//   - Wider CIDRs (e.g. /16 or /48) can never equal a masked prefix and
+//     return ""; list their /24 or /64 subnets individually instead
+func canonicalPrefix(entry string) string {
+	if !strings.Contains(entry, "/") {
+		return MaskIP(entry)
+	}
+
+	ip, network, err := net.ParseCIDR(entry)
+	if err != nil {
+		return ""
+	}
+
+	ones, bits := network.Mask.Size()
+	if (bits == 32 && ones < 24) || (bits == 128 && ones < 64) {
+		return ""
+	}
+	return MaskIP(ip.String())
+}
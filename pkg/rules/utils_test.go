@@ -0,0 +1,58 @@
+package rules
+
+import "testing"
+
+func TestMaskIP(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"192.168.1.55", "192.168.1.0/24"},
+		{"::ffff:192.168.1.55", "192.168.1.0/24"},
+		{"[::ffff:192.168.1.55]", "192.168.1.0/24"},
+		{"192.168.1.55:8080", "192.168.1.0/24"},
+		{"2001:db8::1", "2001:db8::/64"},
+		{"2001:DB8:0:0:1234:5678:9abc:def0", "2001:db8::/64"},
+		{"[2001:db8::1]", "2001:db8::/64"},
+		{"[2001:db8::1]:443", "2001:db8::/64"},
+		{"fe80::1%eth0", "fe80::/64"},
+		{"", ""},
+		{"not-an-ip", ""},
+		{"192.168.1.0/24", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := MaskIP(tt.in); got != tt.want {
+				t.Errorf("MaskIP(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalPrefix(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"185.193.17.42", "185.193.17.0/24"},
+		{"185.193.17.0/24", "185.193.17.0/24"},
+		{"185.193.17.128/25", "185.193.17.0/24"},
+		{"185.193.17.42/32", "185.193.17.0/24"},
+		{"185.193.0.0/16", ""},
+		{"2001:db8::1", "2001:db8::/64"},
+		{"2001:DB8:0:0::/64", "2001:db8::/64"},
+		{"2001:db8::1/128", "2001:db8::/64"},
+		{"2001:db8::/48", ""},
+		{"::ffff:185.193.17.0/120", "185.193.17.0/24"},
+		{"185.193.17.0/33", ""},
+		{"not-a-cidr/24", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := canonicalPrefix(tt.in); got != tt.want {
+				t.Errorf("canonicalPrefix(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
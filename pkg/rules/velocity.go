@@ -30,9 +30,22 @@ import (
 //   - Uses city centroids, not exact locations (heuristic approach)
 //   - May have false positives for VPN users switching servers
 //   - Thresholds should not be overly aggressive to reduce false positives
+//
+// Cellular Connections:
+// Mobile carriers route traffic through regional gateways, so a phone's IP
+// can appear to jump between cities without the user moving. When
+// CellularMaxSpeedKmh is set, it replaces MaxSpeedKmh whenever the current
+// or previous login is models.ConnectionTypeCellular. Connection
+// types require a GeoIP2 Enterprise or Connection-Type database
+// (geoip.Service.OpenConnectionTypeDB); with GeoLite2 the field is empty
+// and MaxSpeedKmh always applies.
+//
+//	rule := rules.Velocity(900, 80)
+//	rule.CellularMaxSpeedKmh = 3000 // Tolerate gateway hops on mobile
 type VelocityRule struct {
-	MaxSpeedKmh float64 // Maximum allowed speed (e.g., 900 km/h for aircraft)
-	RiskScore   int     // Points to add when rule triggers
+	MaxSpeedKmh         float64 // Maximum allowed speed (e.g., 900 km/h for aircraft)
+	CellularMaxSpeedKmh float64 // Maximum speed when either login is cellular (0 = MaxSpeedKmh)
+	RiskScore           int     // Points to add when rule triggers
 }
 
 // Velocity creates a new velocity/impossible travel detection rule.
@@ -55,6 +68,15 @@ func (v *VelocityRule) Description() string {
 	return fmt.Sprintf("Checks if travel speed between logins exceeds %.0f km/h.", v.MaxSpeedKmh)
 }
 
+func (v *VelocityRule) Category() string {
+	return models.CategoryLocation
+}
+
+// Stateful reports that this rule requires historical login data.
+func (v *VelocityRule) Stateful() bool {
+	return true
+}
+
 // Validate satisfies the Rule interface.
 // Returns 0 because this rule requires ephemeral coordinates via ValidateWithGeo.
 func (v *VelocityRule) Validate(input models.LoginRecord, last *models.LoginRecord) (int, error) {
@@ -80,11 +102,7 @@ func (v *VelocityRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord,
 		return 0, nil
 	}
 
-	// Calculate distance between city centroids (heuristic)
-	distance := haversine(ctx.IPLatitude, ctx.IPLongitude, ctx.PreviousIPLatitude, ctx.PreviousIPLongitude)
-
-	// Time elapsed in hours
-	duration := input.Timestamp.Sub(lastRecord.Timestamp).Hours()
+	distance, duration := v.measure(ctx, input, lastRecord)
 
 	// Handle edge case: near-simultaneous logins from different locations
 	if duration <= 0 {
@@ -96,9 +114,53 @@ func (v *VelocityRule) ValidateWithGeo(ctx GeoContext, input models.LoginRecord,
 
 	speed := distance / duration
 
-	if speed > v.MaxSpeedKmh {
+	if speed > v.maxSpeed(input, lastRecord) {
 		return v.RiskScore, nil
 	}
 
 	return 0, nil
-}
\ No newline at end of file
+}
+
+// Explain reports the distance, elapsed time, and implied speed.
+// Implements ExplainableRule interface.
+func (v *VelocityRule) Explain(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) map[string]any {
+	if lastRecord == nil {
+		return nil
+	}
+
+	distance, duration := v.measure(ctx, input, lastRecord)
+	details := map[string]any{
+		"distance_km":     roundTenth(distance),
+		"elapsed_minutes": roundTenth(duration * 60),
+		"max_speed_kmh":   v.maxSpeed(input, lastRecord),
+	}
+	if duration > 0 {
+		details["speed_kmh"] = roundTenth(distance / duration)
+	}
+	if v.cellular(input, lastRecord) {
+		details["cellular"] = true
+	}
+	return details
+}
+
+// maxSpeed returns the speed threshold for this pair of logins.
+func (v *VelocityRule) maxSpeed(input models.LoginRecord, lastRecord *models.LoginRecord) float64 {
+	if v.CellularMaxSpeedKmh > 0 && v.cellular(input, lastRecord) {
+		return v.CellularMaxSpeedKmh
+	}
+	return v.MaxSpeedKmh
+}
+
+// cellular reports whether either login came from a mobile carrier network.
+func (v *VelocityRule) cellular(input models.LoginRecord, lastRecord *models.LoginRecord) bool {
+	return input.ConnectionType == models.ConnectionTypeCellular ||
+		(lastRecord != nil && lastRecord.ConnectionType == models.ConnectionTypeCellular)
+}
+
+// measure returns the distance in km between the current and previous city
+// centroids (heuristic) and the time elapsed in hours.
+func (v *VelocityRule) measure(ctx GeoContext, input models.LoginRecord, lastRecord *models.LoginRecord) (distance, hours float64) {
+	distance = haversine(ctx.IPLatitude, ctx.IPLongitude, ctx.PreviousIPLatitude, ctx.PreviousIPLongitude)
+	hours = input.Timestamp.Sub(lastRecord.Timestamp).Hours()
+	return distance, hours
+}
@@ -1,6 +1,35 @@
 package storage
 
-import "github.com/gokaycavdar/go-geoguard/pkg/models"
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// ErrUnsupported is returned by store wrappers (see WithMetrics) for optional
+// operations the wrapped store does not implement.
+var ErrUnsupported = errors.New("operation not supported by history store")
+
+// Wrapper is implemented by stores that decorate another store (see
+// WithMetrics). Wrappers implement every optional interface, so check
+// capabilities on the store returned by Unwrap rather than on the wrapper.
+type Wrapper interface {
+	// Unwrap returns the decorated store.
+	Unwrap() HistoryStore
+}
+
+// Unwrap returns the innermost store beneath any Wrapper layers.
+func Unwrap(store HistoryStore) HistoryStore {
+	for {
+		wrapper, ok := store.(Wrapper)
+		if !ok {
+			return store
+		}
+		store = wrapper.Unwrap()
+	}
+}
 
 // HistoryStore defines the interface for storing and retrieving login history.
 // Implementations can use any backend: in-memory, Redis, PostgreSQL, etc.
@@ -12,6 +41,11 @@ import "github.com/gokaycavdar/go-geoguard/pkg/models"
 //   - Only coarse location identifiers (country, city ID) are persisted
 //
 // The engine handles all privacy transformations before calling these methods.
+//
+// Schema Versioning:
+// Backends that serialize records (Redis, PostgreSQL, etc.) must persist
+// LoginRecord.SchemaVersion and call Migrate on every decoded record, so
+// records written by older releases upgrade gracefully.
 type HistoryStore interface {
 	// GetLastRecord retrieves the most recent login record for a user.
 	// Returns nil, nil if no previous record exists (first-time user).
@@ -20,4 +54,126 @@ type HistoryStore interface {
 	// SaveRecord persists a new login record.
 	// The record is already privacy-safe when passed to this method.
 	SaveRecord(record *models.LoginRecord) error
-}
\ No newline at end of file
+}
+
+// RecentHistoryStore is an optional interface for stores that retain more than
+// the latest record per user.
+//
+// It enables rules and engine options that reason over a user's recent logins
+// (baseline selection, frequency, churn). Callers detect support via type
+// assertion and fall back to GetLastRecord when it is absent.
+//
+// It is kept separate from HistoryStore so existing single-record backends
+// keep compiling. Stores implementing both should keep GetLastRecord
+// equivalent to the first element of GetRecentRecords(userID, 1).
+type RecentHistoryStore interface {
+	HistoryStore
+
+	// GetRecentRecords returns up to n of the user's most recent records,
+	// ordered most recent first. Returns an empty slice for unknown users.
+	GetRecentRecords(userID string, n int) ([]*models.LoginRecord, error)
+}
+
+// IterableStore is an optional interface for stores that can enumerate their history.
+//
+// It enables offline analysis such as engine.Backtest. Stores that cannot
+// enumerate efficiently (e.g., key-value backends without scans) may omit it;
+// callers detect support via type assertion.
+type IterableStore interface {
+	HistoryStore
+
+	// Iterate calls fn for every stored record, in chronological order per user.
+	// Iteration stops early when fn returns false.
+	// Records passed to fn are copies and may be retained by the caller.
+	Iterate(fn func(record *models.LoginRecord) bool) error
+}
+
+// UserRenamer is an optional interface for stores that can reassign history
+// from one user ID to another (account merges, user ID changes).
+type UserRenamer interface {
+	HistoryStore
+
+	// RenameUser moves all of oldID's records to newID.
+	//
+	// Semantics:
+	//   - Records are rewritten with UserID = newID
+	//   - If newID already has history, both histories are merged in
+	//     chronological order (concatenate, then sort by Timestamp); stores
+	//     with bounded history keep the newest records
+	//   - Renaming an unknown oldID is a no-op and returns nil
+	RenameUser(oldID, newID string) error
+}
+
+// UserDeleter is an optional interface for stores that can erase a user's
+// history, e.g. to honor GDPR/KVKK right-to-erasure requests.
+type UserDeleter interface {
+	HistoryStore
+
+	// DeleteUser removes every stored record for userID.
+	//
+	// Semantics:
+	//   - The deletion must be durable before DeleteUser returns: records
+	//     must not reappear after a restart, failover, or cache refill
+	//   - Deleting an unknown userID is a no-op and returns nil
+	DeleteUser(userID string) error
+}
+
+// PrefixIndexStore is an optional interface for stores that index history by
+// masked IP prefix as well as by user.
+//
+// It enables cross-account rules such as rules.SharedIPRule: credential
+// stuffing shows many distinct users logging in from one /24 within minutes,
+// which no per-user lookup can reveal.
+//
+// Memory Trade-offs:
+// The index costs one entry per distinct (prefix, user) pair. Backends should
+// derive it from the history they already retain (as MemoryStore does) or
+// expire entries by age; an unbounded index grows with every prefix ever seen.
+type PrefixIndexStore interface {
+	HistoryStore
+
+	// CountUsersByPrefix returns the number of distinct users with at least
+	// one record from prefix timestamped within [now-window, now]. now is the
+	// caller's clock (the login timestamp), not the store's, so counts stay
+	// correct under engine.WithClock and when replaying history.
+	// Returns 0 for an empty or unknown prefix.
+	CountUsersByPrefix(prefix string, now time.Time, window time.Duration) (int, error)
+}
+
+// ContextHistoryStore is an optional interface for stores whose lookups can block
+// (remote databases, caches) and should honor request deadlines.
+//
+// GeoGuard.ValidateContext uses it for the last-record lookup; stores without
+// it are called through GetLastRecord after the context is checked. Stores
+// that also keep recent history should implement ContextRecentHistoryStore.
+type ContextHistoryStore interface {
+	HistoryStore
+
+	// GetLastRecordContext is GetLastRecord bounded by ctx.
+	// It should return ctx.Err() promptly once ctx is done.
+	GetLastRecordContext(ctx context.Context, userID string) (*models.LoginRecord, error)
+}
+
+// DiscardingStore is an optional interface for stores that may retain
+// nothing (see NopStore). GeoGuard.Check uses it to warn that stateful rules
+// can never trigger.
+type DiscardingStore interface {
+	HistoryStore
+
+	// DiscardsRecords reports whether saved records are dropped.
+	DiscardsRecords() bool
+}
+
+// ContextRecentHistoryStore is an optional interface for recent-history
+// stores whose reads should honor request deadlines.
+//
+// GeoGuard.ValidateContext uses it for the baseline selector and HistoryRule
+// inputs; RecentHistoryStores without it are called through GetRecentRecords
+// after the context is checked.
+type ContextRecentHistoryStore interface {
+	RecentHistoryStore
+
+	// GetRecentRecordsContext is GetRecentRecords bounded by ctx.
+	// It should return ctx.Err() promptly once ctx is done.
+	GetRecentRecordsContext(ctx context.Context, userID string, n int) ([]*models.LoginRecord, error)
+}
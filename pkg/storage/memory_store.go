@@ -2,11 +2,16 @@ package storage
 
 import (
 	"errors"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/gokaycavdar/go-geoguard/pkg/models"
 )
 
+// DefaultHistorySize is the number of records MemoryStore retains per user.
+const DefaultHistorySize = 10
+
 // MemoryStore is a thread-safe in-memory implementation of HistoryStore.
 // Suitable for testing, development, and single-instance deployments.
 //
@@ -19,33 +24,75 @@ import (
 //   - CountryCode, CityGeonameID (not coordinates)
 //
 // All privacy transformations are handled by the engine layer.
+//
+// Prefix Index:
+// MemoryStore implements PrefixIndexStore with a prefix -> user -> latest
+// timestamp index derived from the retained history. It holds at most one
+// entry per retained record (historySize per user), so it adds no unbounded
+// growth, but it only sees as far back as each user's retained records: a
+// user whose login from a prefix has been evicted is no longer counted.
 type MemoryStore struct {
-	data map[string]*models.LoginRecord // Key: UserID
-	mu   sync.RWMutex                   // Protects concurrent access
+	data        map[string][]*models.LoginRecord // Key: UserID, oldest first
+	prefixes    map[string]map[string]time.Time  // Key: MaskedIPPrefix, then UserID -> latest Timestamp
+	historySize int                              // Maximum records retained per user
+	mu          sync.RWMutex                     // Protects concurrent access
 }
 
 // NewMemoryStore creates a new in-memory history store.
+// It retains the DefaultHistorySize most recent records per user.
 func NewMemoryStore() *MemoryStore {
+	return NewMemoryStoreWithHistory(DefaultHistorySize)
+}
+
+// NewMemoryStoreWithHistory creates an in-memory store retaining up to
+// historySize records per user. Values below 1 are treated as 1.
+func NewMemoryStoreWithHistory(historySize int) *MemoryStore {
+	if historySize < 1 {
+		historySize = 1
+	}
 	return &MemoryStore{
-		data: make(map[string]*models.LoginRecord),
+		data:        make(map[string][]*models.LoginRecord),
+		prefixes:    make(map[string]map[string]time.Time),
+		historySize: historySize,
 	}
 }
 
 // GetLastRecord retrieves the most recent login record for a user.
 // Returns nil, nil if no previous record exists.
+// It is GetRecentRecords with n = 1.
 func (m *MemoryStore) GetLastRecord(userID string) (*models.LoginRecord, error) {
+	recent, err := m.GetRecentRecords(userID, 1)
+	if err != nil || len(recent) == 0 {
+		return nil, err
+	}
+	return recent[0], nil
+}
+
+// GetRecentRecords returns up to n of the user's most recent records,
+// most recent first. Implements RecentHistoryStore interface.
+func (m *MemoryStore) GetRecentRecords(userID string, n int) ([]*models.LoginRecord, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	if record, exists := m.data[userID]; exists {
-		return record, nil
+	history := m.data[userID]
+	if n < 0 {
+		n = 0
+	}
+	if n > len(history) {
+		n = len(history)
+	}
+
+	recent := make([]*models.LoginRecord, 0, n)
+	for i := len(history) - 1; i >= len(history)-n; i-- {
+		recent = append(recent, migratedCopy(history[i]))
 	}
 
-	return nil, nil
+	return recent, nil
 }
 
 // SaveRecord stores a new login record.
 // The record is copied to prevent external mutations.
+// The oldest record is evicted once the per-user history is full.
 func (m *MemoryStore) SaveRecord(record *models.LoginRecord) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -56,6 +103,157 @@ func (m *MemoryStore) SaveRecord(record *models.LoginRecord) error {
 
 	// Copy the record to prevent external mutations
 	recordToSave := *record
-	m.data[record.UserID] = &recordToSave
+	history := append(m.data[record.UserID], &recordToSave)
+	if len(history) > m.historySize {
+		history = history[len(history)-m.historySize:]
+	}
+	m.unindexUser(record.UserID)
+	m.data[record.UserID] = history
+	m.indexUser(record.UserID)
+	return nil
+}
+
+// RenameUser moves oldID's history to newID, merging chronologically with any
+// existing newID history and keeping the newest records. Implements UserRenamer.
+func (m *MemoryStore) RenameUser(oldID, newID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	oldHistory, exists := m.data[oldID]
+	if !exists || oldID == newID {
+		return nil
+	}
+
+	merged := make([]*models.LoginRecord, 0, len(oldHistory)+len(m.data[newID]))
+	for _, record := range oldHistory {
+		renamed := *record
+		renamed.UserID = newID
+		merged = append(merged, &renamed)
+	}
+	merged = append(merged, m.data[newID]...)
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Timestamp.Before(merged[j].Timestamp)
+	})
+	if len(merged) > m.historySize {
+		merged = merged[len(merged)-m.historySize:]
+	}
+
+	m.unindexUser(oldID)
+	m.unindexUser(newID)
+	m.data[newID] = merged
+	delete(m.data, oldID)
+	m.indexUser(newID)
+	return nil
+}
+
+// DeleteUser removes all of userID's records. Implements UserDeleter interface.
+func (m *MemoryStore) DeleteUser(userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.unindexUser(userID)
+	delete(m.data, userID)
 	return nil
-}
\ No newline at end of file
+}
+
+// CountUsersByPrefix returns the number of distinct users with a retained
+// record from prefix within [now-window, now]. Implements PrefixIndexStore.
+func (m *MemoryStore) CountUsersByPrefix(prefix string, now time.Time, window time.Duration) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if prefix == "" {
+		return 0, nil
+	}
+
+	cutoff := now.Add(-window)
+	count := 0
+	for userID, latest := range m.prefixes[prefix] {
+		if latest.Before(cutoff) {
+			continue
+		}
+		// The index holds the latest login; when it is after now (replayed
+		// history), look for an earlier one inside the window
+		if latest.After(now) && !m.hasRecordBetween(userID, prefix, cutoff, now) {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// hasRecordBetween reports whether userID has a retained record from prefix
+// within [from, to]. Callers must hold the lock.
+func (m *MemoryStore) hasRecordBetween(userID, prefix string, from, to time.Time) bool {
+	for _, record := range m.data[userID] {
+		if record.MaskedIPPrefix == prefix && !record.Timestamp.Before(from) && !record.Timestamp.After(to) {
+			return true
+		}
+	}
+	return false
+}
+
+// indexUser adds userID's retained records to the prefix index.
+// Callers must hold the write lock.
+func (m *MemoryStore) indexUser(userID string) {
+	for _, record := range m.data[userID] {
+		if record.MaskedIPPrefix == "" {
+			continue
+		}
+		users := m.prefixes[record.MaskedIPPrefix]
+		if users == nil {
+			users = make(map[string]time.Time)
+			m.prefixes[record.MaskedIPPrefix] = users
+		}
+		if record.Timestamp.After(users[userID]) {
+			users[userID] = record.Timestamp
+		}
+	}
+}
+
+// unindexUser removes userID's retained records from the prefix index.
+// Callers must hold the write lock.
+func (m *MemoryStore) unindexUser(userID string) {
+	for _, record := range m.data[userID] {
+		users := m.prefixes[record.MaskedIPPrefix]
+		delete(users, userID)
+		if len(users) == 0 {
+			delete(m.prefixes, record.MaskedIPPrefix)
+		}
+	}
+}
+
+// Iterate calls fn for every stored record, ordered by UserID and
+// chronologically within each user. Implements IterableStore interface.
+func (m *MemoryStore) Iterate(fn func(record *models.LoginRecord) bool) error {
+	m.mu.RLock()
+	userIDs := make([]string, 0, len(m.data))
+	for userID := range m.data {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+	records := make([]*models.LoginRecord, 0, len(userIDs))
+	for _, userID := range userIDs {
+		for _, record := range m.data[userID] {
+			records = append(records, migratedCopy(record))
+		}
+	}
+	m.mu.RUnlock()
+
+	// Callback runs without the lock so fn may call back into the store
+	for _, record := range records {
+		if !fn(record) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// migratedCopy returns a copy of a stored record upgraded to the current schema.
+// Copies prevent callers from mutating stored history.
+func migratedCopy(record *models.LoginRecord) *models.LoginRecord {
+	upgraded := *record
+	upgraded.Migrate()
+	return &upgraded
+}
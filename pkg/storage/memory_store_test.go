@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+func TestMemoryStoreCountUsersByPrefixUsesCallerClock(t *testing.T) {
+	store := NewMemoryStore()
+	prefix := "81.2.69.0/24"
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	logins := []struct {
+		user string
+		at   time.Duration
+	}{
+		{"alice", 0},
+		{"bob", 5 * time.Minute},
+		{"carol", 20 * time.Minute},
+		{"dave", 2 * time.Hour}, // After the evaluated login
+		{"erin", 3 * time.Hour},
+		{"erin", 8 * time.Minute}, // erin's latest login is later, but this one is in the window
+	}
+	for _, login := range logins {
+		record := &models.LoginRecord{UserID: login.user, MaskedIPPrefix: prefix, Timestamp: base.Add(login.at)}
+		if err := store.SaveRecord(record); err != nil {
+			t.Fatalf("SaveRecord: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		now    time.Duration
+		window time.Duration
+		want   int
+	}{
+		{"replayed login sees only earlier users", 10 * time.Minute, 15 * time.Minute, 3}, // alice, bob, erin
+		{"narrow window", 10 * time.Minute, 3 * time.Minute, 1},                           // erin
+		{"later login", 3 * time.Hour, 90 * time.Minute, 2},                               // dave, erin
+		{"before any login", -time.Hour, 10 * time.Minute, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.CountUsersByPrefix(prefix, base.Add(tt.now), tt.window)
+			if err != nil {
+				t.Fatalf("CountUsersByPrefix: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("count = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if got, _ := store.CountUsersByPrefix("", base, time.Hour); got != 0 {
+		t.Errorf("count for empty prefix = %d, want 0", got)
+	}
+}
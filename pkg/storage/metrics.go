@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// Store operation names reported to StoreMetrics.
+const (
+	OpGetLastRecord      = "get_last_record"
+	OpSaveRecord         = "save_record"
+	OpGetRecentRecords   = "get_recent_records"
+	OpIterate            = "iterate"
+	OpRenameUser         = "rename_user"
+	OpDeleteUser         = "delete_user"
+	OpCountUsersByPrefix = "count_users_by_prefix"
+)
+
+// StoreMetrics receives observations from a store wrapped with WithMetrics.
+//
+// The interface is deliberately small so it can be backed by Prometheus,
+// OpenTelemetry, expvar, or plain logging without GeoGuard depending on any
+// of them. Implementations must be safe for concurrent use.
+type StoreMetrics interface {
+	// ObserveOperation records one store call: its name (Op* constant),
+	// its latency, and the error it returned (nil on success).
+	ObserveOperation(op string, duration time.Duration, err error)
+
+	// ObserveLookup records whether a successful GetLastRecord found a record.
+	ObserveLookup(hit bool)
+}
+
+// WithMetrics wraps a store so every operation is reported to m.
+//
+// The returned store implements every optional interface (RecentHistoryStore,
+// IterableStore, UserRenamer, UserDeleter, PrefixIndexStore,
+// ContextHistoryStore, ContextRecentHistoryStore, and DiscardingStore) and
+// forwards each call to inner. Calls inner does not support return
+// ErrUnsupported without being reported, except the context variants, which
+// fall back to the plain methods after checking ctx.
+// Use Unwrap to inspect the capabilities of the underlying store.
+//
+// Example:
+//
+//	store := storage.WithMetrics(redisStore, promMetrics)
+//	guard := engine.New(geoService, store)
+func WithMetrics(inner HistoryStore, m StoreMetrics) HistoryStore {
+	return &metricsStore{inner: inner, metrics: m}
+}
+
+// metricsStore instruments a HistoryStore and forwards its optional interfaces.
+type metricsStore struct {
+	inner   HistoryStore
+	metrics StoreMetrics
+}
+
+// Unwrap returns the wrapped store. Implements Wrapper.
+func (s *metricsStore) Unwrap() HistoryStore {
+	return s.inner
+}
+
+// observe reports an operation that started at start.
+func (s *metricsStore) observe(op string, start time.Time, err error) {
+	s.metrics.ObserveOperation(op, time.Since(start), err)
+}
+
+// observeLookup reports a last-record lookup and, on success, whether it hit.
+func (s *metricsStore) observeLookup(start time.Time, record *models.LoginRecord, err error) {
+	s.observe(OpGetLastRecord, start, err)
+	if err == nil {
+		s.metrics.ObserveLookup(record != nil)
+	}
+}
+
+func (s *metricsStore) GetLastRecord(userID string) (*models.LoginRecord, error) {
+	start := time.Now()
+	record, err := s.inner.GetLastRecord(userID)
+	s.observeLookup(start, record, err)
+	return record, err
+}
+
+func (s *metricsStore) GetLastRecordContext(ctx context.Context, userID string) (*models.LoginRecord, error) {
+	contextStore, ok := s.inner.(ContextHistoryStore)
+	if !ok {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return s.GetLastRecord(userID)
+	}
+
+	start := time.Now()
+	record, err := contextStore.GetLastRecordContext(ctx, userID)
+	s.observeLookup(start, record, err)
+	return record, err
+}
+
+func (s *metricsStore) SaveRecord(record *models.LoginRecord) error {
+	start := time.Now()
+	err := s.inner.SaveRecord(record)
+	s.observe(OpSaveRecord, start, err)
+	return err
+}
+
+func (s *metricsStore) GetRecentRecords(userID string, n int) ([]*models.LoginRecord, error) {
+	recent, ok := s.inner.(RecentHistoryStore)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+
+	start := time.Now()
+	records, err := recent.GetRecentRecords(userID, n)
+	s.observe(OpGetRecentRecords, start, err)
+	return records, err
+}
+
+func (s *metricsStore) GetRecentRecordsContext(ctx context.Context, userID string, n int) ([]*models.LoginRecord, error) {
+	contextStore, ok := s.inner.(ContextRecentHistoryStore)
+	if !ok {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return s.GetRecentRecords(userID, n)
+	}
+
+	start := time.Now()
+	records, err := contextStore.GetRecentRecordsContext(ctx, userID, n)
+	s.observe(OpGetRecentRecords, start, err)
+	return records, err
+}
+
+func (s *metricsStore) Iterate(fn func(record *models.LoginRecord) bool) error {
+	iterable, ok := s.inner.(IterableStore)
+	if !ok {
+		return ErrUnsupported
+	}
+
+	start := time.Now()
+	err := iterable.Iterate(fn)
+	s.observe(OpIterate, start, err)
+	return err
+}
+
+func (s *metricsStore) RenameUser(oldID, newID string) error {
+	renamer, ok := s.inner.(UserRenamer)
+	if !ok {
+		return ErrUnsupported
+	}
+
+	start := time.Now()
+	err := renamer.RenameUser(oldID, newID)
+	s.observe(OpRenameUser, start, err)
+	return err
+}
+
+func (s *metricsStore) DeleteUser(userID string) error {
+	deleter, ok := s.inner.(UserDeleter)
+	if !ok {
+		return ErrUnsupported
+	}
+
+	start := time.Now()
+	err := deleter.DeleteUser(userID)
+	s.observe(OpDeleteUser, start, err)
+	return err
+}
+
+func (s *metricsStore) CountUsersByPrefix(prefix string, now time.Time, window time.Duration) (int, error) {
+	index, ok := s.inner.(PrefixIndexStore)
+	if !ok {
+		return 0, ErrUnsupported
+	}
+
+	start := time.Now()
+	count, err := index.CountUsersByPrefix(prefix, now, window)
+	s.observe(OpCountUsersByPrefix, start, err)
+	return count, err
+}
+
+// DiscardsRecords reports whether inner drops saved records; false unless
+// inner implements DiscardingStore.
+func (s *metricsStore) DiscardsRecords() bool {
+	discarding, ok := s.inner.(DiscardingStore)
+	return ok && discarding.DiscardsRecords()
+}
@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// recordingMetrics collects observations for assertions.
+type recordingMetrics struct {
+	mu      sync.Mutex
+	ops     []string
+	errs    []error
+	lookups []bool
+}
+
+func (m *recordingMetrics) ObserveOperation(op string, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ops = append(m.ops, op)
+	m.errs = append(m.errs, err)
+}
+
+func (m *recordingMetrics) ObserveLookup(hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lookups = append(m.lookups, hit)
+}
+
+// baseStore implements only HistoryStore.
+type baseStore struct {
+	err error
+}
+
+func (s *baseStore) GetLastRecord(userID string) (*models.LoginRecord, error) {
+	return nil, s.err
+}
+
+func (s *baseStore) SaveRecord(record *models.LoginRecord) error {
+	return s.err
+}
+
+func TestWithMetricsReportsOperations(t *testing.T) {
+	metrics := &recordingMetrics{}
+	store := WithMetrics(NewMemoryStore(), metrics)
+
+	if _, err := store.GetLastRecord("alice"); err != nil {
+		t.Fatalf("GetLastRecord: %v", err)
+	}
+	record := &models.LoginRecord{UserID: "alice", MaskedIPPrefix: "81.2.69.0/24", Timestamp: time.Now()}
+	if err := store.SaveRecord(record); err != nil {
+		t.Fatalf("SaveRecord: %v", err)
+	}
+	if _, err := store.GetLastRecord("alice"); err != nil {
+		t.Fatalf("GetLastRecord: %v", err)
+	}
+	if _, err := store.(RecentHistoryStore).GetRecentRecords("alice", 5); err != nil {
+		t.Fatalf("GetRecentRecords: %v", err)
+	}
+	if _, err := store.(PrefixIndexStore).CountUsersByPrefix("81.2.69.0/24", time.Now(), time.Hour); err != nil {
+		t.Fatalf("CountUsersByPrefix: %v", err)
+	}
+	if err := store.(IterableStore).Iterate(func(*models.LoginRecord) bool { return true }); err != nil {
+		t.Fatalf("Iterate: %v", err)
+	}
+	if err := store.(UserRenamer).RenameUser("alice", "bob"); err != nil {
+		t.Fatalf("RenameUser: %v", err)
+	}
+	if _, err := store.(ContextHistoryStore).GetLastRecordContext(context.Background(), "bob"); err != nil {
+		t.Fatalf("GetLastRecordContext: %v", err)
+	}
+	if err := store.(UserDeleter).DeleteUser("bob"); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+
+	want := []string{
+		OpGetLastRecord, OpSaveRecord, OpGetLastRecord, OpGetRecentRecords,
+		OpCountUsersByPrefix, OpIterate, OpRenameUser, OpGetLastRecord, OpDeleteUser,
+	}
+	if len(metrics.ops) != len(want) {
+		t.Fatalf("ops = %v, want %v", metrics.ops, want)
+	}
+	for i := range want {
+		if metrics.ops[i] != want[i] {
+			t.Errorf("ops[%d] = %q, want %q", i, metrics.ops[i], want[i])
+		}
+	}
+
+	wantLookups := []bool{false, true, true}
+	if len(metrics.lookups) != len(wantLookups) {
+		t.Fatalf("lookups = %v, want %v", metrics.lookups, wantLookups)
+	}
+	for i := range wantLookups {
+		if metrics.lookups[i] != wantLookups[i] {
+			t.Errorf("lookups[%d] = %v, want %v", i, metrics.lookups[i], wantLookups[i])
+		}
+	}
+}
+
+func TestWithMetricsReportsErrors(t *testing.T) {
+	metrics := &recordingMetrics{}
+	storeErr := errors.New("backend down")
+	store := WithMetrics(&baseStore{err: storeErr}, metrics)
+
+	if _, err := store.GetLastRecord("alice"); !errors.Is(err, storeErr) {
+		t.Fatalf("GetLastRecord error = %v, want %v", err, storeErr)
+	}
+	if len(metrics.errs) != 1 || !errors.Is(metrics.errs[0], storeErr) {
+		t.Errorf("observed errors = %v, want [%v]", metrics.errs, storeErr)
+	}
+	if len(metrics.lookups) != 0 {
+		t.Errorf("failed lookup reported as hit/miss: %v", metrics.lookups)
+	}
+}
+
+func TestWithMetricsUnsupportedOperations(t *testing.T) {
+	metrics := &recordingMetrics{}
+	store := WithMetrics(&baseStore{}, metrics)
+
+	if _, err := store.(RecentHistoryStore).GetRecentRecords("alice", 5); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetRecentRecords error = %v, want ErrUnsupported", err)
+	}
+	if _, err := store.(PrefixIndexStore).CountUsersByPrefix("81.2.69.0/24", time.Now(), time.Hour); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("CountUsersByPrefix error = %v, want ErrUnsupported", err)
+	}
+	if err := store.(IterableStore).Iterate(func(*models.LoginRecord) bool { return true }); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Iterate error = %v, want ErrUnsupported", err)
+	}
+	if err := store.(UserRenamer).RenameUser("alice", "bob"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("RenameUser error = %v, want ErrUnsupported", err)
+	}
+	if err := store.(UserDeleter).DeleteUser("alice"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("DeleteUser error = %v, want ErrUnsupported", err)
+	}
+	if len(metrics.ops) != 0 {
+		t.Errorf("unsupported operations were reported: %v", metrics.ops)
+	}
+
+	// The context variant falls back to GetLastRecord
+	if _, err := store.(ContextHistoryStore).GetLastRecordContext(context.Background(), "alice"); err != nil {
+		t.Fatalf("GetLastRecordContext: %v", err)
+	}
+	if len(metrics.ops) != 1 || metrics.ops[0] != OpGetLastRecord {
+		t.Errorf("ops = %v, want [%s]", metrics.ops, OpGetLastRecord)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := store.(ContextHistoryStore).GetLastRecordContext(ctx, "alice"); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetLastRecordContext on canceled ctx = %v, want context.Canceled", err)
+	}
+}
+
+// contextStore records the contexts passed to its context-aware reads.
+type contextStore struct {
+	*MemoryStore
+	contexts []context.Context
+}
+
+func (s *contextStore) GetLastRecordContext(ctx context.Context, userID string) (*models.LoginRecord, error) {
+	s.contexts = append(s.contexts, ctx)
+	return s.GetLastRecord(userID)
+}
+
+func (s *contextStore) GetRecentRecordsContext(ctx context.Context, userID string, n int) ([]*models.LoginRecord, error) {
+	s.contexts = append(s.contexts, ctx)
+	return s.GetRecentRecords(userID, n)
+}
+
+func TestWithMetricsForwardsContext(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "request")
+	inner := &contextStore{MemoryStore: NewMemoryStore()}
+	metrics := &recordingMetrics{}
+	store := WithMetrics(inner, metrics)
+
+	if _, err := store.(ContextHistoryStore).GetLastRecordContext(ctx, "alice"); err != nil {
+		t.Fatalf("GetLastRecordContext: %v", err)
+	}
+	if _, err := store.(ContextRecentHistoryStore).GetRecentRecordsContext(ctx, "alice", 5); err != nil {
+		t.Fatalf("GetRecentRecordsContext: %v", err)
+	}
+
+	if len(inner.contexts) != 2 {
+		t.Fatalf("inner context calls = %d, want 2", len(inner.contexts))
+	}
+	for i, got := range inner.contexts {
+		if got.Value(key{}) != "request" {
+			t.Errorf("call %d did not receive the caller's context", i)
+		}
+	}
+	if len(metrics.ops) != 2 || metrics.ops[0] != OpGetLastRecord || metrics.ops[1] != OpGetRecentRecords {
+		t.Errorf("ops = %v, want [%s %s]", metrics.ops, OpGetLastRecord, OpGetRecentRecords)
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	inner := NewMemoryStore()
+	wrapped := WithMetrics(WithMetrics(inner, &recordingMetrics{}), &recordingMetrics{})
+
+	if got := Unwrap(wrapped); got != HistoryStore(inner) {
+		t.Errorf("Unwrap = %T, want the inner *MemoryStore", got)
+	}
+	if got := Unwrap(inner); got != HistoryStore(inner) {
+		t.Errorf("Unwrap of an unwrapped store = %T, want it unchanged", got)
+	}
+}
@@ -0,0 +1,35 @@
+package storage
+
+import "github.com/gokaycavdar/go-geoguard/pkg/models"
+
+// NopStore is a HistoryStore that retains nothing.
+//
+// Useful for stateless deployments (e.g., pre-login traffic) where no history
+// should be kept. Stateful rules never trigger with this store; the engine's
+// Check method reports this as a configuration warning.
+type NopStore struct{}
+
+// NewNopStore creates a store that discards all records.
+func NewNopStore() *NopStore {
+	return &NopStore{}
+}
+
+// GetLastRecord always returns nil, nil (every login looks like a first login).
+func (n *NopStore) GetLastRecord(userID string) (*models.LoginRecord, error) {
+	return nil, nil
+}
+
+// SaveRecord discards the record.
+func (n *NopStore) SaveRecord(record *models.LoginRecord) error {
+	return nil
+}
+
+// DeleteUser is a no-op: nothing is ever stored. Implements UserDeleter interface.
+func (n *NopStore) DeleteUser(userID string) error {
+	return nil
+}
+
+// DiscardsRecords always returns true. Implements DiscardingStore interface.
+func (n *NopStore) DiscardsRecords() bool {
+	return true
+}
@@ -0,0 +1,272 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/gokaycavdar/go-geoguard/pkg/models"
+)
+
+// postgresSchema creates the login_records table and its lookup indexes.
+//
+// Only privacy-safe LoginRecord fields exist as columns: there is no column
+// for a raw IP address, raw User-Agent, or IP coordinates. Device coordinates
+// are only non-zero when the engine opts into rounded coordinate storage.
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS login_records (
+	id                  BIGSERIAL PRIMARY KEY,
+	schema_version      INTEGER          NOT NULL DEFAULT 0,
+	user_id             TEXT             NOT NULL,
+	timestamp           TIMESTAMPTZ      NOT NULL,
+	masked_ip_prefix    TEXT             NOT NULL DEFAULT '',
+	country_code        TEXT             NOT NULL DEFAULT '',
+	city_geoname_id     BIGINT           NOT NULL DEFAULT 0,
+	asn                 BIGINT           NOT NULL DEFAULT 0,
+	org_name            TEXT             NOT NULL DEFAULT '',
+	connection_type     TEXT             NOT NULL DEFAULT '',
+	fingerprint_hash    TEXT             NOT NULL DEFAULT '',
+	platform            TEXT             NOT NULL DEFAULT '',
+	ip_timezone         TEXT             NOT NULL DEFAULT '',
+	client_timezone     TEXT             NOT NULL DEFAULT '',
+	outcome             TEXT             NOT NULL DEFAULT '',
+	device_latitude     DOUBLE PRECISION NOT NULL DEFAULT 0,
+	device_longitude    DOUBLE PRECISION NOT NULL DEFAULT 0,
+	smoothed_risk_score DOUBLE PRECISION NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS login_records_user_timestamp_idx
+	ON login_records (user_id, timestamp DESC);
+CREATE INDEX IF NOT EXISTS login_records_prefix_timestamp_idx
+	ON login_records (masked_ip_prefix, timestamp DESC);
+`
+
+// postgresColumns lists the selected columns in scanRecord order.
+const postgresColumns = `schema_version, user_id, timestamp, masked_ip_prefix,
+	country_code, city_geoname_id, asn, org_name, connection_type,
+	fingerprint_hash, platform, ip_timezone, client_timezone, outcome,
+	device_latitude, device_longitude, smoothed_risk_score`
+
+// PostgresStore is a PostgreSQL implementation of HistoryStore.
+//
+// Suitable for multi-instance deployments and for audit or analytics use
+// cases that need the full login history. The store is backed by
+// database/sql; the caller opens the *sql.DB with a PostgreSQL driver of
+// their choice (e.g., pgx or lib/pq), so GeoGuard does not depend on one.
+//
+// Privacy Note:
+// The table only has columns for privacy-safe LoginRecord fields (masked
+// prefix, coarse location, ASN, fingerprint hash, timezones, ...).
+//
+// Unlike MemoryStore, history is unbounded; prune old rows with a scheduled
+// DELETE according to your retention policy.
+//
+// Also implements RecentHistoryStore, IterableStore, UserRenamer,
+// UserDeleter, PrefixIndexStore, ContextHistoryStore, and
+// ContextRecentHistoryStore.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore creates a store using an open PostgreSQL connection pool.
+// Call EnsureSchema once at startup to create the table.
+//
+// Example:
+//
+//	db, err := sql.Open("pgx", os.Getenv("DATABASE_URL"))
+//	store := storage.NewPostgresStore(db)
+//	err = store.EnsureSchema(ctx)
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// EnsureSchema creates the login_records table and index if they do not exist.
+// It is idempotent and safe to call on every startup.
+func (p *PostgresStore) EnsureSchema(ctx context.Context) error {
+	_, err := p.db.ExecContext(ctx, postgresSchema)
+	return err
+}
+
+// GetLastRecord retrieves the most recent login record for a user.
+// Returns nil, nil if no previous record exists.
+func (p *PostgresStore) GetLastRecord(userID string) (*models.LoginRecord, error) {
+	return p.GetLastRecordContext(context.Background(), userID)
+}
+
+// GetLastRecordContext is GetLastRecord bounded by ctx.
+// Implements ContextHistoryStore interface.
+func (p *PostgresStore) GetLastRecordContext(ctx context.Context, userID string) (*models.LoginRecord, error) {
+	row := p.db.QueryRowContext(ctx,
+		`SELECT `+postgresColumns+` FROM login_records
+		WHERE user_id = $1 ORDER BY timestamp DESC, id DESC LIMIT 1`, userID)
+
+	record, err := scanRecord(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// GetRecentRecords returns up to n of the user's most recent records,
+// most recent first. Implements RecentHistoryStore interface.
+func (p *PostgresStore) GetRecentRecords(userID string, n int) ([]*models.LoginRecord, error) {
+	return p.GetRecentRecordsContext(context.Background(), userID, n)
+}
+
+// GetRecentRecordsContext is GetRecentRecords bounded by ctx.
+// Implements ContextRecentHistoryStore interface.
+func (p *PostgresStore) GetRecentRecordsContext(ctx context.Context, userID string, n int) ([]*models.LoginRecord, error) {
+	if n < 1 {
+		return make([]*models.LoginRecord, 0), nil
+	}
+
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT `+postgresColumns+` FROM login_records
+		WHERE user_id = $1 ORDER BY timestamp DESC, id DESC LIMIT $2`, userID, n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := make([]*models.LoginRecord, 0, n)
+	for rows.Next() {
+		record, err := scanRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// SaveRecord persists a new login record.
+func (p *PostgresStore) SaveRecord(record *models.LoginRecord) error {
+	if record == nil {
+		return errors.New("record cannot be nil")
+	}
+
+	_, err := p.db.Exec(
+		`INSERT INTO login_records (`+postgresColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`,
+		record.SchemaVersion,
+		record.UserID,
+		record.Timestamp,
+		record.MaskedIPPrefix,
+		record.CountryCode,
+		int64(record.CityGeonameID),
+		int64(record.ASN),
+		record.OrgName,
+		record.ConnectionType,
+		record.FingerprintHash,
+		record.Platform,
+		record.IPTimezone,
+		record.ClientTimezone,
+		string(record.Outcome),
+		record.DeviceLatitude,
+		record.DeviceLongitude,
+		record.SmoothedRiskScore,
+	)
+	return err
+}
+
+// RenameUser moves all of oldID's records to newID. Histories merge naturally
+// because records are ordered by timestamp. Implements UserRenamer interface.
+func (p *PostgresStore) RenameUser(oldID, newID string) error {
+	if oldID == newID {
+		return nil
+	}
+	_, err := p.db.Exec(`UPDATE login_records SET user_id = $1 WHERE user_id = $2`, newID, oldID)
+	return err
+}
+
+// DeleteUser removes all of userID's rows. The deletion is durable once the
+// statement commits. Implements UserDeleter interface.
+func (p *PostgresStore) DeleteUser(userID string) error {
+	_, err := p.db.Exec(`DELETE FROM login_records WHERE user_id = $1`, userID)
+	return err
+}
+
+// CountUsersByPrefix counts distinct users with a row from prefix within
+// [now-window, now]. Implements PrefixIndexStore interface.
+func (p *PostgresStore) CountUsersByPrefix(prefix string, now time.Time, window time.Duration) (int, error) {
+	if prefix == "" {
+		return 0, nil
+	}
+
+	var count int
+	err := p.db.QueryRow(
+		`SELECT COUNT(DISTINCT user_id) FROM login_records
+		WHERE masked_ip_prefix = $1 AND timestamp >= $2 AND timestamp <= $3`,
+		prefix, now.Add(-window), now).Scan(&count)
+	return count, err
+}
+
+// Iterate calls fn for every stored record, ordered by user_id and
+// chronologically within each user. Implements IterableStore interface.
+func (p *PostgresStore) Iterate(fn func(record *models.LoginRecord) bool) error {
+	rows, err := p.db.Query(
+		`SELECT ` + postgresColumns + ` FROM login_records ORDER BY user_id, timestamp, id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		record, err := scanRecord(rows)
+		if err != nil {
+			return err
+		}
+		if !fn(record) {
+			break
+		}
+	}
+	return rows.Err()
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanRecord decodes a row selected with postgresColumns and migrates it
+// to the current schema.
+func scanRecord(row rowScanner) (*models.LoginRecord, error) {
+	var (
+		record        models.LoginRecord
+		cityGeonameID int64
+		asn           int64
+		outcome       string
+	)
+
+	err := row.Scan(
+		&record.SchemaVersion,
+		&record.UserID,
+		&record.Timestamp,
+		&record.MaskedIPPrefix,
+		&record.CountryCode,
+		&cityGeonameID,
+		&asn,
+		&record.OrgName,
+		&record.ConnectionType,
+		&record.FingerprintHash,
+		&record.Platform,
+		&record.IPTimezone,
+		&record.ClientTimezone,
+		&outcome,
+		&record.DeviceLatitude,
+		&record.DeviceLongitude,
+		&record.SmoothedRiskScore,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	record.CityGeonameID = uint(cityGeonameID)
+	record.ASN = uint(asn)
+	record.Outcome = models.Outcome(outcome)
+	record.Migrate()
+	return &record, nil
+}